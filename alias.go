@@ -0,0 +1,120 @@
+package pathrouter
+
+import (
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// AddAlias registers the alias pattern as an alternative pattern for the route
+// registered with the canonical pattern, for example to keep serving legacy
+// paths.
+//
+// Paths matched by the alias are served by the handle of the canonical route
+// and reported with the canonical pattern: by Lookup, in the context (see
+// RouterConfig.PatternInContext), to the Observer and in the RouteStats. The
+// handle is called with the params of the alias, which must have the same
+// param names as the canonical pattern. The alias follows the changes to the
+// canonical route and is removed with it.
+//
+// Panics if the canonical pattern is not registered or is an alias, or if the
+// alias pattern is invalid, has other params or conflicts with a registered
+// pattern.
+func (r *Router[W]) AddAlias(aliasPattern, canonicalPattern string) {
+	alias, err := newRoute[W](r.conf.UnicodeForm.Normalize(aliasPattern), nil, r.conf.Separator)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	err = r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		if err := r.tryAddAlias(t, alias, canonicalPattern); err != nil {
+			return nil, err
+		}
+		return t, nil
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// tryAddAlias makes the alias route an alias of the route registered with the
+// canonical pattern in the table and inserts it, returning any conflict as an
+// error. Localized aliases must be the only alias of the canonical route for
+// the locale, see AddLocaleAlias.
+func (r *Router[W]) tryAddAlias(t *table[W], alias *route[W], canonicalPattern string) error {
+	canonical, err := r.aliasOf(t, alias, canonicalPattern)
+	if err != nil {
+		return err
+	}
+	if alias.locale != "" {
+		if existing := localeAlias(t, canonical.pattern, alias.locale); existing != nil {
+			return errors.Errorf("canonical pattern '%s' already has alias '%s' for locale '%s'", canonical.pattern, existing.pattern, alias.locale)
+		}
+	}
+	return t.tryAddRoute(alias, r.conf.Fallthrough)
+}
+
+// aliasOf makes the alias route an alias of the route registered with the
+// canonical pattern in the table. Returns the canonical route.
+func (r *Router[W]) aliasOf(t *table[W], alias *route[W], canonicalPattern string) (*route[W], error) {
+	canonical := r.routeByPattern(t, canonicalPattern)
+	if canonical == nil {
+		return nil, errors.Errorf("no route registered for canonical pattern '%s'", canonicalPattern)
+	}
+	if canonical.canonical != "" {
+		return nil, errors.Errorf("canonical pattern '%s' is an alias of '%s'", canonicalPattern, canonical.canonical)
+	}
+	if !slices.Equal(paramNames(alias.parts), paramNames(canonical.parts)) {
+		return nil, errors.Errorf("alias '%s' must have the params of canonical pattern '%s'", alias.pattern, canonical.pattern)
+	}
+	alias.resolve(canonical)
+	return canonical, nil
+}
+
+// resolve copies the handle and the definition of the canonical route to the
+// alias route, including the timeout and the redirect target.
+func (alias *route[W]) resolve(canonical *route[W]) {
+	alias.handle = canonical.handle
+	alias.name, alias.metadata, alias.tags = canonical.name, canonical.metadata, canonical.tags
+	alias.defaults, alias.timeout = canonical.defaults, canonical.timeout
+	alias.redirect, alias.rewrite = canonical.redirect, canonical.rewrite
+	alias.counters, alias.disabled = canonical.counters, canonical.disabled
+	alias.canonical, alias.canonicalRoute = canonical.pattern, canonical
+}
+
+// syncAliases updates the aliases of the canonical routes replaced in the
+// table and removes the aliases of the removed canonical routes. The table
+// must not be published yet.
+func (r *Router[W]) syncAliases(t *table[W]) error {
+	var removed bool
+	for pattern, alias := range t.routes {
+		if alias.canonical == "" {
+			continue
+		}
+		canonical := t.routes[alias.canonical]
+		if canonical == alias.canonicalRoute {
+			continue
+		}
+		if canonical == nil || canonical.canonical != "" {
+			delete(t.routes, pattern)
+			t.removed[pattern] = t.version
+			t.changed = append(t.changed, pattern)
+			removed = true
+			continue
+		}
+		updated := *alias
+		updated.resolve(canonical)
+		t.replaceRoute(alias, &updated)
+	}
+	if !removed {
+		return nil
+	}
+
+	trees, maxParams, err := buildTrees(t.routes, r.conf.Fallthrough)
+	if err != nil {
+		return err
+	}
+	t.trees, t.maxParams, t.static = trees, maxParams, staticRoutes(t.routes)
+	return nil
+}
@@ -0,0 +1,124 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRouterAddAlias(t *testing.T) {
+	obs := &testObserver{}
+	router := NewWithConfig(RouterConfig[struct{}]{Observer: obs, RouteStats: true, PatternInContext: true})
+
+	var calls []string
+	router.AddHandler("/users/:id/profile", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		calls = append(calls, PatternFromContext(ctx)+" "+p.ByName("id"))
+		return true, nil
+	}, WithName("profile"))
+	router.AddAlias("/u/:id", "/users/:id/profile")
+	router.AddAlias("/legacy/profile/{id}", "/users/{id}/profile")
+
+	ctx := context.Background()
+	for _, path := range []string{"/users/1/profile", "/u/2", "/legacy/profile/3"} {
+		if found, err := router.Serve(ctx, path, struct{}{}); !found || err != nil {
+			t.Fatalf("path %s: expected found, got found=%v err=%v", path, found, err)
+		}
+	}
+	want := []string{"/users/:id/profile 1", "/users/:id/profile 2", "/users/:id/profile 3"}
+	if len(calls) != len(want) {
+		t.Fatalf("expected calls %v but got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("expected call %q but got %q", want[i], calls[i])
+		}
+	}
+	if obs.lookups[1] != "/u/2 /users/:id/profile found" {
+		t.Errorf("unexpected observed lookup %q", obs.lookups[1])
+	}
+
+	res := router.Lookup("/u/4")
+	if !res.Found() || res.Pattern != "/users/:id/profile" || res.Name != "profile" || res.Params.ByName("id") != "4" {
+		t.Errorf("unexpected lookup result %+v", res)
+	}
+
+	stats := router.RouteStats()
+	if len(stats) != 1 || stats[0].Pattern != "/users/:id/profile" || stats[0].Matches != 3 || stats[0].Lookups != 1 {
+		t.Errorf("unexpected route stats %+v", stats)
+	}
+	if !router.HasRoute("/u/:id") {
+		t.Error("expected alias pattern to be registered")
+	}
+
+	for _, test := range []struct {
+		alias, canonical string
+	}{
+		{"/p/:id", "/missing"},
+		{"/p/:id", "/u/:id"},
+		{"/u/:id", "/users/:id/profile"},
+		{"/p/:", "/users/:id/profile"},
+		{"/p/:x", "/users/:id/profile"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("alias %s of %s: expected panic", test.alias, test.canonical)
+				}
+			}()
+			router.AddAlias(test.alias, test.canonical)
+		}()
+	}
+}
+
+func TestRouterAddAliasFollowsCanonical(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/users/:id", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, nil
+	})
+	router.AddAlias("/u/:id", "/users/:id")
+
+	ctx := context.Background()
+	router.AppendHandler("/users/:id", fakeHandler("appended"))
+	for _, path := range []string{"/users/1", "/u/1"} {
+		fakeHandlerValue = ""
+		if found, _ := router.Serve(ctx, path, struct{}{}); !found || fakeHandlerValue != "appended" {
+			t.Errorf("path %s: expected the appended handle, got found=%v value=%q", path, found, fakeHandlerValue)
+		}
+	}
+
+	version := router.Version()
+	err := router.ApplyDelta(RouteDelta[struct{}]{FromVersion: version, ToVersion: version + 1, Removed: []string{"/users/:id"}})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if router.HasRoute("/u/:id") {
+		t.Error("expected the alias to be removed with the canonical route")
+	}
+	if found, _ := router.Serve(ctx, "/u/1", struct{}{}); found {
+		t.Error("expected the removed alias not to be served")
+	}
+}
+
+func TestRouterAddAliasTimeoutRedirect(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/new/:id", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return false, errors.New("expected deadline")
+		}
+		return true, nil
+	}, WithTimeout(time.Minute))
+	router.AddAlias("/old/:id", "/new/:id")
+	router.AddRedirect("/moved/:id", "/new/:id")
+	router.AddAlias("/m/:id", "/moved/:id")
+
+	ctx := context.Background()
+	for _, path := range []string{"/new/1", "/old/1", "/m/1"} {
+		if found, err := router.Serve(ctx, path, struct{}{}); !found || err != nil {
+			t.Errorf("path %s: expected found, got found=%v err=%v", path, found, err)
+		}
+	}
+	if res := router.Lookup("/m/2"); !res.Found() || res.RedirectPath != "/new/2" {
+		t.Errorf("expected the alias to redirect, got %+v", res)
+	}
+}
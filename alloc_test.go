@@ -0,0 +1,79 @@
+//go:build !race
+
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+// allocRoutes are the routes used by the allocation tests.
+var allocRoutes = []string{
+	"/",
+	"/static/path",
+	"/user/:name/posts/:id",
+	"/files/*filepath",
+}
+
+// allocPaths are the paths served allocation-free by allocRoutes.
+var allocPaths = []struct {
+	name string
+	path string
+}{
+	{"root", "/"},
+	{"static", "/static/path"},
+	{"params", "/user/gopher/posts/42"},
+	{"catch-all", "/files/a/b/c.txt"},
+	{"not-found", "/missing/path"},
+	{"trailing-slash", "/static/path/"},
+}
+
+// newAllocRouter returns a router with the default config serving allocRoutes.
+func newAllocRouter() *Router[struct{}] {
+	handle := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return true, nil
+	}
+	router := New[struct{}]()
+	for _, pattern := range allocRoutes {
+		router.AddHandler(pattern, handle)
+	}
+	return router
+}
+
+// TestRouterZeroAlloc checks the documented zero-allocation guarantee, see
+// Router.Serve. Not built with the race detector which defeats sync.Pool reuse.
+func TestRouterZeroAlloc(t *testing.T) {
+	router := newAllocRouter()
+	ctx := context.Background()
+	for _, tt := range allocPaths {
+		path, pathBytes := tt.path, []byte(tt.path)
+		ops := map[string]func(){
+			"Serve":      func() { _, _ = router.Serve(ctx, path, struct{}{}) },
+			"ServeBytes": func() { _, _ = router.ServeBytes(ctx, pathBytes, struct{}{}) },
+		}
+		for op, fn := range ops {
+			if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+				t.Errorf("%s %s: expected no allocations, got %v", op, tt.name, allocs)
+			}
+		}
+	}
+
+	for _, path := range []string{"/static/path", "/missing/path"} {
+		if allocs := testing.AllocsPerRun(100, func() { _, _, _ = router.LookupPath(path) }); allocs != 0 {
+			t.Errorf("LookupPath %s: expected no allocations, got %v", path, allocs)
+		}
+	}
+}
+
+func BenchmarkRouterServe(b *testing.B) {
+	router := newAllocRouter()
+	ctx := context.Background()
+	for _, tt := range allocPaths {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = router.Serve(ctx, tt.path, struct{}{})
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package pathrouter
+
+import "strings"
+
+// Analyze reports problems with the route table.
+//
+// In addition to the problems found by SelfCheck, such as unreachable and
+// shadowed routes, reports wildcards with a different name or kind than the
+// wildcard at the same position of an earlier route, which can only be
+// registered with RouterConfig.Fallthrough, and routes registered both with and
+// without a trailing slash, for which the trailing slash redirect never
+// applies.
+//
+// The problems are ordered by route registration order.
+func (r *Router[W]) Analyze() []Problem {
+	t := r.load()
+	routes := sortRoutes(t.routes)
+
+	var problems []Problem
+	wildcards := make(map[string]*route[W])
+	paths := make(map[string]*route[W], len(routes))
+	for _, rt := range routes {
+		paths[rt.path] = rt
+	}
+	for _, rt := range routes {
+		problems = append(problems, r.checkRoute(t, rt)...)
+
+		var shape strings.Builder
+		for _, part := range rt.parts {
+			if part.kind == static {
+				shape.WriteString(part.value)
+				continue
+			}
+			key := shape.String()
+			if first, ok := wildcards[key]; ok {
+				if fpart := wildcardAt(first.parts, key); fpart != part {
+					problems = append(problems, Problem{
+						Pattern: rt.pattern,
+						Message: "wildcard '" + part.String() + "' conflicts with '" + fpart.String() + "' of " + first.pattern,
+					})
+				}
+			} else {
+				wildcards[key] = rt
+			}
+			// params of any name match the same paths, catch-alls are last
+			shape.WriteByte(':')
+		}
+
+		if !rt.anchored && strings.HasSuffix(rt.path, "/") && len(rt.path) > 1 {
+			if other := paths[rt.path[:len(rt.path)-1]]; other != nil && !other.anchored {
+				problems = append(problems, Problem{
+					Pattern: rt.pattern,
+					Message: "also registered without a trailing slash as " + other.pattern + ": the trailing slash redirect never applies",
+				})
+			}
+		}
+	}
+	return problems
+}
+
+// wildcardAt returns the wildcard part following the static and wildcard parts
+// matching the shape key.
+func wildcardAt(parts []patternPart, key string) patternPart {
+	var shape strings.Builder
+	for _, part := range parts {
+		if part.kind == static {
+			shape.WriteString(part.value)
+			continue
+		}
+		if shape.String() == key {
+			return part
+		}
+		shape.WriteByte(':')
+	}
+	return patternPart{}
+}
+
+// String formats the part as in a pattern.
+func (p patternPart) String() string {
+	switch p.kind {
+	case param:
+		return ":" + p.value
+	case catchAll:
+		return "*" + p.value
+	default:
+		return p.value
+	}
+}
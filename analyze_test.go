@@ -0,0 +1,47 @@
+package pathrouter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouterAnalyze(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.Fallthrough = true
+	router := NewWithConfig(conf)
+	for _, pattern := range []string{
+		"/user/:id",
+		"/user/:name/posts",
+		"/user/:id/posts/:post",
+		"/user/:id/posts/*rest",
+		"/files/",
+		"/files",
+		"/dir",
+		"/dir/{$}",
+		"/ok/:a/:b",
+	} {
+		router.AddHandler(pattern, fakeHandler(pattern))
+	}
+
+	var got []string
+	for _, p := range router.Analyze() {
+		got = append(got, p.String())
+	}
+	expected := []string{
+		"/user/:name/posts: wildcard ':name' conflicts with ':id' of /user/:id",
+		"/user/:id/posts/*rest: wildcard '*rest' conflicts with ':post' of /user/:id/posts/:post",
+		"/files/: also registered without a trailing slash as /files: the trailing slash redirect never applies",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected problems:\n%q\nexpected:\n%q", got, expected)
+	}
+}
+
+func TestRouterAnalyzeClean(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/src/*filepath", fakeHandler("src"))
+	router.AddHandler("/user/:name", fakeHandler("user"))
+	if problems := router.Analyze(); len(problems) != 0 {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+}
@@ -0,0 +1,60 @@
+package pathrouter
+
+import "strings"
+
+// arenaTree returns a copy of the tree with the nodes, the child slices and
+// the node strings allocated from contiguous arenas, see CompileArena.
+//
+// The nodes are laid out in depth-first order so a node is followed by its
+// first child. The tree must not be changed afterwards.
+func arenaTree[W any](tree *node[W]) *node[W] {
+	var nodeCount, childCount, strLen int
+	offsets := make(map[string]int)
+	var order []string
+	var count func(n *node[W])
+	count = func(n *node[W]) {
+		nodeCount++
+		childCount += len(n.children)
+		for _, s := range [...]string{n.path, n.indices} {
+			if _, ok := offsets[s]; !ok {
+				offsets[s] = strLen
+				order = append(order, s)
+				strLen += len(s)
+			}
+		}
+		for _, child := range n.children {
+			count(child)
+		}
+	}
+	count(tree)
+
+	var sb strings.Builder
+	sb.Grow(strLen)
+	for _, s := range order {
+		sb.WriteString(s)
+	}
+	strs := sb.String()
+	arenaString := func(s string) string {
+		off := offsets[s]
+		return strs[off : off+len(s)]
+	}
+
+	nodes := make([]node[W], 0, nodeCount)
+	children := make([]*node[W], 0, childCount)
+	var place func(n *node[W]) *node[W]
+	place = func(n *node[W]) *node[W] {
+		nodes = append(nodes, *n)
+		out := &nodes[len(nodes)-1]
+		out.path, out.indices = arenaString(n.path), arenaString(n.indices)
+		if len(n.children) != 0 {
+			start := len(children)
+			children = children[:start+len(n.children)]
+			out.children = children[start:len(children):len(children)]
+			for i, child := range n.children {
+				out.children[i] = place(child)
+			}
+		}
+		return out
+	}
+	return place(tree)
+}
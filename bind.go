@@ -0,0 +1,70 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// TypedHandle is a handle called with the params decoded into a struct, see
+// AddHandlerT.
+type TypedHandle[P, W any] func(ctx context.Context, reqPath string, p P, rw W) (bool, error)
+
+// AddHandlerT registers a new handle with the given pattern on the router
+// decoding the params into the struct P before calling the handle, see
+// Params.Decode. The handle is not called and the error is returned if a param
+// cannot be decoded.
+//
+// Panics if P is not a struct, if a tagged field refers to a param not in the
+// pattern, or if the pattern is invalid or conflicts with a registered pattern.
+func AddHandlerT[P, W any](r *Router[W], pattern string, handle TypedHandle[P, W], opts ...RouteOption) {
+	if handle == nil {
+		return
+	}
+	typ := reflect.TypeOf((*P)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		panic("param type " + typ.String() + " is not a struct")
+	}
+	rt, err := newRoute[W](r.conf.UnicodeForm.Normalize(pattern), nil, r.conf.Separator)
+	if err != nil {
+		panic(err.Error())
+	}
+	for _, name := range paramTagNames(typ) {
+		if !rt.hasWildcard(name) {
+			panic(errors.Errorf("param '%s' of %s is not in path '%s'", name, typ, pattern).Error())
+		}
+	}
+
+	r.AddHandler(pattern, func(ctx context.Context, reqPath string, ps Params, rw W) (bool, error) {
+		var p P
+		if err := ps.Decode(&p); err != nil {
+			return false, err
+		}
+		return handle(ctx, reqPath, p, rw)
+	}, opts...)
+}
+
+// paramTagNames returns the param names of the fields decoded by
+// Params.Decode.
+func paramTagNames(typ reflect.Type) []string {
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, tagged := field.Tag.Lookup(paramTag)
+		if !tagged {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				names = append(names, paramTagNames(field.Type)...)
+			}
+			continue
+		}
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,58 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+type testPageParams struct {
+	Pagination
+	User string `path:"user"`
+	Skip string `path:"-"`
+}
+
+type Pagination struct {
+	Page int `path:"page"`
+}
+
+func TestAddHandlerT(t *testing.T) {
+	router := New[struct{}]()
+	var got testPageParams
+	AddHandlerT(router, "/users/:user/pages/:page", func(ctx context.Context, reqPath string, p testPageParams, rw struct{}) (bool, error) {
+		got = p
+		return true, nil
+	})
+
+	ctx := context.Background()
+	if found, err := router.Serve(ctx, "/users/gopher/pages/3", struct{}{}); !found || err != nil {
+		t.Fatalf("expected found, got found=%v err=%v", found, err)
+	}
+	if got.User != "gopher" || got.Page != 3 {
+		t.Errorf("unexpected params %+v", got)
+	}
+	if found, err := router.Serve(ctx, "/users/gopher/pages/x", struct{}{}); found || err == nil {
+		t.Errorf("expected decode error, got found=%v err=%v", found, err)
+	}
+
+	for _, register := range []func(){
+		func() {
+			AddHandlerT(router, "/users/:name/pages/:page", func(ctx context.Context, reqPath string, p testPageParams, rw struct{}) (bool, error) {
+				return true, nil
+			})
+		},
+		func() {
+			AddHandlerT(router, "/other/:id", func(ctx context.Context, reqPath string, p string, rw struct{}) (bool, error) {
+				return true, nil
+			})
+		},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			register()
+		}()
+	}
+}
@@ -0,0 +1,103 @@
+package pathrouter
+
+import (
+	"regexp"
+	"time"
+)
+
+// RouteBuilder collects the options of a route and registers it, see
+// Router.Route.
+type RouteBuilder[W any] struct {
+	router      *Router[W]
+	pattern     string
+	opts        []RouteOption
+	middleware  []Middleware[W]
+	constraints Constraints
+}
+
+// Route returns a builder for a route with the pattern, for example:
+//
+//	router.Route("/users/:id").
+//		Name("user").
+//		Meta("scope", "admin").
+//		Middleware(Recover[W]()).
+//		Handle(handle)
+//
+// The route is registered by Handle.
+func (r *Router[W]) Route(pattern string) *RouteBuilder[W] {
+	return &RouteBuilder[W]{router: r, pattern: pattern}
+}
+
+// Name sets the handler name, see WithName.
+func (b *RouteBuilder[W]) Name(name string) *RouteBuilder[W] {
+	return b.Options(WithName(name))
+}
+
+// Meta attaches a metadata value, see WithMetadata.
+func (b *RouteBuilder[W]) Meta(key string, value any) *RouteBuilder[W] {
+	return b.Options(WithMetadata(key, value))
+}
+
+// Tags tags the route, see WithTags.
+func (b *RouteBuilder[W]) Tags(tags ...string) *RouteBuilder[W] {
+	return b.Options(WithTags(tags...))
+}
+
+// Default sets the default value of a named param, see WithDefault.
+func (b *RouteBuilder[W]) Default(name, value string) *RouteBuilder[W] {
+	return b.Options(WithDefault(name, value))
+}
+
+// Timeout sets the timeout of the handle, see WithTimeout.
+func (b *RouteBuilder[W]) Timeout(timeout time.Duration) *RouteBuilder[W] {
+	return b.Options(WithTimeout(timeout))
+}
+
+// Options adds route options.
+func (b *RouteBuilder[W]) Options(opts ...RouteOption) *RouteBuilder[W] {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Middleware adds middleware wrapping the handle.
+// The first middleware added is the outermost.
+func (b *RouteBuilder[W]) Middleware(mws ...Middleware[W]) *RouteBuilder[W] {
+	b.middleware = append(b.middleware, mws...)
+	return b
+}
+
+// Constraint constrains a param to values matching the regexp in full: the
+// regexp is anchored as by ConvertMuxPattern, so `\d+` does not match "a1". If
+// the value does not match the route declines the request and the middleware
+// and handle are not called, see ConstrainHandle.
+func (b *RouteBuilder[W]) Constraint(name string, re *regexp.Regexp) *RouteBuilder[W] {
+	if b.constraints == nil {
+		b.constraints = make(Constraints)
+	}
+	b.constraints[name] = regexp.MustCompile("^(?:" + re.String() + ")$")
+	return b
+}
+
+// Handle registers the route with the handle.
+//
+// Panics if the pattern is invalid or conflicts with a registered pattern, or
+// if an option or constraint refers to a param not in the pattern.
+func (b *RouteBuilder[W]) Handle(handle Handle[W]) {
+	if handle == nil {
+		return
+	}
+	if len(b.constraints) != 0 {
+		r := b.router
+		rt, err := newRoute[W](r.conf.UnicodeForm.Normalize(b.pattern), nil, r.conf.Separator)
+		if err != nil {
+			panic(err.Error())
+		}
+		for name := range b.constraints {
+			if !rt.hasWildcard(name) {
+				panic("constraint for unknown param '" + name + "' in path '" + b.pattern + "'")
+			}
+		}
+	}
+	handle = applyMiddleware(handle, b.middleware)
+	b.router.AddHandler(b.pattern, ConstrainHandle(b.constraints, handle), b.opts...)
+}
@@ -0,0 +1,66 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestRouteBuilder(t *testing.T) {
+	var calls []string
+	mw := func(name string) Middleware[struct{}] {
+		return func(next Handle[struct{}]) Handle[struct{}] {
+			return func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+				calls = append(calls, name)
+				return next(ctx, reqPath, p, rw)
+			}
+		}
+	}
+
+	router := New[struct{}]()
+	router.Route("/users/:id/:tab").
+		Name("user").
+		Meta("scope", "admin").
+		Default("id", "0").
+		Timeout(time.Minute).
+		Constraint("id", regexp.MustCompile(`\d+`)).
+		Middleware(mw("outer"), mw("inner")).
+		Handle(func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("expected timeout")
+			}
+			calls = append(calls, "handle "+p.ByName("id")+" "+p.ByName("tab"))
+			return true, nil
+		})
+
+	res := router.Lookup("/users/1/x")
+	if res.Name != "user" || !reflect.DeepEqual(res.Metadata, map[string]any{"scope": "admin"}) {
+		t.Errorf("unexpected lookup result %+v", res)
+	}
+
+	ctx := context.Background()
+	calls = nil
+	if found, err := router.Serve(ctx, "/users/42/posts", struct{}{}); !found || err != nil {
+		t.Fatalf("expected found, got found=%v err=%v", found, err)
+	}
+	if want := []string{"outer", "inner", "handle 42 posts"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected calls %v but got %v", want, calls)
+	}
+	calls = nil
+	for _, path := range []string{"/users/abc/posts", "/users/4a/posts"} {
+		if found, _ := router.Serve(ctx, path, struct{}{}); found || len(calls) != 0 {
+			t.Errorf("%s: expected constrained param to decline before the middleware, got calls %v", path, calls)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for constraint of unknown param")
+			}
+		}()
+		router.Route("/teams/:id").Constraint("name", regexp.MustCompile(".")).Handle(fakeHandler(""))
+	}()
+}
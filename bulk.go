@@ -0,0 +1,56 @@
+package pathrouter
+
+import (
+	"sort"
+)
+
+// AddHandlers registers the handles with the patterns in sorted pattern order.
+//
+// If any of the patterns are invalid or conflict an error is returned and none
+// of the handles are registered. Nil handles are skipped.
+func (r *Router[W]) AddHandlers(handles map[string]Handle[W]) error {
+	patterns := make([]string, 0, len(handles))
+	for pattern, handle := range handles {
+		if handle != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+
+	defs := make([]RouteDef[W], len(patterns))
+	for i, pattern := range patterns {
+		defs[i] = RouteDef[W]{Pattern: pattern, Handle: handles[pattern]}
+	}
+	return r.AddRoutes(defs)
+}
+
+// AddRoutes registers the routes in order.
+//
+// If any of the routes are invalid or conflict, including with each other, an
+// error is returned and none of the routes are registered.
+func (r *Router[W]) AddRoutes(defs []RouteDef[W]) error {
+	rts := make([]*route[W], len(defs))
+	for i, def := range defs {
+		rt, err := newRouteFromDef(def, r.conf.UnicodeForm, r.conf.Separator)
+		if err != nil {
+			return err
+		}
+		rts[i] = rt
+	}
+
+	return r.addRoutes(rts)
+}
+
+// addRoutes registers the routes in order.
+// If any of the routes conflict none of the routes are registered.
+func (r *Router[W]) addRoutes(rts []*route[W]) error {
+	return r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		for _, rt := range rts {
+			if err := t.tryAddRoute(rt, r.conf.Fallthrough); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	})
+}
@@ -0,0 +1,65 @@
+package pathrouter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouterAddHandlers(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/existing", fakeHandler("existing"))
+
+	err := router.AddHandlers(map[string]Handle[struct{}]{
+		"/users/:id": fakeHandler("user"),
+		"/about":     fakeHandler("about"),
+		"/skipped":   nil,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var patterns []string
+	for _, def := range router.Routes() {
+		patterns = append(patterns, def.Pattern)
+	}
+	if want := []string{"/existing", "/about", "/users/:id"}; !reflect.DeepEqual(patterns, want) {
+		t.Errorf("expected patterns %v but got %v", want, patterns)
+	}
+
+	for _, handles := range []map[string]Handle[struct{}]{
+		{"/a": fakeHandler(""), "/existing": fakeHandler("")},
+		{"/a": fakeHandler(""), "/users/:name": fakeHandler("")},
+		{"/a": fakeHandler(""), "/b/:": fakeHandler("")},
+	} {
+		if err := router.AddHandlers(handles); err == nil {
+			t.Errorf("handles %v: expected error", handles)
+		}
+		if router.HasRoute("/a") {
+			t.Fatalf("handles %v: expected no routes to be registered", handles)
+		}
+	}
+}
+
+func TestRouterAddRoutes(t *testing.T) {
+	router := New[struct{}]()
+	err := router.AddRoutes([]RouteDef[struct{}]{
+		{Pattern: "/b", Handle: fakeHandler("b"), Name: "b"},
+		{Pattern: "/a", Handle: fakeHandler("a")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if defs := router.Routes(); len(defs) != 2 || defs[0].Pattern != "/b" || defs[0].Name != "b" {
+		t.Errorf("unexpected routes %v", defs)
+	}
+
+	err = router.AddRoutes([]RouteDef[struct{}]{
+		{Pattern: "/c", Handle: fakeHandler("c")},
+		{Pattern: "/c", Handle: fakeHandler("c")},
+	})
+	if err == nil || router.HasRoute("/c") {
+		t.Errorf("expected duplicate error without registering, got %v", err)
+	}
+	if err := router.AddRoutes([]RouteDef[struct{}]{{Pattern: "/nil"}}); err == nil {
+		t.Error("expected error for nil handle")
+	}
+}
@@ -0,0 +1,35 @@
+package pathrouter
+
+import (
+	"context"
+	"unsafe"
+)
+
+// bytesToString returns a string sharing the memory of b without copying.
+// The bytes must not be modified while the string is in use.
+func bytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+// LookupPathBytes looks up the path as with LookupPath without converting the
+// path to a string, for paths read straight off the wire.
+//
+// The values of the returned params share the memory of the path: the path
+// must not be modified while the params are in use, see Params.Clone.
+func (r *Router[W]) LookupPathBytes(path []byte) (Handle[W], Params, bool) {
+	return r.LookupPath(bytesToString(path))
+}
+
+// ServeBytes serves the path as with Serve without converting the path to a
+// string, for paths read straight off the wire.
+//
+// The path and the param values passed to the handles, the hooks and the
+// Observer share the memory of the path: the path must not be modified until
+// ServeBytes returns and strings derived from it must be copied to be retained,
+// for example with strings.Clone or Params.Clone.
+func (r *Router[W]) ServeBytes(ctx context.Context, path []byte, wr W) (bool, error) {
+	return r.Serve(ctx, bytesToString(path), wr)
+}
@@ -0,0 +1,57 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRouterBytes(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/static", fakeHandler("static"))
+	router.AddHandler("/user/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		fakeHandlerValue = reqPath + " " + p.ByName("name")
+		return true, nil
+	})
+
+	tests := []struct {
+		path  string
+		found bool
+		want  string
+		tsr   bool
+	}{
+		{"/static", true, "static", false},
+		{"/user/gopher", true, "/user/gopher gopher", false},
+		{"/static/", false, "static", true},
+		{"", false, "", false},
+	}
+	for _, tt := range tests {
+		handle, ps, tsr := router.LookupPathBytes([]byte(tt.path))
+		if (handle != nil) != tt.found || tsr != tt.tsr {
+			t.Errorf("%q: expected found=%v tsr=%v, got found=%v tsr=%v", tt.path, tt.found, tt.tsr, handle != nil, tsr)
+		}
+		if tt.found {
+			fakeHandlerValue = ""
+			if _, err := handle(context.Background(), tt.path, ps, struct{}{}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.path == "/user/gopher" && ps.ByName("name") != "gopher" {
+				t.Errorf("%q: unexpected params %v", tt.path, ps)
+			}
+		}
+
+		fakeHandlerValue = ""
+		found, err := router.ServeBytes(context.Background(), []byte(tt.path), struct{}{})
+		if err != nil || (found && fakeHandlerValue != tt.want) {
+			t.Errorf("%q: expected %q, got %q found=%v err=%v", tt.path, tt.want, fakeHandlerValue, found, err)
+		}
+	}
+
+	path := []byte("/static")
+	if allocs := testing.AllocsPerRun(100, func() {
+		if handle, _, _ := router.LookupPathBytes(path); handle == nil {
+			t.Fatal("expected static route")
+		}
+	}); allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,145 @@
+package pathrouter
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// lruCache is a bounded least recently used cache safe for concurrent use.
+type lruCache[K comparable, V any] struct {
+	mtx     sync.Mutex
+	size    int
+	entries map[K]*list.Element
+	order   *list.List
+}
+
+// lruEntry is an entry of a lruCache.
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// newLRUCache constructs a new cache holding at most size entries.
+func newLRUCache[K comparable, V any](size int) *lruCache[K, V] {
+	return &lruCache[K, V]{size: size, entries: make(map[K]*list.Element, size), order: list.New()}
+}
+
+// get returns the value for the key and marks it as recently used.
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		var empty V
+		return empty, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry[K, V]).value, true
+}
+
+// add adds or replaces the value for the key evicting the least recently used
+// entry if the cache is full.
+func (c *lruCache[K, V]) add(key K, value V) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+}
+
+// len returns the number of entries.
+func (c *lruCache[K, V]) len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.order.Len()
+}
+
+// cachedMatch is a match in the lookup cache, see RouterConfig.LookupCacheSize.
+type cachedMatch[W any] struct {
+	route  *route[W]
+	handle Handle[W]
+	// params contains the params in the form used by the trees.
+	// Shared between requests: copied before use.
+	params Params
+}
+
+// lookupCache returns the lookup cache of the table or nil if disabled.
+// The cache is created on first use: each table has its own cache so changes
+// to the routes invalidate it.
+func (r *Router[W]) lookupCache(t *table[W]) *lruCache[string, cachedMatch[W]] {
+	if r.conf.LookupCacheSize <= 0 {
+		return nil
+	}
+	if c := t.cache.Load(); c != nil {
+		return c
+	}
+	c := newLRUCache[string, cachedMatch[W]](r.conf.LookupCacheSize)
+	if !t.cache.CompareAndSwap(nil, c) {
+		return t.cache.Load()
+	}
+	return c
+}
+
+// cachedLookup returns the cached match for the path, if any, with a copy of
+// the params from the pool.
+func (r *Router[W]) cachedLookup(cache *lruCache[string, cachedMatch[W]], reqPath string) (match[W], bool) {
+	cm, ok := cache.get(reqPath)
+	if !ok || !cm.route.enabled() {
+		return match[W]{}, false
+	}
+	m := match[W]{route: cm.route, handle: cm.handle}
+	if cm.params != nil {
+		m.ps = r.getParams()
+		*m.ps = append(*m.ps, cm.params...)
+	}
+	return m, true
+}
+
+// cacheMatch adds the match for the path to the cache. The path and the
+// params are copied as they can share memory with the caller, see ServeBytes.
+func cacheMatch[W any](cache *lruCache[string, cachedMatch[W]], reqPath string, leaf *node[W], ps *Params) {
+	cm := cachedMatch[W]{route: leaf.route, handle: leaf.handle}
+	if ps != nil {
+		cm.params = make(Params, len(*ps))
+		for i, p := range *ps {
+			cm.params[i] = Param{Key: p.Key, Value: strings.Clone(p.Value)}
+		}
+	}
+	cache.add(strings.Clone(reqPath), cm)
+}
+
+// missCache returns the not found cache of the table or nil if disabled.
+// The cache is created on first use, see lookupCache.
+func (r *Router[W]) missCache(t *table[W]) *lruCache[string, struct{}] {
+	if r.conf.NotFoundCacheSize <= 0 {
+		return nil
+	}
+	if c := t.misses.Load(); c != nil {
+		return c
+	}
+	c := newLRUCache[string, struct{}](r.conf.NotFoundCacheSize)
+	if !t.misses.CompareAndSwap(nil, c) {
+		return t.misses.Load()
+	}
+	return c
+}
+
+// pureMiss checks if the path not found has no corrected path even to a
+// disabled route, so the miss stays valid when routes are enabled.
+func (r *Router[W]) pureMiss(t *table[W], reqPath string, tsr bool) bool {
+	if !r.anyDisabled.Load() {
+		// correctPath did not filter the disabled routes
+		return true
+	}
+	_, corrected := r.findCorrectedPath(t, reqPath, tsr)
+	return !corrected
+}
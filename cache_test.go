@@ -0,0 +1,143 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache[string, int](2)
+	c.add("a", 1)
+	c.add("b", 2)
+	if v, ok := c.get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, got %v %v", v, ok)
+	}
+	c.add("c", 3) // evicts b
+	if _, ok := c.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	c.add("a", 4)
+	if v, _ := c.get("a"); v != 4 || c.len() != 2 {
+		t.Errorf("expected a=4 with 2 entries, got %v with %d", v, c.len())
+	}
+}
+
+func TestRouterLookupCache(t *testing.T) {
+	var got string
+	handle := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		got = reqPath + " " + p.ByName("name")
+		// the handle may modify the params in place
+		for i := range p {
+			p[i].Value = "modified"
+		}
+		return true, nil
+	}
+
+	conf := DefaultConfig[struct{}]()
+	conf.LookupCacheSize = 2
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", handle)
+
+	ctx := context.Background()
+	serve := func(path string) string {
+		got = ""
+		if found, err := router.Serve(ctx, path, struct{}{}); !found || err != nil {
+			t.Fatalf("%s: expected found, got found=%v err=%v", path, found, err)
+		}
+		return got
+	}
+	for i := 0; i < 3; i++ {
+		if res := serve("/user/gopher"); res != "/user/gopher gopher" {
+			t.Fatalf("unexpected result %q", res)
+		}
+	}
+	if n := router.lookupCache(router.load()).len(); n != 1 {
+		t.Errorf("expected 1 cached path, got %d", n)
+	}
+
+	// the cached path must not share memory with the caller
+	path := []byte("/user/alice")
+	if _, err := router.ServeBytes(ctx, path, struct{}{}); err != nil || got != "/user/alice alice" {
+		t.Fatalf("unexpected result %q err=%v", got, err)
+	}
+	copy(path, "/user/bobby")
+	if res := serve("/user/alice"); res != "/user/alice alice" {
+		t.Errorf("expected the cached path to be copied, got %q", res)
+	}
+
+	// disabled routes are not served from the cache
+	router.SetRouteEnabled("/user/:name", false)
+	if found, _ := router.Serve(ctx, "/user/gopher", struct{}{}); found {
+		t.Error("expected the disabled route not to be served")
+	}
+	router.SetRouteEnabled("/user/:name", true)
+
+	// changing the routes clears the cache
+	err := router.ApplyDelta(RouteDelta[struct{}]{
+		FromVersion: router.Version(),
+		ToVersion:   router.Version() + 1,
+		Added: []RouteDef[struct{}]{{Pattern: "/user/:name", Handle: func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			got = "replaced " + p.ByName("name")
+			return true, nil
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := router.lookupCache(router.load()).len(); n != 0 {
+		t.Errorf("expected an empty cache after a change, got %d", n)
+	}
+	if res := serve("/user/gopher"); res != "replaced gopher" {
+		t.Errorf("expected the new route, got %q", res)
+	}
+}
+
+func TestRouterNotFoundCache(t *testing.T) {
+	var notFound int
+	conf := DefaultConfig[struct{}]()
+	conf.NotFoundCacheSize = 8
+	conf.NotFound = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		notFound++
+		return true, nil
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", fakeHandler("user"))
+	router.AddHandler("/dir/", fakeHandler("dir"))
+	router.AddHandler("/decline", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, nil
+	})
+	router.AddHandler("/off", fakeHandler("off"))
+	router.SetRouteEnabled("/off", false)
+
+	ctx := context.Background()
+	misses := func() int { return router.missCache(router.load()).len() }
+	for _, path := range []string{"/missing", "/missing", "/MISSING/../missing"} {
+		notFound = 0
+		if found, err := router.Serve(ctx, path, struct{}{}); !found || err != nil || notFound != 1 {
+			t.Errorf("%s: expected the NotFound handler, got found=%v err=%v calls=%d", path, found, err, notFound)
+		}
+	}
+	if n := misses(); n != 2 {
+		t.Errorf("expected 2 cached misses, got %d", n)
+	}
+
+	// declined, disabled and corrected paths are not cached
+	for _, path := range []string{"/decline", "/off", "/off/", "/dir", "/USER/x"} {
+		_, _ = router.Serve(ctx, path, struct{}{})
+	}
+	if n := misses(); n != 2 {
+		t.Errorf("expected 2 cached misses, got %d", n)
+	}
+	router.SetRouteEnabled("/off", true)
+	fakeHandlerValue = ""
+	if found, _ := router.Serve(ctx, "/off/", struct{}{}); !found || fakeHandlerValue != "off" {
+		t.Errorf("expected the enabled route to be served, got %q", fakeHandlerValue)
+	}
+
+	// changing the routes clears the cache
+	router.AddHandler("/missing", fakeHandler("missing"))
+	fakeHandlerValue = ""
+	if found, _ := router.Serve(ctx, "/missing", struct{}{}); !found || fakeHandlerValue != "missing" {
+		t.Errorf("expected the new route to be served, got %q", fakeHandlerValue)
+	}
+}
@@ -0,0 +1,25 @@
+package pathrouter
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ctxErr returns the context error if CheckCanceled is set.
+func (r *Router[W]) ctxErr(ctx context.Context) error {
+	if !r.conf.CheckCanceled {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// canceled handles a canceled request, see RouterConfig.CheckCanceled.
+func (r *Router[W]) canceled(ctx context.Context, reqPath string, wr W, err error) (bool, error) {
+	if r.debugEnabled(ctx) {
+		r.logDebug(ctx, "canceled request", slog.String("path", reqPath), slog.Any("error", err))
+	}
+	if r.conf.CanceledHandler != nil {
+		return r.conf.CanceledHandler(ctx, reqPath, wr, err)
+	}
+	return false, err
+}
@@ -0,0 +1,65 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRouterCheckCanceled(t *testing.T) {
+	var calls int
+	handle := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		calls++
+		return false, nil
+	}
+
+	conf := DefaultConfig[struct{}]()
+	conf.CheckCanceled = true
+	conf.Fallthrough = true
+	router := NewWithConfig(conf)
+	router.AddHandler("/a/:name", handle)
+	router.AddHandler("/a/*rest", handle)
+	router.AddHandler("/dir/", handle)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, path := range []string{"/a/b", "/dir", "/missing"} {
+		calls = 0
+		if found, err := router.Serve(canceled, path, struct{}{}); found || !errors.Is(err, context.Canceled) || calls != 0 {
+			t.Errorf("path %s: expected canceled, got found=%v err=%v calls=%d", path, found, err, calls)
+		}
+	}
+	if found, err := router.ServeAll(canceled, "/a/b", struct{}{}); found || !errors.Is(err, context.Canceled) || calls != 0 {
+		t.Errorf("expected canceled, got found=%v err=%v calls=%d", found, err, calls)
+	}
+
+	// the context is checked before each handle
+	ctx, cancel := context.WithCancel(context.Background())
+	router.AddHandler("/cancel/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		calls++
+		cancel()
+		return false, nil
+	})
+	router.AddHandler("/cancel/*rest", handle)
+	calls = 0
+	if _, err := router.Serve(ctx, "/cancel/x", struct{}{}); !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("expected canceled after the first handle, got err=%v calls=%d", err, calls)
+	}
+
+	conf.CanceledHandler = func(ctx context.Context, reqPath string, rw struct{}, err error) (bool, error) {
+		return true, errors.New("shed " + reqPath)
+	}
+	withHandler := NewWithConfig(conf)
+	withHandler.AddHandler("/a", handle)
+	if found, err := withHandler.Serve(canceled, "/a", struct{}{}); !found || err == nil || err.Error() != "shed /a" {
+		t.Errorf("expected canceled handler result, got found=%v err=%v", found, err)
+	}
+
+	// not checked by default
+	calls = 0
+	unchecked := New[struct{}]()
+	unchecked.AddHandler("/a", handle)
+	if _, err := unchecked.Serve(canceled, "/a", struct{}{}); err != nil || calls != 1 {
+		t.Errorf("expected handle to be called, got err=%v calls=%d", err, calls)
+	}
+}
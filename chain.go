@@ -0,0 +1,70 @@
+package pathrouter
+
+import "context"
+
+// chainHandles returns a handle calling each of the handles in order until one
+// returns true or an error.
+func chainHandles[W any](handles []Handle[W]) Handle[W] {
+	if len(handles) == 1 {
+		return handles[0]
+	}
+	return func(ctx context.Context, reqPath string, p Params, rw W) (bool, error) {
+		for _, handle := range handles {
+			found, err := handle(ctx, reqPath, p, rw)
+			if found || err != nil {
+				return found, err
+			}
+		}
+		return false, nil
+	}
+}
+
+// AppendHandler appends handles to the chain registered for the path.
+//
+// The handles in the chain are called in the order they were appended until
+// one returns true or an error. If no handle is registered for the path, a new
+// chain is registered as with AddHandler. Appending to a registered route does
+// not change the version, see Version.
+func (r *Router[W]) AppendHandler(path string, handles ...Handle[W]) {
+	var add []Handle[W]
+	for _, handle := range handles {
+		if handle != nil {
+			add = append(add, handle)
+		}
+	}
+	if len(add) == 0 {
+		return
+	}
+
+	rt, err := newRoute[W](r.conf.UnicodeForm.Normalize(path), nil, r.conf.Separator)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	err = r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		existing := t.routes[rt.pattern]
+		if existing == nil {
+			rt.handle, rt.handles = chainHandles(add), add
+			t.addRoute(rt, r.conf.Fallthrough)
+			return t, nil
+		}
+
+		chain := existing.handles
+		if len(chain) == 0 {
+			chain = []Handle[W]{existing.handle}
+		}
+		chain = append(chain[:len(chain):len(chain)], add...)
+
+		// routes are shared with the previous table: replace instead of modify.
+		// The definition of the route is unchanged: keep the version.
+		updated := *existing
+		updated.handles = chain
+		updated.handle = chainHandles(chain)
+		t.swapRoute(existing, &updated)
+		return t, nil
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+}
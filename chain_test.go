@@ -0,0 +1,62 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestRouterAppendHandler(t *testing.T) {
+	var calls []string
+	handler := func(name string, found bool, err error) Handle[struct{}] {
+		return func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			calls = append(calls, name+" "+p.ByName("name"))
+			return found, err
+		}
+	}
+
+	router := New[struct{}]()
+	router.AppendHandler("/user/:name", handler("auth", false, nil), handler("cache", false, nil))
+	router.AppendHandler("/user/:name", nil, handler("render", true, nil))
+	router.AppendHandler("/user/:name", handler("unreachable", true, nil))
+	router.AddHandler("/fail", handler("fail", false, errors.New("fail")))
+	router.AppendHandler("/fail", handler("unreachable", true, nil))
+	router.AppendHandler("/files/*filepath", handler("files", false, nil))
+	router.AppendHandler("/files/*filepath", handler("files2", false, nil))
+
+	version := router.Version()
+	router.AppendHandler("/files/*filepath", handler("files3", false, nil))
+	if router.Version() != version {
+		t.Errorf("expected appending to a route to keep the version %d, got %d", version, router.Version())
+	}
+
+	if recv := catchPanic(func() {
+		router.AddHandler("/user/:name", handler("conflict", true, nil))
+	}); recv == nil {
+		t.Error("no panic for AddHandler on an existing chain")
+	}
+
+	tests := []struct {
+		path  string
+		found bool
+		err   bool
+		calls []string
+	}{
+		{"/user/gopher", true, false, []string{"auth gopher", "cache gopher", "render gopher"}},
+		{"/fail", false, true, []string{"fail "}},
+		{"/files/a", false, false, []string{"files ", "files2 ", "files3 "}},
+	}
+	ctx := context.Background()
+	for _, test := range tests {
+		calls = nil
+		found, err := router.Serve(ctx, test.path, struct{}{})
+		if found != test.found || (err != nil) != test.err {
+			t.Errorf("path %s: unexpected result found=%v err=%v", test.path, found, err)
+		}
+		if !reflect.DeepEqual(calls, test.calls) {
+			t.Errorf("path %s: expected calls %q but got %q", test.path, test.calls, calls)
+		}
+	}
+}
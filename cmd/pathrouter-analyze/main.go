@@ -0,0 +1,96 @@
+// Command pathrouter-analyze reports problems with a route manifest, see
+// Router.Analyze.
+//
+// The route manifest is a text file with one pattern per line, read from
+// stdin if no file is given. Empty lines and lines starting with '#' are
+// ignored. Exits with status 1 if any problems are found.
+//
+//	pathrouter-analyze -routes routes.txt
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+// handle is the handle registered for every route.
+func handle(ctx context.Context, reqPath string, p pathrouter.Params, rw struct{}) (bool, error) {
+	return true, nil
+}
+
+// readLines reads the non-empty, non-comment lines.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// addRoute registers the route returning the panic message if any.
+func addRoute(router *pathrouter.Router[struct{}], pattern string) (msg string) {
+	defer func() {
+		if rcv := recover(); rcv != nil {
+			msg = fmt.Sprint(rcv)
+		}
+	}()
+	router.AddHandler(pattern, handle)
+	return ""
+}
+
+func main() {
+	routesPath := flag.String("routes", "", "path to the route manifest (default stdin)")
+	fallthroughRoutes := flag.Bool("fallthrough", false, "allow overlapping routes as with RouterConfig.Fallthrough")
+	noRedirects := flag.Bool("no-redirects", false, "disable the trailing slash and fixed path redirects")
+	flag.Parse()
+
+	in := io.Reader(os.Stdin)
+	if *routesPath != "" {
+		f, err := os.Open(*routesPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(2)
+		}
+		defer f.Close()
+		in = f
+	}
+	routes, err := readLines(in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+
+	conf := pathrouter.DefaultConfig[struct{}]()
+	if *noRedirects {
+		conf = pathrouter.RouterConfig[struct{}]{}
+	}
+	conf.Fallthrough = *fallthroughRoutes
+	router := pathrouter.NewWithConfig(conf)
+
+	var count int
+	for _, route := range routes {
+		if msg := addRoute(router, route); msg != "" {
+			fmt.Printf("%s: cannot register: %s\n", route, msg)
+			count++
+		}
+	}
+	for _, problem := range router.Analyze() {
+		fmt.Println(problem.String())
+		count++
+	}
+	if count != 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,215 @@
+// Command pathrouter-bench compares lookup performance across router
+// configurations for a route manifest and a sample path corpus.
+//
+// The route manifest and path corpus are text files with one entry per line.
+// Empty lines and lines starting with '#' are ignored.
+//
+//	pathrouter-bench -routes routes.txt -paths paths.txt
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+// benchConfig is a named router configuration to benchmark.
+type benchConfig struct {
+	// name is the name of the configuration.
+	name string
+	// desc describes the configuration.
+	desc string
+	// build constructs a router with the routes registered.
+	build func(routes []string) *pathrouter.Router[struct{}]
+}
+
+// cacheSize is the size of the lookup and not found caches of the cached
+// configuration.
+const cacheSize = 4096
+
+// benchConfigs are the available configurations in display order.
+var benchConfigs = []benchConfig{{
+	name: "default",
+	desc: "DefaultConfig: trailing slash and fixed path redirects",
+	build: func(routes []string) *pathrouter.Router[struct{}] {
+		return buildRouter(pathrouter.DefaultConfig[struct{}](), routes)
+	},
+}, {
+	name: "strict",
+	desc: "no trailing slash or fixed path redirects",
+	build: func(routes []string) *pathrouter.Router[struct{}] {
+		return buildRouter(pathrouter.RouterConfig[struct{}]{}, routes)
+	},
+}, {
+	name: "static",
+	desc: "DefaultConfig with StaticFastPath",
+	build: func(routes []string) *pathrouter.Router[struct{}] {
+		conf := pathrouter.DefaultConfig[struct{}]()
+		conf.StaticFastPath = true
+		return buildRouter(conf, routes)
+	},
+}, {
+	name: "cached",
+	desc: "DefaultConfig with the lookup and not found caches",
+	build: func(routes []string) *pathrouter.Router[struct{}] {
+		conf := pathrouter.DefaultConfig[struct{}]()
+		conf.LookupCacheSize = cacheSize
+		conf.NotFoundCacheSize = cacheSize
+		return buildRouter(conf, routes)
+	},
+}, {
+	name: "backtracking",
+	desc: "DefaultConfig with Fallthrough: overlapping routes, declined matches try the next route",
+	build: func(routes []string) *pathrouter.Router[struct{}] {
+		conf := pathrouter.DefaultConfig[struct{}]()
+		conf.Fallthrough = true
+		return buildRouter(conf, routes)
+	},
+}, {
+	name: "compiled",
+	desc: "DefaultConfig with Compile",
+	build: func(routes []string) *pathrouter.Router[struct{}] {
+		return buildRouter(pathrouter.DefaultConfig[struct{}](), routes).Compile()
+	},
+}}
+
+// handle is the handler registered for every route.
+func handle(ctx context.Context, reqPath string, p pathrouter.Params, rw struct{}) (bool, error) {
+	return true, nil
+}
+
+// buildRouter builds a router with the config and routes.
+func buildRouter(conf pathrouter.RouterConfig[struct{}], routes []string) *pathrouter.Router[struct{}] {
+	router := pathrouter.NewWithConfig(conf)
+	for _, route := range routes {
+		router.AddHandler(route, handle)
+	}
+	return router
+}
+
+// readLines reads the non-empty, non-comment lines of a file.
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func main() {
+	routesPath := flag.String("routes", "", "path to the route manifest")
+	pathsPath := flag.String("paths", "", "path to the sample path corpus")
+	configNames := flag.String("configs", "", "comma-separated configurations to run (default all)")
+	listConfigs := flag.Bool("list", false, "list the available configurations and exit")
+	flag.Parse()
+
+	if *listConfigs {
+		for _, conf := range benchConfigs {
+			fmt.Printf("%s\t%s\n", conf.name, conf.desc)
+		}
+		return
+	}
+
+	if err := run(*routesPath, *pathsPath, *configNames); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// run runs the benchmarks and prints the comparison table.
+func run(routesPath, pathsPath, configNames string) error {
+	if routesPath == "" || pathsPath == "" {
+		return fmt.Errorf("both -routes and -paths must be specified")
+	}
+
+	routes, err := readLines(routesPath)
+	if err != nil {
+		return err
+	}
+	paths, err := readLines(pathsPath)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no paths in corpus: %s", pathsPath)
+	}
+
+	confs, err := selectConfigs(configNames)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("routes: %d paths: %d\n\n", len(routes), len(paths))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "config\tmatched\tns/path\tB/path\tallocs/path\t")
+	for _, conf := range confs {
+		router := conf.build(routes)
+
+		ctx := context.Background()
+		var matched int
+		for _, p := range paths {
+			if found, _ := router.Serve(ctx, p, struct{}{}); found {
+				matched++
+			}
+		}
+
+		res := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = router.Serve(ctx, paths[i%len(paths)], struct{}{})
+			}
+		})
+		fmt.Fprintf(
+			tw,
+			"%s\t%d/%d\t%d\t%d\t%d\t\n",
+			conf.name,
+			matched,
+			len(paths),
+			res.NsPerOp(),
+			res.AllocedBytesPerOp(),
+			res.AllocsPerOp(),
+		)
+	}
+	return tw.Flush()
+}
+
+// selectConfigs selects the configurations from a comma-separated list.
+func selectConfigs(names string) ([]benchConfig, error) {
+	if names == "" {
+		return benchConfigs, nil
+	}
+	var confs []benchConfig
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		var found bool
+		for _, conf := range benchConfigs {
+			if conf.name == name {
+				confs = append(confs, conf)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown config: %s", name)
+		}
+	}
+	return confs, nil
+}
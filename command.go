@@ -0,0 +1,100 @@
+package pathrouter
+
+import (
+	"context"
+	"strings"
+)
+
+// commandArgEscaper escapes the separator and escape characters in arguments.
+var commandArgEscaper = strings.NewReplacer("%", "%25", " ", "%20")
+
+// commandArgUnescaper reverses commandArgEscaper.
+var commandArgUnescaper = strings.NewReplacer("%20", " ", "%25", "%")
+
+// CommandLinePath converts the arguments of a command line to the path matched
+// by a CommandRouter. The arguments are joined with spaces, escaping spaces and
+// percent signs within the arguments.
+func CommandLinePath(args []string) string {
+	escaped := make([]string, len(args))
+	for i, arg := range args {
+		escaped[i] = commandArgEscaper.Replace(arg)
+	}
+	return strings.Join(escaped, " ")
+}
+
+// SplitCommandArgs splits the value of a catch-all param matched by a
+// CommandRouter into the arguments.
+func SplitCommandArgs(value string) []string {
+	value = strings.TrimPrefix(value, " ")
+	if value == "" {
+		return nil
+	}
+	args := strings.Split(value, " ")
+	for i, arg := range args {
+		args[i] = commandArgUnescaper.Replace(arg)
+	}
+	return args
+}
+
+// CommandRouter dispatches command lines like "deploy prod web-1 web-2" to the
+// handles registered with space separated patterns like "deploy :env *hosts".
+//
+// Named params contain the argument. Catch-all params contain the remaining
+// arguments with a leading space in escaped form: use SplitCommandArgs to get
+// the arguments. The handles are called with the command line path, see
+// CommandLinePath.
+//
+// Safe to use concurrently.
+type CommandRouter[W any] struct {
+	router *Router[W]
+}
+
+// NewCommandRouter constructs a new CommandRouter with the given config.
+//
+// The Separator of the config is set to a space and RedirectTrailingSlash is
+// set so catch-all params also match zero arguments.
+func NewCommandRouter[W any](conf RouterConfig[W]) *CommandRouter[W] {
+	conf.Separator = ' '
+	conf.RedirectTrailingSlash = true
+	return &CommandRouter[W]{router: NewWithConfig(conf)}
+}
+
+// Router returns the underlying router.
+func (c *CommandRouter[W]) Router() *Router[W] {
+	return c.router
+}
+
+// AddCommand registers a new handle with the given command pattern.
+// The fields of the pattern may be separated by any amount of white space.
+//
+// Panics if the pattern is invalid or conflicts with a registered pattern.
+func (c *CommandRouter[W]) AddCommand(pattern string, handle Handle[W], opts ...RouteOption) {
+	if handle == nil {
+		return
+	}
+	c.router.AddHandler(strings.Join(strings.Fields(pattern), " "), func(ctx context.Context, reqPath string, p Params, rw W) (bool, error) {
+		for i := range p {
+			// catch-all values start with the separator and are split by the handle
+			if !strings.HasPrefix(p[i].Value, " ") {
+				p[i].Value = commandArgUnescaper.Replace(p[i].Value)
+			}
+		}
+		return handle(ctx, reqPath, p, rw)
+	}, opts...)
+}
+
+// Lookup looks up the command line, see Router.Lookup. The redirect path is
+// looked up if the command line is not found, as with Serve.
+// The named params in the result are not unescaped.
+func (c *CommandRouter[W]) Lookup(args []string) LookupResult[W] {
+	res := c.router.Lookup(CommandLinePath(args))
+	if !res.Found() && res.RedirectPath != "" {
+		res = c.router.Lookup(res.RedirectPath)
+	}
+	return res
+}
+
+// Serve serves the command line with the handle of the matching pattern.
+func (c *CommandRouter[W]) Serve(ctx context.Context, args []string, rw W) (bool, error) {
+	return c.router.Serve(ctx, CommandLinePath(args), rw)
+}
@@ -0,0 +1,69 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCommandLinePath(t *testing.T) {
+	tests := []struct {
+		args []string
+		path string
+	}{
+		{[]string{"deploy", "prod"}, "deploy prod"},
+		{[]string{"echo", "hello world", "100%"}, "echo hello%20world 100%25"},
+		{[]string{"cp", "a/b", ""}, "cp a/b "},
+	}
+	for _, test := range tests {
+		if path := CommandLinePath(test.args); path != test.path {
+			t.Errorf("args %q: expected %q but got %q", test.args, test.path, path)
+		}
+		if args := SplitCommandArgs(" " + CommandLinePath(test.args)); !reflect.DeepEqual(args, test.args) {
+			t.Errorf("args %q: expected to round-trip but got %q", test.args, args)
+		}
+	}
+}
+
+func TestCommandRouter(t *testing.T) {
+	var gotParams Params
+	var gotArgs []string
+	handler := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		gotParams = append(Params(nil), p...)
+		gotArgs = SplitCommandArgs(p.ByName("hosts"))
+		return true, nil
+	}
+
+	router := NewCommandRouter(RouterConfig[struct{}]{})
+	router.AddCommand("deploy   :env *hosts", handler)
+	router.AddCommand("echo :msg", handler)
+	router.AddCommand("status", handler)
+
+	tests := []struct {
+		args   []string
+		params Params
+		hosts  []string
+	}{
+		{[]string{"deploy", "prod", "web-1", "web 2"}, Params{{"env", "prod"}, {"hosts", " web-1 web%202"}}, []string{"web-1", "web 2"}},
+		{[]string{"deploy", "dev"}, Params{{"env", "dev"}, {"hosts", " "}}, nil},
+		{[]string{"echo", "50% off/now"}, Params{{"msg", "50% off/now"}}, nil},
+		{[]string{"status"}, nil, nil},
+	}
+	for _, test := range tests {
+		gotParams, gotArgs = nil, nil
+		if found, err := router.Serve(context.Background(), test.args, struct{}{}); !found || err != nil {
+			t.Errorf("args %q: expected found, got found=%v err=%v", test.args, found, err)
+			continue
+		}
+		if !reflect.DeepEqual(gotParams, test.params) || !reflect.DeepEqual(gotArgs, test.hosts) {
+			t.Errorf("args %q: expected %v %q but got %v %q", test.args, test.params, test.hosts, gotParams, gotArgs)
+		}
+	}
+
+	if found, _ := router.Serve(context.Background(), []string{"echo", "a", "b"}, struct{}{}); found {
+		t.Error("expected echo with two args not to be found")
+	}
+	if res := router.Lookup([]string{"deploy", "prod"}); !res.Found() || res.Pattern != "deploy :env *hosts" {
+		t.Errorf("unexpected lookup result %+v", res)
+	}
+}
@@ -0,0 +1,172 @@
+package pathrouter
+
+import (
+	"context"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MuxCatchAllName is the catch-all parameter name used for the chi style
+// trailing "*" wildcard.
+const MuxCatchAllName = "wildcard"
+
+// Constraints maps parameter names to the regular expressions the values must
+// match in full.
+type Constraints map[string]*regexp.Regexp
+
+// Match checks if the params satisfy the constraints.
+func (c Constraints) Match(ps Params) bool {
+	for name, re := range c {
+		if !re.MatchString(ps.ByName(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ConvertMuxPattern converts a gorilla/mux or chi style pattern to a pathrouter
+// pattern and the constraints for the parameters.
+//
+// Supported are {name} and {name:regexp} parameters spanning a full path
+// segment and the chi style trailing "*" wildcard, which is converted to a
+// catch-all named MuxCatchAllName. Returns an error for constructs the router
+// cannot represent, such as parameters spanning part of a segment, literal ':'
+// and '*' characters and regexps which can match a '/'.
+//
+// Parameter values never contain a slash: the constraints only apply to a
+// single path segment.
+func ConvertMuxPattern(pattern string) (string, Constraints, error) {
+	var sb strings.Builder
+	var constraints Constraints
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '}':
+			return "", nil, errors.Errorf("unmatched '}' in pattern '%s'", pattern)
+		case ':':
+			return "", nil, errors.Errorf("unsupported literal ':' in pattern '%s'", pattern)
+		case '*':
+			if i != len(pattern)-1 || i == 0 || pattern[i-1] != '/' {
+				return "", nil, errors.Errorf("the '*' wildcard must be the final path segment in pattern '%s'", pattern)
+			}
+			sb.WriteString("*" + MuxCatchAllName)
+			continue
+		case '{':
+		default:
+			sb.WriteByte(c)
+			continue
+		}
+
+		// find the matching brace, the regexp may contain braces
+		depth, end := 1, -1
+		for j := i + 1; j < len(pattern) && end < 0; j++ {
+			switch pattern[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					end = j
+				}
+			}
+		}
+		if end < 0 {
+			return "", nil, errors.Errorf("unmatched '{' in pattern '%s'", pattern)
+		}
+
+		name, expr, hasExpr := strings.Cut(pattern[i+1:end], ":")
+		name = strings.TrimSpace(name)
+		if name == "" || strings.ContainsAny(name, "/*{}.") {
+			return "", nil, errors.Errorf("invalid parameter name '%s' in pattern '%s'", name, pattern)
+		}
+		if (i != 0 && pattern[i-1] != '/') || (end+1 < len(pattern) && pattern[end+1] != '/') {
+			return "", nil, errors.Errorf("parameter '%s' must span a full path segment in pattern '%s'", name, pattern)
+		}
+		if hasExpr {
+			re, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return "", nil, errors.Wrapf(err, "parameter '%s' in pattern '%s'", name, pattern)
+			}
+			if matchesSlash(re) {
+				return "", nil, errors.Errorf("regexp of parameter '%s' in pattern '%s' can match '/': parameters match a single path segment", name, pattern)
+			}
+			if constraints == nil {
+				constraints = make(Constraints)
+			}
+			constraints[name] = re
+		}
+		sb.WriteString(":" + name)
+		i = end
+	}
+
+	out := sb.String()
+	if _, _, err := parsePattern(out); err != nil {
+		return "", nil, errors.Wrapf(err, "pattern '%s'", pattern)
+	}
+	return out, constraints, nil
+}
+
+// matchesSlash checks if the regexp can match a string containing a '/'.
+func matchesSlash(re *regexp.Regexp) bool {
+	prog, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return true
+	}
+	return syntaxMatchesSlash(prog)
+}
+
+// syntaxMatchesSlash checks if the regexp or any of its sub-expressions can
+// match a '/'.
+func syntaxMatchesSlash(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return true
+	case syntax.OpLiteral:
+		if strings.ContainsRune(string(re.Rune), '/') {
+			return true
+		}
+	case syntax.OpCharClass:
+		// the class contains pairs of inclusive ranges
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if re.Rune[i] <= '/' && '/' <= re.Rune[i+1] {
+				return true
+			}
+		}
+	}
+	for _, sub := range re.Sub {
+		if syntaxMatchesSlash(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConstrainHandle returns a handle calling handle if the params satisfy the
+// constraints and returning false otherwise.
+func ConstrainHandle[W any](constraints Constraints, handle Handle[W]) Handle[W] {
+	if len(constraints) == 0 {
+		return handle
+	}
+	return func(ctx context.Context, reqPath string, p Params, rw W) (bool, error) {
+		if !constraints.Match(p) {
+			return false, nil
+		}
+		return handle(ctx, reqPath, p, rw)
+	}
+}
+
+// AddMuxHandler registers a new request handle with a gorilla/mux or chi style
+// pattern, see ConvertMuxPattern. The handle is only called if the params
+// satisfy the constraints of the pattern.
+//
+// Panics if the pattern cannot be converted.
+func (r *Router[W]) AddMuxHandler(pattern string, handle Handle[W], opts ...RouteOption) {
+	path, constraints, err := ConvertMuxPattern(pattern)
+	if err != nil {
+		panic(err.Error())
+	}
+	r.AddHandler(path, ConstrainHandle(constraints, handle), opts...)
+}
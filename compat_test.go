@@ -0,0 +1,103 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConvertMuxPattern(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		path        string
+		constraints map[string]string
+	}{
+		{"/user/{name}", "/user/:name", nil},
+		{"/articles/{id:[0-9]+}", "/articles/:id", map[string]string{"id": "^(?:[0-9]+)$"}},
+		{"/dates/{year:[0-9]{4}}/{slug}", "/dates/:year/:slug", map[string]string{"year": "^(?:[0-9]{4})$"}},
+		{"/files/{name:[^/]+}", "/files/:name", map[string]string{"name": "^(?:[^/]+)$"}},
+		{"/static/*", "/static/*" + MuxCatchAllName, nil},
+		{"/{ id }", "/:id", nil},
+	}
+	for _, test := range tests {
+		path, constraints, err := ConvertMuxPattern(test.pattern)
+		if err != nil {
+			t.Errorf("pattern %s: unexpected error: %v", test.pattern, err)
+			continue
+		}
+		if path != test.path {
+			t.Errorf("pattern %s: expected path %q but got %q", test.pattern, test.path, path)
+		}
+		if len(constraints) != len(test.constraints) {
+			t.Errorf("pattern %s: expected %d constraints but got %d", test.pattern, len(test.constraints), len(constraints))
+			continue
+		}
+		for name, expr := range test.constraints {
+			if re := constraints[name]; re == nil || re.String() != expr {
+				t.Errorf("pattern %s: expected constraint %s=%q but got %v", test.pattern, name, expr, re)
+			}
+		}
+	}
+}
+
+func TestConvertMuxPatternInvalid(t *testing.T) {
+	patterns := []string{
+		"/user/{name",
+		"/user/name}",
+		"/user/{}",
+		"/user/{:[0-9]+}",
+		"/user/{id:[0-9}",
+		"/user/{id:(}",
+		"/{a}-{b}",
+		"/user:name",
+		"/static/*/x",
+		"/static*",
+		"/files/{file}.json",
+		"/files/{id:[0-9]+}.json",
+		"/files/x{id}",
+		"/files/{rest:.*}",
+		"/files/{path:[a-z/]+}",
+		"/files/{path:a/b}",
+		"/files/{path:(?s).+}",
+	}
+	for _, pattern := range patterns {
+		if _, _, err := ConvertMuxPattern(pattern); err == nil {
+			t.Errorf("pattern %s: expected error", pattern)
+		}
+	}
+}
+
+func TestRouterAddMuxHandler(t *testing.T) {
+	var notFound bool
+	conf := DefaultConfig[struct{}]()
+	conf.NotFound = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		notFound = true
+		return true, nil
+	}
+	router := NewWithConfig(conf)
+	router.AddMuxHandler("/articles/{id:[0-9]+}", fakeHandler("article"))
+	router.AddMuxHandler("/static/*", fakeHandler("static"))
+
+	ctx := context.Background()
+	tests := []struct {
+		path     string
+		value    string
+		notFound bool
+	}{
+		{"/articles/42", "article", false},
+		{"/articles/abc", "", true},
+		{"/static/css/main.css", "static", false},
+	}
+	for _, test := range tests {
+		fakeHandlerValue, notFound = "", false
+		if _, err := router.Serve(ctx, test.path, struct{}{}); err != nil {
+			t.Errorf("path %s: unexpected error: %v", test.path, err)
+		}
+		if fakeHandlerValue != test.value || notFound != test.notFound {
+			t.Errorf("path %s: expected %q %v but got %q %v", test.path, test.value, test.notFound, fakeHandlerValue, notFound)
+		}
+	}
+
+	if recv := catchPanic(func() { router.AddMuxHandler("/{a}-{b}", fakeHandler("")) }); recv == nil {
+		t.Error("expected panic for unsupported pattern")
+	}
+}
@@ -0,0 +1,250 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+func TestNodeCompile(t *testing.T) {
+	leaf := &node[struct{}]{path: "c/", indices: "d", priority: 2, children: []*node[struct{}]{
+		{path: "d", handle: fakeHandler("/abc/d"), priority: 1},
+	}}
+	other := &node[struct{}]{path: "e", handle: fakeHandler("/abe"), priority: 1}
+	tree := &node[struct{}]{path: "/a", nType: root, indices: "b", priority: 3, children: []*node[struct{}]{
+		{path: "b", indices: "ec", priority: 3, children: []*node[struct{}]{other, leaf}},
+	}}
+
+	tree.compile(make(map[string]string))
+	if tree.path != "/ab" || tree.nType != root {
+		t.Fatalf("expected merged root /ab but got %q", tree.path)
+	}
+	if tree.indices != "ce" || tree.children[0] != leaf || tree.children[1] != other {
+		t.Fatalf("expected children ordered by priority but got %q", tree.indices)
+	}
+}
+
+func TestRouterCompile(t *testing.T) {
+	routes := []string{
+		"/",
+		"/hi",
+		"/b/",
+		"/search/:query",
+		"/cmd/:tool/",
+		"/cmd/:tool/:sub",
+		"/src/*filepath",
+		"/x",
+		"/x/y",
+		"/y/",
+		"/y/z",
+		"/0/:id",
+		"/0/:id/1",
+		"/1/:id/",
+		"/1/:id/2",
+		"/aa",
+		"/a/",
+		"/admin",
+		"/admin/:category",
+		"/admin/:category/:page",
+		"/doc",
+		"/doc/go_faq.html",
+		"/doc/go1.html",
+		"/user_:name",
+		"/user_:name/about",
+		"/files/:dir/*filepath",
+		"/info/:user/project/:project",
+		"/vendor/:x/*y",
+		"/exact/{$}",
+	}
+	router := New[struct{}]()
+	for _, route := range routes {
+		router.AddHandler(route, fakeHandler(route))
+	}
+	compiled := router.Compile()
+	arena := router.Compile(CompileArena())
+	if router.Compiled() || !compiled.Compiled() || !arena.Compiled() {
+		t.Fatal("expected only the compiled routers to be compiled")
+	}
+
+	// the compiled router must behave exactly like the original
+	var paths []string
+	for _, route := range routes {
+		path, err := BuildPath(route, witnessParams(mustParsePattern(t, route)))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		paths = append(paths, path, path+"/", strings.TrimSuffix(path, "/"), strings.ToUpper(path))
+	}
+	for _, path := range paths {
+		want, got := router.Lookup(path), compiled.Lookup(path)
+		if want.Pattern != got.Pattern || want.TSR != got.TSR || want.RedirectPath != got.RedirectPath || !reflect.DeepEqual(want.Params, got.Params) {
+			t.Errorf("path %s: expected %+v but got %+v", path, want, got)
+		}
+		if got := arena.Lookup(path); want.Pattern != got.Pattern || want.TSR != got.TSR || want.RedirectPath != got.RedirectPath || !reflect.DeepEqual(want.Params, got.Params) {
+			t.Errorf("path %s: arena: expected %+v but got %+v", path, want, got)
+		}
+	}
+
+	fakeHandlerValue = ""
+	if found, _ := compiled.Serve(context.Background(), "/cmd/go/vet", struct{}{}); !found || fakeHandlerValue != "/cmd/:tool/:sub" {
+		t.Errorf("compiled router did not serve the route: %v %q", found, fakeHandlerValue)
+	}
+
+	if recv := catchPanic(func() { compiled.AddHandler("/new", fakeHandler("/new")) }); recv == nil {
+		t.Error("expected panic adding a route to a compiled router")
+	}
+	if err := compiled.ApplyDelta(RouteDelta[struct{}]{}); !errors.Is(err, ErrCompiled) {
+		t.Errorf("expected ErrCompiled but got %v", err)
+	}
+	if clone := compiled.Clone(); !clone.Compiled() {
+		t.Error("expected the copy of a compiled router to be compiled")
+	} else if recv := catchPanic(func() { clone.AddHandler("/new", fakeHandler("/new")) }); recv == nil {
+		t.Error("expected panic adding a route to the copy of a compiled router")
+	}
+}
+
+// mustParsePattern parses the pattern or fails the test.
+func mustParsePattern(t *testing.T, pattern string) []patternPart {
+	parts, _, err := parsePattern(strings.TrimSuffix(pattern, anchorSuffix))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return parts
+}
+
+func BenchmarkRouterCompileArena(b *testing.B) {
+	const routeCount = 20000
+	paths := make([]string, routeCount)
+	for i := range paths {
+		paths[i] = "/api/v" + strconv.Itoa(i%3) + "/tenant" + strconv.Itoa(i%50) + "/resource" + strconv.Itoa(i%400) + "/item" + strconv.Itoa(i)
+	}
+	handle := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return true, nil
+	}
+	router := New[struct{}]()
+	if err := router.ApplyDelta(routeDeltaForPaths(paths, handle)); err != nil {
+		b.Fatal(err.Error())
+	}
+
+	for _, bc := range []struct {
+		name string
+		opts []CompileOption
+	}{{"heap", nil}, {"arena", []CompileOption{CompileArena()}}} {
+		compiled := router.Compile(bc.opts...)
+		b.Run(bc.name+"/lookup", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _, _ = compiled.LookupPath(paths[(i*7919)%len(paths)])
+			}
+		})
+		b.Run(bc.name+"/gc", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+			}
+			runtime.KeepAlive(compiled)
+		})
+	}
+}
+
+func TestRouterOptimize(t *testing.T) {
+	routes := []string{
+		"/",
+		"/search/:query",
+		"/cmd/:tool/:sub",
+		"/src/*filepath",
+		"/admin/:category/:page",
+		"/doc/go_faq.html",
+		"/doc/go1.html",
+		"/user_:name/about",
+		"/exact/{$}",
+	}
+	later := []string{"/doc/go2.html", "/cmd/:tool/", "/admin", "/a"}
+
+	for _, layered := range []bool{false, true} {
+		conf := DefaultConfig[struct{}]()
+		conf.Fallthrough = layered
+		router := NewWithConfig(conf)
+		reference := NewWithConfig(conf)
+		for _, route := range routes {
+			router.AddHandler(route, fakeHandler(route))
+			reference.AddHandler(route, fakeHandler(route))
+		}
+		version := router.Version()
+		router.Optimize()
+		if router.Compiled() || router.Version() != version {
+			t.Fatal("expected Optimize not to compile the router or change the version")
+		}
+
+		// routes can be added after optimizing
+		for _, route := range later {
+			router.AddHandler(route, fakeHandler(route))
+			reference.AddHandler(route, fakeHandler(route))
+		}
+		router.Optimize()
+
+		for _, route := range append(routes, later...) {
+			path, err := BuildPath(route, witnessParams(mustParsePattern(t, route)))
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			for _, path := range []string{path, path + "/", strings.TrimSuffix(path, "/"), strings.ToUpper(path)} {
+				want, got := reference.Lookup(path), router.Lookup(path)
+				if want.Pattern != got.Pattern || want.TSR != got.TSR || want.RedirectPath != got.RedirectPath || !reflect.DeepEqual(want.Params, got.Params) {
+					t.Errorf("path %s: expected %+v but got %+v", path, want, got)
+				}
+			}
+		}
+		if want, got := reference.RoutesWithPrefix("/doc/"), router.RoutesWithPrefix("/doc/"); !reflect.DeepEqual(want, got) {
+			t.Errorf("expected routes with prefix %v but got %v", want, got)
+		}
+	}
+
+	// compiled routers are already optimized
+	compiled := New[struct{}]().Compile()
+	compiled.Optimize()
+}
+
+func TestRouterCompileParamKeys(t *testing.T) {
+	routes := []string{"/user/:id", "/org/:org/team/:id", "/blob/:org/*path", "/tree/*path"}
+	router := New[struct{}]()
+	for _, route := range routes {
+		router.AddHandler(route, fakeHandler(route))
+	}
+	paths := []string{"/user/1", "/org/a/team/2", "/blob/b/c", "/tree/d"}
+
+	keyData := func(r *Router[struct{}]) map[string]map[*byte]struct{} {
+		out := make(map[string]map[*byte]struct{})
+		for _, path := range paths {
+			for _, p := range r.Lookup(path).Params {
+				if out[p.Key] == nil {
+					out[p.Key] = make(map[*byte]struct{})
+				}
+				out[p.Key][unsafe.StringData(p.Key)] = struct{}{}
+			}
+		}
+		return out
+	}
+	if keys := keyData(router); len(keys["id"]) != 2 {
+		t.Fatalf("expected the keys of the uncompiled router not to be shared, got %v", keys)
+	}
+
+	compiled := router.Compile()
+	router.Optimize()
+	for _, r := range []*Router[struct{}]{compiled, router} {
+		keys := keyData(r)
+		if len(keys) != 3 {
+			t.Fatalf("expected 3 param keys, got %v", keys)
+		}
+		for key, data := range keys {
+			if len(data) != 1 {
+				t.Errorf("expected the %s keys to share a backing string, got %d strings", key, len(data))
+			}
+		}
+	}
+}
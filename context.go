@@ -0,0 +1,52 @@
+package pathrouter
+
+import "context"
+
+// paramsCtxKey is the context key for the Params.
+type paramsCtxKey struct{}
+
+// ContextWithParams returns a child context with the Params attached.
+//
+// The Params are not copied: if they were passed to a Handle they are only
+// valid until the handle returns, see Params.Clone.
+func ContextWithParams(ctx context.Context, ps Params) context.Context {
+	return context.WithValue(ctx, paramsCtxKey{}, ps)
+}
+
+// ParamsFromContext returns the Params attached to the context, if any.
+func ParamsFromContext(ctx context.Context) Params {
+	ps, _ := ctx.Value(paramsCtxKey{}).(Params)
+	return ps
+}
+
+// patternCtxKey is the context key for the matched route pattern.
+type patternCtxKey struct{}
+
+// ContextWithPattern returns a child context with the route pattern attached.
+func ContextWithPattern(ctx context.Context, pattern string) context.Context {
+	return context.WithValue(ctx, patternCtxKey{}, pattern)
+}
+
+// PatternFromContext returns the route pattern attached to the context, if any.
+//
+// The pattern is the one passed to AddHandler, for example /user/:name.
+func PatternFromContext(ctx context.Context) string {
+	pattern, _ := ctx.Value(patternCtxKey{}).(string)
+	return pattern
+}
+
+// localeCtxKey is the context key for the locale of the matched alias.
+type localeCtxKey struct{}
+
+// ContextWithLocale returns a child context with the locale attached.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeCtxKey{}, locale)
+}
+
+// LocaleFromContext returns the locale attached to the context, if any.
+//
+// Serve attaches the locale of a matched localized alias, see AddLocaleAlias.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeCtxKey{}).(string)
+	return locale
+}
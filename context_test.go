@@ -0,0 +1,71 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParamsFromContext(t *testing.T) {
+	ctx := context.Background()
+	if ps := ParamsFromContext(ctx); ps != nil {
+		t.Fatalf("expected no params, got %v", ps)
+	}
+
+	want := Params{Param{"name", "gopher"}}
+	if got := ParamsFromContext(ContextWithParams(ctx, want)).ByName("name"); got != "gopher" {
+		t.Fatalf("wrong param value: %q", got)
+	}
+}
+
+func TestRouterParamsInContext(t *testing.T) {
+	for _, inContext := range []bool{false, true} {
+		conf := DefaultConfig[struct{}]()
+		conf.ParamsInContext = inContext
+
+		var got string
+		router := NewWithConfig(conf)
+		router.AddHandler("/user/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			got = ParamsFromContext(ctx).ByName("name")
+			return true, nil
+		})
+
+		if _, err := router.Serve(context.Background(), "/user/gopher", struct{}{}); err != nil {
+			t.Fatal(err.Error())
+		}
+		want := ""
+		if inContext {
+			want = "gopher"
+		}
+		if got != want {
+			t.Errorf("ParamsInContext=%v: expected %q but got %q", inContext, want, got)
+		}
+	}
+}
+
+func TestRouterPatternInContext(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.PatternInContext = true
+	conf.Fallthrough = true
+
+	var got []string
+	handler := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		got = append(got, PatternFromContext(ctx))
+		return false, nil
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", handler)
+	router.AddHandler("/user/*rest", handler)
+	router.AddHandler("/files/{$}", handler)
+
+	if _, err := router.Serve(context.Background(), "/user/gopher", struct{}{}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := router.Serve(context.Background(), "/files/", struct{}{}); err != nil {
+		t.Fatal(err.Error())
+	}
+	want := []string{"/user/:name", "/user/*rest", "/files/{$}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected patterns %v but got %v", want, got)
+	}
+}
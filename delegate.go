@@ -0,0 +1,49 @@
+package pathrouter
+
+import (
+	"context"
+	"strings"
+)
+
+// delegateParam is the name of the catch-all param of a DelegatePrefix route.
+const delegateParam = "rest"
+
+// DelegatePrefix returns a handle serving the remainder of the path after the
+// prefix with the sub-router. Register it with the catch-all pattern:
+//
+//	router.AddHandler("/t/:tenant/*rest", DelegatePrefix("/t/:tenant", sub))
+//
+// The remainder is the value of the rest param. If the handle is registered
+// without a rest param, the prefix is stripped from the path instead and the
+// handle declines paths without the prefix.
+//
+// The params of the route other than rest are attached to the context passed
+// to the sub-router, see ParamsFromContext. If the sub-router sets
+// RouterConfig.ParamsInContext the params of the sub-route replace them.
+//
+// Unlike a static mount, the routes of the sub-router can change at any time.
+func DelegatePrefix[W any](prefix string, sub *Router[W]) Handle[W] {
+	return func(ctx context.Context, reqPath string, p Params, rw W) (bool, error) {
+		rest, ok := p.Get(delegateParam)
+		if !ok {
+			if rest, ok = strings.CutPrefix(reqPath, prefix); !ok {
+				return false, nil
+			}
+		}
+		if outer := delegateParams(p); len(outer) != 0 {
+			ctx = ContextWithParams(ctx, outer)
+		}
+		return sub.Serve(ctx, rest, rw)
+	}
+}
+
+// delegateParams returns the params without the rest param.
+func delegateParams(p Params) Params {
+	out := make(Params, 0, len(p))
+	for _, param := range p {
+		if param.Key != delegateParam {
+			out = append(out, param)
+		}
+	}
+	return out
+}
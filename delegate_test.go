@@ -0,0 +1,56 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDelegatePrefix(t *testing.T) {
+	var tenant string
+	sub := New[struct{}]()
+	sub.AddHandler("/users/:id", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		fakeHandlerValue = "user:" + p.ByName("id")
+		tenant = ParamsFromContext(ctx).ByName("tenant")
+		return true, nil
+	})
+	sub.AddHandler("/", fakeHandler("index"))
+
+	router := New[struct{}]()
+	router.AddHandler("/t/:tenant/*rest", DelegatePrefix("/t/:tenant", sub))
+
+	tests := []struct {
+		path   string
+		found  bool
+		want   string
+		tenant string
+	}{
+		{"/t/acme/users/1", true, "user:1", "acme"},
+		{"/t/acme/", true, "index", ""},
+		{"/t/acme/missing", false, "", ""},
+	}
+	for _, tt := range tests {
+		fakeHandlerValue, tenant = "", ""
+		found, err := router.Serve(context.Background(), tt.path, struct{}{})
+		if err != nil || found != tt.found {
+			t.Errorf("%s: expected found=%v, got found=%v err=%v", tt.path, tt.found, found, err)
+		}
+		if fakeHandlerValue != tt.want || tenant != tt.tenant {
+			t.Errorf("%s: expected %q tenant %q, got %q tenant %q", tt.path, tt.want, tt.tenant, fakeHandlerValue, tenant)
+		}
+	}
+
+	// routes added to the sub-router later are served
+	sub.AddHandler("/late", fakeHandler("late"))
+	if found, _ := router.Serve(context.Background(), "/t/acme/late", struct{}{}); !found || fakeHandlerValue != "late" {
+		t.Errorf("expected late route, got found=%v value=%q", found, fakeHandlerValue)
+	}
+
+	// without a rest param the prefix is stripped
+	handle := DelegatePrefix("/static", sub)
+	if found, _ := handle(context.Background(), "/static/late", nil, struct{}{}); !found {
+		t.Error("expected stripped prefix to be served")
+	}
+	if found, _ := handle(context.Background(), "/other/late", nil, struct{}{}); found {
+		t.Error("expected path without the prefix to be declined")
+	}
+}
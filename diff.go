@@ -0,0 +1,96 @@
+package pathrouter
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// RouteDiff contains the differences between the routes of two routers, see
+// Diff. The patterns are sorted.
+type RouteDiff struct {
+	// Added contains the patterns of the routes only registered with the other
+	// router.
+	Added []string
+	// Removed contains the patterns of the routes only registered with the
+	// receiver.
+	Removed []string
+	// Changed contains the routes registered with both routers which differ.
+	Changed []RouteChange
+}
+
+// Empty checks if the routers have the same routes.
+func (d RouteDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// RouteChange is a route registered with both routers with a different
+// definition, see Diff.
+type RouteChange struct {
+	// Pattern is the pattern of the route.
+	Pattern string
+	// Fields contains the names of the differing fields in order: name,
+	// metadata, tags, defaults, target and timeout. The target is the target of a
+	// redirect, rewrite or alias route.
+	Fields []string
+}
+
+// Diff compares the routes of the router with the routes of the other router,
+// for example to review the changes before promoting a route table.
+//
+// Routes are matched by pattern. Handles are not compared: functions cannot be
+// compared reliably, for example the closures created by a middleware are
+// equal whatever handle they wrap. Name the handlers to tell them apart, see
+// WithName. Metadata is compared with reflect.DeepEqual.
+func (r *Router[W]) Diff(other *Router[W]) RouteDiff {
+	var diff RouteDiff
+	a, b := r.load().routes, other.load().routes
+	for pattern, art := range a {
+		brt, ok := b[pattern]
+		if !ok {
+			diff.Removed = append(diff.Removed, pattern)
+			continue
+		}
+		if fields := diffRoute(art, brt); len(fields) != 0 {
+			diff.Changed = append(diff.Changed, RouteChange{Pattern: pattern, Fields: fields})
+		}
+	}
+	for pattern := range b {
+		if _, ok := a[pattern]; !ok {
+			diff.Added = append(diff.Added, pattern)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Pattern < diff.Changed[j].Pattern
+	})
+	return diff
+}
+
+// diffRoute returns the names of the fields differing between the routes, see
+// RouteChange.
+func diffRoute[W any](a, b *route[W]) []string {
+	var fields []string
+	if a.name != b.name {
+		fields = append(fields, "name")
+	}
+	if !reflect.DeepEqual(a.metadata, b.metadata) {
+		fields = append(fields, "metadata")
+	}
+	if !slices.Equal(a.tags, b.tags) {
+		fields = append(fields, "tags")
+	}
+	if !maps.Equal(a.defaults, b.defaults) {
+		fields = append(fields, "defaults")
+	}
+	if a.redirect != b.redirect || a.rewrite != b.rewrite || a.canonical != b.canonical || a.locale != b.locale {
+		fields = append(fields, "target")
+	}
+	if a.timeout != b.timeout {
+		fields = append(fields, "timeout")
+	}
+	return fields
+}
@@ -0,0 +1,62 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func otherHandle(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+	return false, nil
+}
+
+func TestRouterDiff(t *testing.T) {
+	build := func(staging bool) *Router[struct{}] {
+		router := New[struct{}]()
+		router.AddHandler("/same", fakeHandler("same"), WithName("same"), WithMetadata("a", []any{1.0}))
+		router.AddHandler("/user/:name", fakeHandler("user"), WithTags("public"))
+		router.AddRedirect("/old", "/same")
+		if staging {
+			router.AddHandler("/new", fakeHandler("new"))
+			router.AddHandler("/handle", otherHandle, WithName("other"))
+			router.Group("/admin", Recover[struct{}]()).AddHandler("/stats", otherHandle, WithName("other"))
+			router.AddHandler("/meta", fakeHandler("meta"), WithName("meta2"), WithMetadata("b", 2))
+			router.AddHandler("/timeout", fakeHandler("timeout"), WithTimeout(time.Second), WithTags("internal"))
+			router.AddRedirect("/moved", "/new")
+		} else {
+			router.AddHandler("/gone", fakeHandler("gone"))
+			router.AddHandler("/handle", fakeHandler("handle"), WithName("handle"))
+			router.Group("/admin", Recover[struct{}]()).AddHandler("/stats", fakeHandler("stats"), WithName("stats"))
+			router.AddHandler("/meta", fakeHandler("meta"), WithName("meta"), WithMetadata("b", 1))
+			router.AddHandler("/timeout", fakeHandler("timeout"))
+			router.AddRedirect("/moved", "/same")
+		}
+		return router
+	}
+	prod, staging := build(false), build(true)
+
+	want := RouteDiff{
+		Added:   []string{"/new"},
+		Removed: []string{"/gone"},
+		Changed: []RouteChange{
+			{Pattern: "/admin/stats", Fields: []string{"name"}},
+			{Pattern: "/handle", Fields: []string{"name"}},
+			{Pattern: "/meta", Fields: []string{"name", "metadata"}},
+			{Pattern: "/moved", Fields: []string{"target"}},
+			{Pattern: "/timeout", Fields: []string{"tags", "timeout"}},
+		},
+	}
+	if diff := prod.Diff(staging); !reflect.DeepEqual(diff, want) {
+		t.Errorf("unexpected diff:\n%+v\nexpected:\n%+v", diff, want)
+	}
+	if diff := staging.Diff(prod); !reflect.DeepEqual(diff.Added, want.Removed) || !reflect.DeepEqual(diff.Removed, want.Added) {
+		t.Errorf("expected the reverse diff to swap added and removed, got %+v", diff)
+	}
+	if diff := prod.Diff(build(false)); !diff.Empty() {
+		t.Errorf("expected routers built the same way not to differ, got %+v", diff)
+	}
+	if diff := prod.Diff(prod.Clone()); !diff.Empty() {
+		t.Errorf("expected a clone not to differ, got %+v", diff)
+	}
+}
@@ -0,0 +1,130 @@
+package pathrouter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// String returns the name of the node type.
+func (t nodeType) String() string {
+	switch t {
+	case static:
+		return "static"
+	case root:
+		return "root"
+	case param:
+		return "param"
+	case catchAll:
+		return "catchAll"
+	default:
+		return "nodeType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// DumpTree writes a human-readable dump of the route trees to w.
+//
+// Each node is written on a line indented by its depth with the node path, the
+// node type and priority, and the pattern of the route registered at the node.
+// Intended for debugging: the format is not stable.
+func (r *Router[W]) DumpTree(w io.Writer) error {
+	for i, tree := range r.load().trees {
+		if _, err := fmt.Fprintf(w, "tree %d\n", i); err != nil {
+			return err
+		}
+		if err := tree.dump(w, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dump writes the node and its children to w, see DumpTree.
+func (n *node[W]) dump(w io.Writer, depth int) error {
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(strconv.Quote(n.path))
+	sb.WriteString(" ")
+	sb.WriteString(n.nType.String())
+	sb.WriteString(" priority=")
+	sb.WriteString(strconv.FormatUint(uint64(n.priority), 10))
+	if n.indices != "" {
+		sb.WriteString(" indices=")
+		sb.WriteString(strconv.Quote(n.indices))
+	}
+	if n.wildChild {
+		sb.WriteString(" wildChild")
+	}
+	if n.handle != nil {
+		sb.WriteString(" -> ")
+		if n.route != nil {
+			sb.WriteString(n.route.pattern)
+		} else {
+			sb.WriteString("(handle)")
+		}
+	}
+	sb.WriteString("\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return err
+	}
+
+	for _, child := range n.children {
+		if err := child.dump(w, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportDOT writes the route trees to w as a Graphviz DOT graph.
+//
+// Static nodes are drawn as boxes, params as blue ellipses and catch-alls as
+// orange ellipses. Nodes with a registered route have a double border and the
+// route pattern in the label. Each tree is drawn as a cluster.
+func (r *Router[W]) ExportDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph pathrouter {\n")
+	sb.WriteString("\tnode [fontname=\"monospace\"];\n")
+	var id int
+	for i, tree := range r.load().trees {
+		sb.WriteString("\tsubgraph cluster_" + strconv.Itoa(i) + " {\n")
+		sb.WriteString("\t\tlabel=\"tree " + strconv.Itoa(i) + "\";\n")
+		tree.writeDOT(&sb, &id)
+		sb.WriteString("\t}\n")
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeDOT writes the node and its children as DOT statements, see ExportDOT.
+// id is the next free node id. Returns the id of the node.
+func (n *node[W]) writeDOT(sb *strings.Builder, id *int) int {
+	nodeID := *id
+	*id++
+
+	label := n.path
+	if n.route != nil {
+		label += "\n" + n.route.pattern
+	}
+	attrs := "label=" + strconv.Quote(label)
+	switch n.nType {
+	case param:
+		attrs += ", shape=ellipse, style=filled, fillcolor=lightblue"
+	case catchAll:
+		attrs += ", shape=ellipse, style=filled, fillcolor=orange"
+	default:
+		attrs += ", shape=box"
+	}
+	if n.handle != nil {
+		attrs += ", peripheries=2"
+	}
+	sb.WriteString("\t\tn" + strconv.Itoa(nodeID) + " [" + attrs + "];\n")
+
+	for _, child := range n.children {
+		childID := child.writeDOT(sb, id)
+		sb.WriteString("\t\tn" + strconv.Itoa(nodeID) + " -> n" + strconv.Itoa(childID) + ";\n")
+	}
+	return nodeID
+}
@@ -0,0 +1,63 @@
+package pathrouter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRouterDumpTree(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	router.AddHandler("/users", fakeHandler("/users"))
+	router.AddHandler("/src/*filepath", fakeHandler("/src/*filepath"))
+
+	var sb strings.Builder
+	if err := router.DumpTree(&sb); err != nil {
+		t.Fatal(err.Error())
+	}
+	want := `tree 0
+  "/" root priority=3 indices="us"
+    "user" static priority=2 indices="/s"
+      "/" static priority=1 wildChild
+        ":name" param priority=1 -> /user/:name
+      "s" static priority=1 -> /users
+    "src" static priority=1 indices="/"
+      "" catchAll priority=1 wildChild
+        "/*filepath" catchAll priority=1 -> /src/*filepath
+`
+	if got := sb.String(); got != want {
+		t.Errorf("unexpected dump:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func TestRouterExportDOT(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	router.AddHandler("/src/*filepath", fakeHandler("/src/*filepath"))
+
+	var sb strings.Builder
+	if err := router.ExportDOT(&sb); err != nil {
+		t.Fatal(err.Error())
+	}
+	want := `digraph pathrouter {
+	node [fontname="monospace"];
+	subgraph cluster_0 {
+		label="tree 0";
+		n0 [label="/", shape=box];
+		n1 [label="user/", shape=box];
+		n2 [label=":name\n/user/:name", shape=ellipse, style=filled, fillcolor=lightblue, peripheries=2];
+		n1 -> n2;
+		n0 -> n1;
+		n3 [label="src", shape=box];
+		n4 [label="", shape=ellipse, style=filled, fillcolor=orange];
+		n5 [label="/*filepath\n/src/*filepath", shape=ellipse, style=filled, fillcolor=orange, peripheries=2];
+		n4 -> n5;
+		n3 -> n4;
+		n0 -> n3;
+	}
+}
+`
+	if got := sb.String(); got != want {
+		t.Errorf("unexpected DOT:\n%s\nexpected:\n%s", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package pathrouter
+
+// SetRouteEnabled enables or disables the route registered with the pattern.
+// Returns false if no route is registered with the pattern, see HasRoute.
+//
+// Disabled routes behave as if they were not registered: the next matching
+// route is tried or the NotFound handler is called, and paths are not
+// redirected to them. The route table is not changed: toggling a route is
+// cheap and safe while serving requests. Aliases are enabled and disabled with
+// the canonical route, see AddAlias. Routes are enabled when registered,
+// including when replaced, for example with ApplyDelta. The enabled state is
+// shared with the routers returned by Clone and Compile.
+func (r *Router[W]) SetRouteEnabled(pattern string, enabled bool) bool {
+	rt := r.routeByPattern(r.load(), pattern)
+	if rt == nil {
+		return false
+	}
+	if !enabled {
+		r.anyDisabled.Store(true)
+	}
+	rt.disabled.Store(!enabled)
+	return true
+}
+
+// RouteEnabled checks if the route registered with the pattern is enabled.
+// Returns false if no route is registered with the pattern.
+func (r *Router[W]) RouteEnabled(pattern string) bool {
+	rt := r.routeByPattern(r.load(), pattern)
+	return rt != nil && rt.enabled()
+}
+
+// enabled checks if the route is enabled, see SetRouteEnabled.
+// Handles registered without a route are always enabled.
+func (rt *route[W]) enabled() bool {
+	return rt == nil || rt.disabled == nil || !rt.disabled.Load()
+}
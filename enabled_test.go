@@ -0,0 +1,78 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRouterSetRouteEnabled(t *testing.T) {
+	for _, conf := range []RouterConfig[struct{}]{
+		DefaultConfig[struct{}](),
+		{RedirectTrailingSlash: true, StaticFastPath: true},
+		{RedirectTrailingSlash: true, Fallthrough: true},
+	} {
+		var notFound bool
+		conf.NotFound = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			notFound = true
+			return false, nil
+		}
+		router := NewWithConfig(conf)
+		router.AddHandler("/beta/feature", fakeHandler("feature"))
+		router.AddHandler("/dir/", fakeHandler("dir"))
+		if conf.Fallthrough {
+			router.AddHandler("/beta/*rest", fakeHandler("rest"))
+		}
+
+		if router.SetRouteEnabled("/missing", false) {
+			t.Error("expected missing route not to be found")
+		}
+		if !router.SetRouteEnabled("/beta/feature", false) || !router.SetRouteEnabled("/dir/", false) {
+			t.Fatal("expected routes to be found")
+		}
+		if router.RouteEnabled("/beta/feature") {
+			t.Error("expected route to be disabled")
+		}
+
+		ctx := context.Background()
+		fakeHandlerValue, notFound = "", false
+		_, _ = router.Serve(ctx, "/beta/feature", struct{}{})
+		if conf.Fallthrough {
+			if fakeHandlerValue != "rest" {
+				t.Errorf("expected fallthrough to the next route, got %q", fakeHandlerValue)
+			}
+		} else if !notFound || fakeHandlerValue != "" {
+			t.Errorf("expected not found, got %q", fakeHandlerValue)
+		}
+		if res := router.Lookup("/dir"); res.Found() || res.RedirectPath != "" {
+			t.Errorf("expected no redirect to disabled route, got %+v", res)
+		}
+		if handle, _, tsr := router.LookupPath("/dir/"); handle != nil || tsr {
+			t.Error("expected disabled route not to be found by LookupPath")
+		}
+		if problems := router.SelfCheck(); len(problems) != 0 {
+			t.Errorf("expected disabled routes to be checked as enabled, got %v", problems)
+		}
+
+		router.SetRouteEnabled("/beta/feature", true)
+		fakeHandlerValue = ""
+		if found, _ := router.Serve(ctx, "/beta/feature", struct{}{}); !found || fakeHandlerValue != "feature" {
+			t.Errorf("expected enabled route to be served, got %q", fakeHandlerValue)
+		}
+	}
+}
+
+func TestRouterCloneEnabled(t *testing.T) {
+	router := NewWithConfig(RouterConfig[struct{}]{RedirectTrailingSlash: true})
+	router.AddHandler("/users", fakeHandler("users"))
+	clone := router.Clone()
+
+	clone.SetRouteEnabled("/users", false)
+	for name, r := range map[string]*Router[struct{}]{"router": router, "clone": clone} {
+		if r.RouteEnabled("/users") {
+			t.Errorf("%s: expected the route to be disabled", name)
+		}
+		if res := r.Lookup("/users/"); res.RedirectPath != "" {
+			t.Errorf("%s: expected no redirect to the disabled route, got %q", name, res.RedirectPath)
+		}
+	}
+}
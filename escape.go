@@ -0,0 +1,93 @@
+package pathrouter
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidEscape is returned if a path contains an invalid %XX escape.
+	ErrInvalidEscape = errors.New("invalid escape in path")
+	// ErrEncodedSlash is returned if a path contains an encoded slash (%2F)
+	// and the EncodedSlashPolicy is EncodedSlashReject.
+	ErrEncodedSlash = errors.New("encoded slash in path")
+)
+
+// EncodedSlashPolicy controls how an encoded slash (%2F) is unescaped.
+type EncodedSlashPolicy int
+
+const (
+	// EncodedSlashKeep keeps encoded slashes as %2F so that they do not
+	// separate path segments. Encoded percent signs are kept as %25 so that a
+	// decoded %2F is always an encoded slash: %252F stays %252F. Other escapes
+	// are decoded.
+	EncodedSlashKeep EncodedSlashPolicy = iota
+	// EncodedSlashDecode decodes encoded slashes to '/' so they separate path
+	// segments like an unencoded slash.
+	EncodedSlashDecode
+	// EncodedSlashReject rejects paths containing an encoded slash.
+	EncodedSlashReject
+)
+
+// UnescapePath decodes the %XX escapes in a path.
+//
+// Encoded slashes are handled according to the policy. Unlike
+// url.PathUnescape, '+' is not treated specially.
+func UnescapePath(p string, slash EncodedSlashPolicy) (string, error) {
+	n := strings.IndexByte(p, '%')
+	if n < 0 {
+		return p, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(p))
+	b.WriteString(p[:n])
+	for i := n; i < len(p); i++ {
+		c := p[i]
+		if c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+2 >= len(p) || !isHex(p[i+1]) || !isHex(p[i+2]) {
+			return "", errors.Wrapf(ErrInvalidEscape, "%q", p[i:min(i+3, len(p))])
+		}
+
+		c = unhex(p[i+1])<<4 | unhex(p[i+2])
+		if c == '%' && slash == EncodedSlashKeep {
+			b.WriteString(p[i : i+3])
+			i += 2
+			continue
+		}
+		if c == '/' {
+			switch slash {
+			case EncodedSlashReject:
+				return "", ErrEncodedSlash
+			case EncodedSlashKeep:
+				b.WriteString(p[i : i+3])
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(c)
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// isHex checks if c is a hex digit.
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// unhex returns the value of the hex digit c.
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
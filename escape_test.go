@@ -0,0 +1,74 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestUnescapePath(t *testing.T) {
+	tests := []struct {
+		path   string
+		slash  EncodedSlashPolicy
+		result string
+		err    error
+	}{
+		{"/plain/path", EncodedSlashKeep, "/plain/path", nil},
+		{"/caf%C3%A9/a%20b", EncodedSlashKeep, "/café/a b", nil},
+		{"/a+b/%2b", EncodedSlashKeep, "/a+b/+", nil},
+		{"/a%2Fb/c", EncodedSlashKeep, "/a%2Fb/c", nil},
+		{"/a%2fb/c", EncodedSlashDecode, "/a/b/c", nil},
+		{"/a%252Fb/c", EncodedSlashKeep, "/a%252Fb/c", nil},
+		{"/100%25", EncodedSlashKeep, "/100%25", nil},
+		{"/a%252Fb/c", EncodedSlashDecode, "/a%2Fb/c", nil},
+		{"/a%2Fb/c", EncodedSlashReject, "", ErrEncodedSlash},
+		{"/a%2", EncodedSlashKeep, "", ErrInvalidEscape},
+		{"/a%zz", EncodedSlashKeep, "", ErrInvalidEscape},
+		{"/a%", EncodedSlashKeep, "", ErrInvalidEscape},
+	}
+	for _, test := range tests {
+		result, err := UnescapePath(test.path, test.slash)
+		if result != test.result || !errors.Is(err, test.err) {
+			t.Errorf("UnescapePath(%q, %v) = %q, %v, want %q, %v", test.path, test.slash, result, err, test.result, test.err)
+		}
+	}
+}
+
+func TestRouterUnescapePath(t *testing.T) {
+	for _, slash := range []EncodedSlashPolicy{EncodedSlashKeep, EncodedSlashDecode, EncodedSlashReject} {
+		conf := DefaultConfig[struct{}]()
+		conf.UnescapePath = true
+		conf.EncodedSlash = slash
+
+		var got string
+		router := NewWithConfig(conf)
+		router.AddHandler("/file/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			got = p.ByName("name")
+			return true, nil
+		})
+
+		ctx := context.Background()
+		got = ""
+		if found, err := router.Serve(ctx, "/file/a%20b", struct{}{}); !found || err != nil || got != "a b" {
+			t.Errorf("policy %v: expected decoded param, got %q found=%v err=%v", slash, got, found, err)
+		}
+
+		got = ""
+		found, err := router.Serve(ctx, "/file/a%2Fb", struct{}{})
+		switch slash {
+		case EncodedSlashKeep:
+			if !found || got != "a%2Fb" {
+				t.Errorf("policy %v: expected kept slash, got %q found=%v err=%v", slash, got, found, err)
+			}
+		case EncodedSlashDecode:
+			if found || err != nil {
+				t.Errorf("policy %v: expected not found, got %q found=%v err=%v", slash, got, found, err)
+			}
+		case EncodedSlashReject:
+			if found || !errors.Is(err, ErrEncodedSlash) {
+				t.Errorf("policy %v: expected error, got %q found=%v err=%v", slash, got, found, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package pathrouter
+
+import (
+	"io/fs"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RegisterFS registers a route per file and directory in fsys.
+//
+// The route of a file is the root pattern joined with the file path, for
+// example /static/css/main.css for css/main.css with the root pattern /static.
+// Directories, including the root directory, are registered as trailing slash
+// routes like /static/css/. build is called with the path in fsys, "." for the
+// root directory, and returns the handle or nil to skip the file or directory.
+//
+// The root pattern may contain named params. File names containing the ':', '*',
+// '{' or '}' wildcard characters are rejected. If any of the routes are invalid
+// or conflict an error is returned and none of the routes are registered.
+func (r *Router[W]) RegisterFS(fsys fs.FS, rootPattern string, build func(path string) Handle[W]) error {
+	sep := "/"
+	if customSeparator(r.conf.Separator) {
+		sep = string(r.conf.Separator)
+	}
+	root := strings.TrimSuffix(rootPattern, sep)
+
+	var rts []*route[W]
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.ContainsAny(p, ":*{}") {
+			return errors.Errorf("unsupported wildcard character in file path '%s'", p)
+		}
+
+		// patterns with a custom separator do not start with the separator
+		pattern := root
+		if p != "." {
+			if pattern != "" || !customSeparator(r.conf.Separator) {
+				pattern += sep
+			}
+			pattern += strings.ReplaceAll(p, "/", sep)
+		}
+		if d.IsDir() && (pattern != "" || !customSeparator(r.conf.Separator)) {
+			pattern += sep
+		}
+
+		handle := build(p)
+		if handle == nil {
+			return nil
+		}
+		rt, err := newRoute(r.conf.UnicodeForm.Normalize(pattern), handle, r.conf.Separator)
+		if err != nil {
+			return err
+		}
+		rts = append(rts, rt)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.addRoutes(rts)
+}
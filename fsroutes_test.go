@@ -0,0 +1,88 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRouterRegisterFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":        {Data: []byte("index")},
+		"css/main.css":      {Data: []byte("css")},
+		"blog/post-1.html":  {Data: []byte("post")},
+		"blog/drafts/x.txt": {Data: []byte("draft")},
+	}
+	build := func(path string) Handle[struct{}] {
+		if path == "blog/drafts" || path == "blog/drafts/x.txt" {
+			return nil
+		}
+		return fakeHandler(path)
+	}
+
+	tests := []struct {
+		root     string
+		sep      byte
+		patterns []string
+		path     string
+		value    string
+	}{{
+		root:     "/static",
+		patterns: []string{"/static/", "/static/blog/", "/static/blog/post-1.html", "/static/css/", "/static/css/main.css", "/static/index.html"},
+		path:     "/static/css/main.css",
+		value:    "css/main.css",
+	}, {
+		root:     "/sites/:site/",
+		patterns: []string{"/sites/:site/", "/sites/:site/blog/", "/sites/:site/blog/post-1.html", "/sites/:site/css/", "/sites/:site/css/main.css", "/sites/:site/index.html"},
+		path:     "/sites/example/blog/",
+		value:    "blog",
+	}, {
+		root:     "",
+		patterns: []string{"/", "/blog/", "/blog/post-1.html", "/css/", "/css/main.css", "/index.html"},
+		path:     "/",
+		value:    ".",
+	}, {
+		root:     "",
+		sep:      '.',
+		patterns: []string{"", "blog.", "blog.post-1.html", "css.", "css.main.css", "index.html"},
+		path:     "blog.post-1.html",
+		value:    "blog/post-1.html",
+	}}
+	for _, test := range tests {
+		router := NewWithConfig(RouterConfig[struct{}]{Separator: test.sep})
+		if err := router.RegisterFS(fsys, test.root, build); err != nil {
+			t.Errorf("root %q: unexpected error: %v", test.root, err)
+			continue
+		}
+
+		var patterns []string
+		for _, def := range router.Routes() {
+			patterns = append(patterns, def.Pattern)
+		}
+		if !reflect.DeepEqual(patterns, test.patterns) {
+			t.Errorf("root %q: expected patterns %v but got %v", test.root, test.patterns, patterns)
+		}
+
+		fakeHandlerValue = ""
+		if found, err := router.Serve(context.Background(), test.path, struct{}{}); !found || err != nil || fakeHandlerValue != test.value {
+			t.Errorf("root %q: expected %s to serve %q, got found=%v err=%v value=%q", test.root, test.path, test.value, found, err, fakeHandlerValue)
+		}
+	}
+}
+
+func TestRouterRegisterFSInvalid(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/static/index.html", fakeHandler("existing"))
+
+	build := func(path string) Handle[struct{}] { return fakeHandler(path) }
+	if err := router.RegisterFS(fstest.MapFS{"a.txt": {}, "index.html": {}}, "/static", build); err == nil {
+		t.Error("expected conflict error")
+	}
+	if err := router.RegisterFS(fstest.MapFS{"a:b.txt": {}}, "/files", build); err == nil {
+		t.Error("expected error for wildcard character")
+	}
+	if routes := router.Routes(); len(routes) != 1 {
+		t.Errorf("expected routes to be unchanged, got %d routes", len(routes))
+	}
+}
@@ -0,0 +1,124 @@
+package pathrouter
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+// fuzzRoutes are the routes registered for FuzzLookup.
+var fuzzRoutes = []string{
+	"/",
+	"/cmd/:tool/:sub",
+	"/cmd/:tool/",
+	"/src/*filepath",
+	"/search/",
+	"/search/:query",
+	"/user_:name",
+	"/user_:name/about",
+	"/files/:dir/*filepath",
+	"/doc/go_faq.html",
+	"/info/:user/public",
+	"/info/:user/project/:project",
+	"/α/:β/γ",
+	"/dir/{$}",
+}
+
+func FuzzLookup(f *testing.F) {
+	for _, route := range fuzzRoutes {
+		f.Add(route)
+	}
+	f.Add("/cmd/test/3")
+	f.Add("/src/some/file.png")
+	f.Add("/SEARCH/someth!ng+in+ünìcodé/")
+	f.Add("/user_gopher/about/")
+	f.Add("")
+
+	router := New[struct{}]()
+	for _, route := range fuzzRoutes {
+		router.AddHandler(route, fakeHandler(route))
+	}
+
+	f.Fuzz(func(t *testing.T, reqPath string) {
+		res := router.Lookup(reqPath)
+		if _, err := router.Serve(context.Background(), reqPath, struct{}{}); err != nil {
+			t.Fatalf("path %q: unexpected error: %v", reqPath, err)
+		}
+		if !res.Found() || reqPath == "" {
+			return
+		}
+		for _, p := range res.Params {
+			// named params match empty segments which BuildPath rejects
+			if p.Value == "" {
+				return
+			}
+		}
+
+		// the params round-trip through the pattern
+		built, err := BuildPath(res.Pattern, res.Params)
+		if err != nil {
+			t.Fatalf("path %q: cannot build path from %s %v: %v", reqPath, res.Pattern, res.Params, err)
+		}
+		if built != reqPath {
+			t.Fatalf("path %q: matched %s %v but built %q", reqPath, res.Pattern, res.Params, built)
+		}
+	})
+}
+
+func FuzzAddRoute(f *testing.F) {
+	f.Add("/user/:name", "/user/new")
+	f.Add("/src/*filepath", "/src/:file")
+	f.Add("/a/b/c", "/a/:b/*c")
+	f.Add("/dir/{$}", "/dir/")
+	f.Add("/user/{name}", "/user_:name")
+	f.Add(":", "*")
+
+	f.Fuzz(func(t *testing.T, first, second string) {
+		router := New[struct{}]()
+		addRoute := func(pattern string) bool {
+			return catchPanic(func() {
+				router.AddHandler(pattern, fakeHandler(pattern))
+			}) == nil
+		}
+
+		if !addRoute(first) {
+			if len(router.Routes()) != 0 {
+				t.Fatalf("pattern %q: failed registration changed the routes", first)
+			}
+			return
+		}
+		if !addRoute(second) && len(router.Routes()) != 1 {
+			t.Fatalf("pattern %q: failed registration changed the routes", second)
+		}
+
+		// the route registered first is reachable with a witness path
+		rt := router.load().routes[router.Routes()[0].Pattern]
+		witness, err := BuildPath(rt.path, witnessParams(rt.parts))
+		if err != nil {
+			t.Fatalf("pattern %q: cannot build witness path: %v", first, err)
+		}
+		if res := router.Lookup(witness); !res.Found() {
+			t.Fatalf("pattern %q: witness path %q not found", first, witness)
+		}
+	})
+}
+
+func FuzzCleanPath(f *testing.F) {
+	for _, test := range cleanTests {
+		f.Add(test.path)
+	}
+
+	f.Fuzz(func(t *testing.T, p string) {
+		cleaned := CleanPath(p)
+		if !strings.HasPrefix(cleaned, "/") {
+			t.Fatalf("path %q: cleaned path %q does not start with a slash", p, cleaned)
+		}
+		if again := CleanPath(cleaned); again != cleaned {
+			t.Fatalf("path %q: CleanPath is not idempotent: %q then %q", p, cleaned, again)
+		}
+		if want := path.Clean("/" + p); cleaned != want && cleaned != want+"/" {
+			t.Fatalf("path %q: cleaned path %q does not match path.Clean %q", p, cleaned, want)
+		}
+	})
+}
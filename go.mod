@@ -1,5 +1,11 @@
 module github.com/aperturerobotics/pathrouter
 
-go 1.19
+go 1.23
 
-require github.com/pkg/errors v0.9.1
+require (
+	github.com/aperturerobotics/protobuf-go-lite v0.14.0
+	github.com/pkg/errors v0.9.1
+	golang.org/x/text v0.21.0
+)
+
+require github.com/aperturerobotics/json-iterator-lite v1.0.0 // indirect
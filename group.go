@@ -0,0 +1,100 @@
+package pathrouter
+
+import "strings"
+
+// Group registers routes under a common prefix with a common middleware stack
+// and NotFound handler, see Router.Group.
+//
+// The middleware and NotFound handler apply only to routes registered through
+// the group. A Group is not safe for concurrent configuration.
+type Group[W any] struct {
+	router     *Router[W]
+	prefix     string
+	middleware []Middleware[W]
+}
+
+// Group returns a group registering routes under the prefix wrapped by the
+// middleware, for example:
+//
+//	api := router.Group("/api", authMiddleware)
+//	api.AddHandler("/users/:id", handle) // registers /api/users/:id
+//
+// The prefix can contain params.
+func (r *Router[W]) Group(prefix string, mws ...Middleware[W]) *Group[W] {
+	return &Group[W]{router: r, prefix: prefix, middleware: mws}
+}
+
+// Group returns a nested group under the prefix of the group.
+// Routes of the nested group are wrapped by the middleware of the group then by
+// the middleware passed here.
+func (g *Group[W]) Group(prefix string, mws ...Middleware[W]) *Group[W] {
+	middleware := make([]Middleware[W], 0, len(g.middleware)+len(mws))
+	middleware = append(middleware, g.middleware...)
+	middleware = append(middleware, mws...)
+	return &Group[W]{router: g.router, prefix: g.pattern(prefix), middleware: middleware}
+}
+
+// Prefix returns the prefix of the group.
+func (g *Group[W]) Prefix() string {
+	return g.prefix
+}
+
+// Use adds middleware to the group.
+// The middleware applies to the routes registered afterwards.
+// The first middleware added is the outermost.
+func (g *Group[W]) Use(mws ...Middleware[W]) {
+	g.middleware = append(g.middleware[:len(g.middleware):len(g.middleware)], mws...)
+}
+
+// AddHandler registers a handle for the pattern under the prefix of the group
+// wrapped by the middleware of the group, see Router.AddHandler.
+func (g *Group[W]) AddHandler(pattern string, handle Handle[W], opts ...RouteOption) {
+	if handle == nil {
+		return
+	}
+	g.router.AddHandler(g.pattern(pattern), applyMiddleware(handle, g.middleware), opts...)
+}
+
+// Route returns a builder for a route with the pattern under the prefix of the
+// group, see Router.Route. The middleware of the group wraps the middleware
+// added to the builder.
+func (g *Group[W]) Route(pattern string) *RouteBuilder[W] {
+	return g.router.Route(g.pattern(pattern)).Middleware(g.middleware...)
+}
+
+// NotFound sets the handle called if no route handles a path under the prefix
+// of the group, instead of RouterConfig.NotFound. The handle is wrapped by the
+// middleware of the group. The fallback router is tried first, see SetFallback.
+//
+// If groups are nested, the handle of the group with the longest prefix is
+// called. The prefix cannot contain params. Set nil to remove the handle.
+func (g *Group[W]) NotFound(handle Handle[W]) {
+	if strings.ContainsAny(g.prefix, ":*{}") {
+		panic("group with params in prefix '" + g.prefix + "' cannot have a NotFound handler")
+	}
+	r := g.router
+	prefix := strings.TrimSuffix(toTree(r.conf.UnicodeForm.Normalize(g.prefix), r.conf.Separator), "/")
+	if handle != nil {
+		handle = applyMiddleware(handle, g.middleware)
+	}
+	err := r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		if handle == nil {
+			delete(t.notFound, prefix)
+			return t, nil
+		}
+		if t.notFound == nil {
+			t.notFound = make(map[string]Handle[W], 1)
+		}
+		t.notFound[prefix] = handle
+		return t, nil
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// pattern joins the prefix of the group and the pattern.
+func (g *Group[W]) pattern(pattern string) string {
+	return g.router.joinPrefix(g.prefix, pattern)
+}
@@ -0,0 +1,116 @@
+package pathrouter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware[*[]string] {
+		return func(next Handle[*[]string]) Handle[*[]string] {
+			return func(ctx context.Context, reqPath string, p Params, rw *[]string) (bool, error) {
+				*rw = append(*rw, name)
+				return next(ctx, reqPath, p, rw)
+			}
+		}
+	}
+	handle := func(name string) Handle[*[]string] {
+		return func(ctx context.Context, reqPath string, p Params, rw *[]string) (bool, error) {
+			*rw = append(*rw, name+":"+p.ByName("id"))
+			return true, nil
+		}
+	}
+
+	conf := DefaultConfig[*[]string]()
+	conf.NotFound = handle("notfound")
+	router := NewWithConfig(conf)
+	router.AddHandler("/", handle("root"))
+
+	api := router.Group("/api/", record("api"))
+	api.AddHandler("/users/:id", handle("user"))
+	api.NotFound(handle("api-notfound"))
+	admin := api.Group("admin", record("admin"))
+	admin.Route("/users/:id").Middleware(record("route")).Handle(handle("admin-user"))
+	admin.NotFound(handle("admin-notfound"))
+	api.Use(record("late"))
+	api.AddHandler("/late", handle("late"))
+	tenant := router.Group("/t/:id")
+	tenant.AddHandler("/home", handle("home"))
+
+	if prefix := admin.Prefix(); prefix != "/api/admin" {
+		t.Errorf("unexpected nested prefix %q", prefix)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/", "root:"},
+		{"/api/users/1", "api user:1"},
+		{"/api/admin/users/2", "api admin route admin-user:2"},
+		{"/api/late", "api late late:"},
+		{"/t/3/home", "home:3"},
+		{"/api/missing", "api api-notfound:"},
+		{"/api", "api api-notfound:"},
+		{"/api/admin/missing", "api admin admin-notfound:"},
+		{"/apix", "notfound:"},
+		{"/missing", "notfound:"},
+	}
+	for _, tt := range tests {
+		calls = nil
+		if found, err := router.Serve(context.Background(), tt.path, &calls); !found || err != nil {
+			t.Errorf("%s: expected handled, got found=%v err=%v", tt.path, found, err)
+		}
+		if got := strings.Join(calls, " "); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.want, got)
+		}
+	}
+
+	// removing the group handle falls back to the parent group
+	admin.NotFound(nil)
+	calls = nil
+	_, _ = router.Serve(context.Background(), "/api/admin/missing", &calls)
+	if got := strings.Join(calls, " "); got != "api api-notfound:" {
+		t.Errorf("expected parent group NotFound, got %q", got)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for NotFound on a group with params")
+			}
+		}()
+		tenant.NotFound(handle("tenant"))
+	}()
+}
+
+func TestGroupSeparator(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.Separator = '.'
+	conf.NotFound = fakeHandler("notfound")
+	router := NewWithConfig(conf)
+
+	sensors := router.Group("sensors")
+	sensors.AddHandler(":id.temp", fakeHandler("temp"))
+	sensors.NotFound(fakeHandler("sensors-notfound"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"sensors.a.temp", "temp"},
+		{"sensors.a.humidity", "sensors-notfound"},
+		{"other", "notfound"},
+	}
+	for _, tt := range tests {
+		fakeHandlerValue = ""
+		if found, err := router.Serve(context.Background(), tt.path, struct{}{}); !found || err != nil {
+			t.Errorf("%s: expected handled, got found=%v err=%v", tt.path, found, err)
+		}
+		if fakeHandlerValue != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.want, fakeHandlerValue)
+		}
+	}
+}
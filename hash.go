@@ -0,0 +1,69 @@
+package pathrouter
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// Hash returns a digest of the route table, for example to detect if the
+// effective route table of a deployment changed or to label metrics.
+//
+// The digest covers the patterns, handler names, metadata, tags, param
+// defaults, timeouts and the targets of redirect, rewrite and alias routes. It does not depend on the
+// registration order or the version. Handles are not covered: routes should be
+// registered with handler names, see WithName. Metadata is encoded as JSON.
+// The digest is computed once per route table.
+func (r *Router[W]) Hash() uint64 {
+	t := r.load()
+	if sum := t.hash.Load(); sum != 0 {
+		return sum
+	}
+
+	patterns := make([]string, 0, len(t.routes))
+	for pattern := range t.routes {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	h := fnv.New64a()
+	for _, pattern := range patterns {
+		rt := t.routes[pattern]
+		hashString(h, rt.pattern)
+		hashString(h, rt.name)
+		metadata, err := json.Marshal(rt.metadata)
+		if err != nil {
+			metadata = []byte(fmt.Sprintf("%v", rt.metadata))
+		}
+		hashString(h, string(metadata))
+		hashString(h, strconv.Itoa(len(rt.tags)))
+		for _, tag := range rt.tags {
+			hashString(h, tag)
+		}
+		defaults, _ := json.Marshal(rt.defaults)
+		hashString(h, string(defaults))
+		hashString(h, rt.canonical)
+		hashString(h, rt.locale)
+		hashString(h, rt.redirect)
+		hashString(h, strconv.FormatBool(rt.rewrite))
+		hashString(h, strconv.FormatInt(int64(rt.timeout), 10))
+	}
+	sum := h.Sum64()
+	if sum == 0 {
+		// zero indicates the digest was not computed
+		sum = 1
+	}
+	t.hash.Store(sum)
+	return sum
+}
+
+// hashString writes the length-prefixed string to the hash.
+func hashString(h hash.Hash64, s string) {
+	var n [binary.MaxVarintLen64]byte
+	_, _ = h.Write(n[:binary.PutUvarint(n[:], uint64(len(s)))])
+	_, _ = h.Write([]byte(s))
+}
@@ -0,0 +1,87 @@
+package pathrouter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRouterHash(t *testing.T) {
+	build := func(reverse bool) *Router[struct{}] {
+		router := New[struct{}]()
+		defs := []RouteDef[struct{}]{
+			{Pattern: "/user/:name", Handle: fakeHandler("user"), Name: "user", Metadata: map[string]any{"b": 2, "a": []any{1}}},
+			{Pattern: "/files/*filepath", Handle: fakeHandler("files"), Name: "files", Tags: []string{"public"}},
+		}
+		if reverse {
+			defs[0], defs[1] = defs[1], defs[0]
+		}
+		if err := router.AddRoutes(defs); err != nil {
+			t.Fatal(err.Error())
+		}
+		return router
+	}
+
+	router := build(false)
+	sum := router.Hash()
+	if sum == 0 || router.Hash() != sum {
+		t.Fatalf("expected a stable non-zero hash, got %d", sum)
+	}
+	if other := build(true); other.Hash() != sum {
+		t.Error("expected the hash not to depend on the registration order")
+	}
+	if New[struct{}]().Hash() == sum {
+		t.Error("expected the empty router to have a different hash")
+	}
+
+	// changes to the effective route table change the hash
+	changes := []func(r *Router[struct{}]){
+		func(r *Router[struct{}]) { r.AddHandler("/new", fakeHandler("new")) },
+		func(r *Router[struct{}]) { r.AddRedirect("/old", "/new") },
+		func(r *Router[struct{}]) { r.AddAlias("/u/:name", "/user/:name") },
+		func(r *Router[struct{}]) {
+			delta := r.DeltaSince(0)
+			delta.Added[0].Timeout = time.Second
+			delta.ToVersion++
+			_ = r.ApplyDelta(delta)
+		},
+		func(r *Router[struct{}]) {
+			delta := r.DeltaSince(0)
+			delta.Added[0].Name = "renamed"
+			delta.ToVersion++
+			_ = r.ApplyDelta(delta)
+		},
+		func(r *Router[struct{}]) {
+			delta := r.DeltaSince(0)
+			delta.Added[0].Metadata = map[string]any{"b": 3}
+			delta.ToVersion++
+			_ = r.ApplyDelta(delta)
+		},
+		func(r *Router[struct{}]) {
+			delta := r.DeltaSince(0)
+			delta.Added[1].Tags = nil
+			delta.ToVersion++
+			_ = r.ApplyDelta(delta)
+		},
+	}
+	seen := map[uint64]int{sum: -1}
+	for i, change := range changes {
+		r := build(false)
+		change(r)
+		if prev, ok := seen[r.Hash()]; ok {
+			t.Errorf("change %d: expected a new hash, got the hash of %d", i, prev)
+		}
+		seen[r.Hash()] = i
+	}
+
+	// the enabled state and the handles are not covered
+	router.SetRouteEnabled("/user/:name", false)
+	delta := router.DeltaSince(0)
+	delta.Added[0].Handle = fakeHandler("other")
+	delta.ToVersion++
+	if err := router.ApplyDelta(delta); err != nil {
+		t.Fatal(err.Error())
+	}
+	if router.Hash() != sum {
+		t.Error("expected the hash to only cover the route definitions")
+	}
+}
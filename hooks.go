@@ -0,0 +1,107 @@
+package pathrouter
+
+import (
+	"slices"
+	"sort"
+)
+
+// routeHooks contains the route change hooks of a router.
+// Copied on write: must not be modified once published.
+type routeHooks[W any] struct {
+	added    []func(def RouteDef[W])
+	removed  []func(def RouteDef[W])
+	replaced []func(old, def RouteDef[W])
+}
+
+// OnRouteAdded registers a func called with the definition of each route
+// added to the router, for example to keep an auxiliary index of the routes in
+// sync with the routes registered by other modules.
+//
+// The route change hooks are called after the route table is published,
+// without holding the router lock: the hooks may change the router. If the
+// router is changed concurrently the hooks may be called concurrently and
+// out of order. Routes registered before the hook are not reported, see
+// Routes. The hooks are not copied by Clone.
+func (r *Router[W]) OnRouteAdded(fn func(def RouteDef[W])) {
+	r.addHooks(func(h *routeHooks[W]) {
+		h.added = append(h.added, fn)
+	})
+}
+
+// OnRouteRemoved registers a func called with the definition of each route
+// removed from the router, for example by ApplyDelta, see OnRouteAdded.
+func (r *Router[W]) OnRouteRemoved(fn func(def RouteDef[W])) {
+	r.addHooks(func(h *routeHooks[W]) {
+		h.removed = append(h.removed, fn)
+	})
+}
+
+// OnRouteReplaced registers a func called with the old and the new definition
+// of each route replaced with a route with the same pattern, for example by
+// AppendHandler, WrapTag or ApplyDelta, see OnRouteAdded.
+func (r *Router[W]) OnRouteReplaced(fn func(old, def RouteDef[W])) {
+	r.addHooks(func(h *routeHooks[W]) {
+		h.replaced = append(h.replaced, fn)
+	})
+}
+
+// addHooks updates a copy of the route change hooks with fn.
+func (r *Router[W]) addHooks(fn func(h *routeHooks[W])) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	var hooks routeHooks[W]
+	if old := r.hooks.Load(); old != nil {
+		hooks.added = append(hooks.added, old.added...)
+		hooks.removed = append(hooks.removed, old.removed...)
+		hooks.replaced = append(hooks.replaced, old.replaced...)
+	}
+	fn(&hooks)
+	r.hooks.Store(&hooks)
+}
+
+// notify calls the hooks with the changes from the old to the new table.
+// The removed and replaced routes are reported by pattern, then the added
+// routes in registration order.
+//
+// Only the routes changed since the new table was cloned from the old table
+// are compared, see table.changed.
+func (h *routeHooks[W]) notify(old, t *table[W]) {
+	patterns := slices.Clone(t.changed)
+	slices.Sort(patterns)
+	patterns = slices.Compact(patterns)
+
+	var added []*route[W]
+	for _, pattern := range patterns {
+		ort, existed := old.routes[pattern]
+		rt, ok := t.routes[pattern]
+		switch {
+		case !existed:
+			if ok {
+				added = append(added, rt)
+			}
+		case !ok:
+			for _, fn := range h.removed {
+				fn(ort.routeDef())
+			}
+		case rt != ort:
+			for _, fn := range h.replaced {
+				fn(ort.routeDef(), rt.routeDef())
+			}
+		}
+	}
+
+	if len(h.added) != 0 {
+		sort.Slice(added, func(i, j int) bool {
+			if added[i].version != added[j].version {
+				return added[i].version < added[j].version
+			}
+			return added[i].pattern < added[j].pattern
+		})
+		for _, rt := range added {
+			for _, fn := range h.added {
+				fn(rt.routeDef())
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package pathrouter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouterRouteHooks(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/before", fakeHandler("before"))
+
+	var events []string
+	router.OnRouteAdded(func(def RouteDef[struct{}]) {
+		events = append(events, "added "+def.Pattern)
+	})
+	router.OnRouteRemoved(func(def RouteDef[struct{}]) {
+		events = append(events, "removed "+def.Pattern)
+	})
+	router.OnRouteReplaced(func(old, def RouteDef[struct{}]) {
+		if old.Pattern != def.Pattern {
+			t.Errorf("unexpected replaced route %v %v", old, def)
+		}
+		events = append(events, "replaced "+def.Pattern)
+	})
+	// hooks may change the router
+	router.OnRouteAdded(func(def RouteDef[struct{}]) {
+		if def.Pattern == "/user/:name" {
+			router.AddHandler("/user/:name/about", fakeHandler("about"))
+		}
+	})
+
+	expect := func(want ...string) {
+		t.Helper()
+		if !reflect.DeepEqual(events, want) {
+			t.Errorf("expected events %v but got %v", want, events)
+		}
+		events = nil
+	}
+
+	router.AddHandler("/user/:name", fakeHandler("user"), WithName("user"))
+	expect("added /user/:name", "added /user/:name/about")
+	// only the changed routes are compared
+	if changed := router.load().changed; !reflect.DeepEqual(changed, []string{"/user/:name/about"}) {
+		t.Errorf("expected only the added route to be compared, got %v", changed)
+	}
+
+	if err := router.AddRoutes([]RouteDef[struct{}]{
+		{Pattern: "/b", Handle: fakeHandler("b")},
+		{Pattern: "/a", Handle: fakeHandler("a")},
+	}); err != nil {
+		t.Fatal(err.Error())
+	}
+	expect("added /b", "added /a")
+
+	// failed changes are not reported
+	if err := router.AddRoutes([]RouteDef[struct{}]{
+		{Pattern: "/c", Handle: fakeHandler("c")},
+		{Pattern: "/user/:id", Handle: fakeHandler("id")},
+	}); err == nil {
+		t.Fatal("expected conflict")
+	}
+	expect()
+
+	delta := router.DeltaSince(0)
+	delta.Added = delta.Added[:3]
+	delta.Added[1].Name = "renamed"
+	delta.ToVersion = router.Version() + 1
+	if err := router.ApplyDelta(delta); err != nil {
+		t.Fatal(err.Error())
+	}
+	expect("removed /a", "removed /b", "replaced /before", "replaced /user/:name", "replaced /user/:name/about")
+	if def := router.Routes()[1]; def.Name != "renamed" {
+		t.Errorf("expected the replaced route, got %v", def)
+	}
+
+	// the enabled state is not a route change
+	router.SetRouteEnabled("/before", false)
+	expect()
+
+	if clone := router.Clone(); clone.hooks.Load() != nil {
+		t.Error("expected the hooks not to be cloned")
+	}
+}
@@ -0,0 +1,147 @@
+package pathrouter
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// HostRouter dispatches requests to a Router by hostname.
+//
+// Hosts are registered either exactly, for example api.example.com, or with a
+// wildcard subdomain, for example *.example.com, which matches any host with
+// one or more labels before example.com. An exact match takes precedence over
+// a wildcard and a longer wildcard over a shorter one. Hostnames are matched
+// case-insensitively and the port, if any, is ignored.
+//
+// Safe to use concurrently.
+type HostRouter[W any] struct {
+	mtx  sync.RWMutex
+	root hostNode[W]
+	// def is the router used if no host matches, if any.
+	def *Router[W]
+}
+
+// hostNode is a node in the reverse label trie of a HostRouter.
+type hostNode[W any] struct {
+	// children contains the child nodes by the next label.
+	children map[string]*hostNode[W]
+	// router is the router for the exact host, if any.
+	router *Router[W]
+	// wildcard is the router for subdomains of the host, if any.
+	wildcard *Router[W]
+}
+
+// NewHostRouter constructs a new empty HostRouter.
+func NewHostRouter[W any]() *HostRouter[W] {
+	return &HostRouter[W]{}
+}
+
+// AddHost registers the router for a host.
+//
+// The host is a hostname optionally prefixed with "*." to match subdomains.
+// Panics if the host is invalid or a router is already registered for it.
+func (h *HostRouter[W]) AddHost(host string, router *Router[W]) {
+	if router == nil {
+		panic("router must not be nil")
+	}
+	wildcard := strings.HasPrefix(host, "*.")
+	if wildcard {
+		host = host[2:]
+	}
+	name := normalizeHost(host)
+	if name == "" || strings.ContainsAny(name, "*:") {
+		panic("invalid host '" + host + "'")
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	n := &h.root
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		if labels[i] == "" {
+			panic("invalid host '" + host + "'")
+		}
+		child := n.children[labels[i]]
+		if child == nil {
+			child = &hostNode[W]{}
+			if n.children == nil {
+				n.children = make(map[string]*hostNode[W])
+			}
+			n.children[labels[i]] = child
+		}
+		n = child
+	}
+
+	slot := &n.router
+	if wildcard {
+		slot = &n.wildcard
+		host = "*." + host
+	}
+	if *slot != nil {
+		panic("a router is already registered for host '" + host + "'")
+	}
+	*slot = router
+}
+
+// SetDefault sets the router used if no host matches.
+// Set nil to remove the default router.
+func (h *HostRouter[W]) SetDefault(router *Router[W]) {
+	h.mtx.Lock()
+	h.def = router
+	h.mtx.Unlock()
+}
+
+// Match returns the router for the host or the default router if none match.
+// The host may contain a port. Returns nil if no router matches.
+func (h *HostRouter[W]) Match(host string) *Router[W] {
+	name := normalizeHost(host)
+
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+	match := h.def
+	n := &h.root
+	for name != "" {
+		var label string
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			label, name = name[i+1:], name[:i]
+		} else {
+			label, name = name, ""
+		}
+		if n.wildcard != nil {
+			match = n.wildcard
+		}
+		n = n.children[label]
+		if n == nil {
+			return match
+		}
+	}
+	if n.router != nil {
+		return n.router
+	}
+	return match
+}
+
+// Serve serves a request with the router for the host.
+// Returns false if no router matches the host.
+func (h *HostRouter[W]) Serve(ctx context.Context, host, reqPath string, rw W) (bool, error) {
+	router := h.Match(host)
+	if router == nil {
+		return false, nil
+	}
+	return router.Serve(ctx, reqPath, rw)
+}
+
+// normalizeHost lowercases the host and removes the port and trailing dot.
+func normalizeHost(host string) string {
+	if strings.HasPrefix(host, "[") {
+		// IPv6 literal
+		if i := strings.IndexByte(host, ']'); i >= 0 {
+			host = host[:i+1]
+		}
+	} else if i := strings.LastIndexByte(host, ':'); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.TrimSuffix(host, ".")
+	return strings.ToLower(host)
+}
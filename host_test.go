@@ -0,0 +1,75 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHostRouter(t *testing.T) {
+	newRouter := func(name string) *Router[struct{}] {
+		router := New[struct{}]()
+		router.AddHandler("/*path", fakeHandler(name))
+		return router
+	}
+
+	hosts := NewHostRouter[struct{}]()
+	hosts.AddHost("example.com", newRouter("example.com"))
+	hosts.AddHost("*.example.com", newRouter("*.example.com"))
+	hosts.AddHost("api.example.com", newRouter("api.example.com"))
+	hosts.AddHost("*.eu.example.com", newRouter("*.eu.example.com"))
+
+	ctx := context.Background()
+	tests := []struct {
+		host  string
+		value string
+	}{
+		{"example.com", "example.com"},
+		{"EXAMPLE.com:8080", "example.com"},
+		{"example.com.", "example.com"},
+		{"api.example.com", "api.example.com"},
+		{"www.example.com", "*.example.com"},
+		{"a.b.example.com", "*.example.com"},
+		{"eu.example.com", "*.example.com"},
+		{"api.eu.example.com", "*.eu.example.com"},
+		{"v1.api.example.com", "*.example.com"},
+		{"example.org", ""},
+		{"com", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		fakeHandlerValue = ""
+		found, _ := hosts.Serve(ctx, test.host, "/", struct{}{})
+		if found != (test.value != "") || fakeHandlerValue != test.value {
+			t.Errorf("host %q: expected %q but got %v %q", test.host, test.value, found, fakeHandlerValue)
+		}
+	}
+
+	hosts.SetDefault(newRouter("default"))
+	fakeHandlerValue = ""
+	if found, _ := hosts.Serve(ctx, "example.org", "/", struct{}{}); !found || fakeHandlerValue != "default" {
+		t.Errorf("expected default router, got %v %q", found, fakeHandlerValue)
+	}
+
+	for _, host := range []string{"example.com", "*.example.com", "", "*.", "a..com", "*.*.com"} {
+		if recv := catchPanic(func() { hosts.AddHost(host, newRouter(host)) }); recv == nil {
+			t.Errorf("host %q: expected panic", host)
+		}
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		host, result string
+	}{
+		{"Example.COM", "example.com"},
+		{"example.com:443", "example.com"},
+		{"example.com.", "example.com"},
+		{"[::1]:8080", "[::1]"},
+		{"[::1]", "[::1]"},
+	}
+	for _, test := range tests {
+		if result := normalizeHost(test.host); result != test.result {
+			t.Errorf("normalizeHost(%q) = %q, want %q", test.host, result, test.result)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package httprouter adapts pathrouter to net/http.
+//
+// Router implements http.Handler with a pathrouter.MethodRouter, similar to
+// julienschmidt/httprouter:
+//
+//	router := httprouter.New()
+//	router.GET("/hello/:name", func(w http.ResponseWriter, r *http.Request, ps pathrouter.Params) {
+//		fmt.Fprintf(w, "hello, %s!\n", ps.ByName("name"))
+//	})
+//	log.Fatal(http.ListenAndServe(":8080", router))
+package httprouter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+// Handle is a request handler with the path params.
+//
+// The params are only valid until the handle returns, see Params.Clone.
+type Handle func(w http.ResponseWriter, r *http.Request, ps pathrouter.Params)
+
+// requestCtxKey is the context key for the request passed to the path routers.
+type requestCtxKey struct{}
+
+// Router is a http.Handler dispatching requests by method and path.
+//
+// The options must be set before registering routes.
+type Router struct {
+	// RedirectTrailingSlash enables redirects if the path has an extra or
+	// missing trailing slash and a route exists for the corrected path.
+	// GET requests are redirected with 301, other methods with 308.
+	RedirectTrailingSlash bool
+
+	// RedirectFixedPath enables redirects to the cleaned and case-corrected
+	// path if a route exists for it, see pathrouter.CleanPath.
+	RedirectFixedPath bool
+
+	// HandleMethodNotAllowed enables replying with 405 Method Not Allowed and
+	// the Allow header if no route matches the method but routes for other
+	// methods match the path.
+	HandleMethodNotAllowed bool
+
+	// MethodNotAllowed is called instead of replying with the default 405
+	// response if HandleMethodNotAllowed is set. The Allow header is set.
+	MethodNotAllowed http.Handler
+
+	// NotFound is called if no route matches. Defaults to http.NotFound.
+	NotFound http.Handler
+
+	// PanicHandler is called with the value recovered from a panicking handle.
+	// If nil, panics are not recovered.
+	PanicHandler func(w http.ResponseWriter, r *http.Request, rcv interface{})
+
+	once    sync.Once
+	methods *pathrouter.MethodRouter[http.ResponseWriter]
+}
+
+// New returns a new Router with trailing slash and fixed path redirects and
+// 405 Method Not Allowed responses.
+func New() *Router {
+	return &Router{
+		RedirectTrailingSlash:  true,
+		RedirectFixedPath:      true,
+		HandleMethodNotAllowed: true,
+	}
+}
+
+// GET registers a handle for GET requests.
+func (r *Router) GET(path string, handle Handle) {
+	r.Handle(http.MethodGet, path, handle)
+}
+
+// HEAD registers a handle for HEAD requests.
+func (r *Router) HEAD(path string, handle Handle) {
+	r.Handle(http.MethodHead, path, handle)
+}
+
+// OPTIONS registers a handle for OPTIONS requests.
+func (r *Router) OPTIONS(path string, handle Handle) {
+	r.Handle(http.MethodOptions, path, handle)
+}
+
+// POST registers a handle for POST requests.
+func (r *Router) POST(path string, handle Handle) {
+	r.Handle(http.MethodPost, path, handle)
+}
+
+// PUT registers a handle for PUT requests.
+func (r *Router) PUT(path string, handle Handle) {
+	r.Handle(http.MethodPut, path, handle)
+}
+
+// PATCH registers a handle for PATCH requests.
+func (r *Router) PATCH(path string, handle Handle) {
+	r.Handle(http.MethodPatch, path, handle)
+}
+
+// DELETE registers a handle for DELETE requests.
+func (r *Router) DELETE(path string, handle Handle) {
+	r.Handle(http.MethodDelete, path, handle)
+}
+
+// Handle registers a handle for the request method and path.
+// The params are attached to the request context, see pathrouter.ParamsFromContext.
+// Panics if the path conflicts with an existing route for the method.
+func (r *Router) Handle(method, path string, handle Handle, opts ...pathrouter.RouteOption) {
+	if method == "" {
+		panic("method must not be empty")
+	}
+	if handle == nil {
+		panic("handle must not be nil")
+	}
+	r.methodRouter().AddHandler(method, path, func(ctx context.Context, reqPath string, ps pathrouter.Params, w http.ResponseWriter) (bool, error) {
+		req := requestFromContext(ctx)
+		if len(ps) != 0 {
+			req = req.WithContext(pathrouter.ContextWithParams(req.Context(), ps))
+		}
+		handle(w, req, ps)
+		return true, nil
+	}, opts...)
+}
+
+// Handler registers a http.Handler for the request method and path.
+// The params are attached to the request context, see pathrouter.ParamsFromContext.
+func (r *Router) Handler(method, path string, handler http.Handler, opts ...pathrouter.RouteOption) {
+	r.Handle(method, path, func(w http.ResponseWriter, req *http.Request, ps pathrouter.Params) {
+		handler.ServeHTTP(w, req)
+	}, opts...)
+}
+
+// HandlerFunc registers a http.HandlerFunc for the request method and path.
+func (r *Router) HandlerFunc(method, path string, handler http.HandlerFunc, opts ...pathrouter.RouteOption) {
+	r.Handler(method, path, handler, opts...)
+}
+
+// Lookup looks up the handle for the method and path.
+// Returns the handle and params if found or if a trailing slash redirect
+// would find a handle.
+func (r *Router) Lookup(method, path string) (pathrouter.Handle[http.ResponseWriter], pathrouter.Params, bool) {
+	res := r.methodRouter().Lookup(method, path)
+	return res.Handle, res.Params, res.TSR
+}
+
+// ServeHTTP serves a request with the route for the method and path.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.PanicHandler != nil {
+		defer r.recoverPanic(w, req)
+	}
+
+	ctx := context.WithValue(req.Context(), requestCtxKey{}, req)
+	if found, _ := r.methodRouter().Serve(ctx, req.Method, req.URL.Path, w); found {
+		return
+	}
+
+	if r.NotFound != nil {
+		r.NotFound.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+// recoverPanic recovers from a panic while serving the request.
+func (r *Router) recoverPanic(w http.ResponseWriter, req *http.Request) {
+	if rcv := recover(); rcv != nil {
+		r.PanicHandler(w, req, rcv)
+	}
+}
+
+// methodRouter returns the method router, creating it on first use.
+func (r *Router) methodRouter() *pathrouter.MethodRouter[http.ResponseWriter] {
+	r.once.Do(func() {
+		conf := pathrouter.MethodRouterConfig[http.ResponseWriter]{
+			RouterConfig: pathrouter.RouterConfig[http.ResponseWriter]{
+				RedirectTrailingSlash: r.RedirectTrailingSlash,
+				RedirectFixedPath:     r.RedirectFixedPath,
+				RedirectHandler:       redirect,
+			},
+		}
+		if r.HandleMethodNotAllowed {
+			conf.MethodNotAllowed = r.methodNotAllowed
+		}
+		r.methods = pathrouter.NewMethodRouter(conf)
+	})
+	return r.methods
+}
+
+// methodNotAllowed replies with 405 Method Not Allowed and the Allow header.
+func (r *Router) methodNotAllowed(ctx context.Context, method, reqPath string, allowed []string, w http.ResponseWriter) (bool, error) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	if r.MethodNotAllowed != nil {
+		r.MethodNotAllowed.ServeHTTP(w, requestFromContext(ctx))
+	} else {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+	return true, nil
+}
+
+// redirect redirects the request to the corrected path.
+// GET requests are redirected with 301 Moved Permanently and other methods
+// with 308 Permanent Redirect to preserve the method and body.
+func redirect(ctx context.Context, fromPath, toPath string, w http.ResponseWriter) (bool, error) {
+	req := requestFromContext(ctx)
+	code := http.StatusMovedPermanently
+	if req.Method != http.MethodGet {
+		code = http.StatusPermanentRedirect
+	}
+	if req.URL.RawQuery != "" {
+		toPath += "?" + req.URL.RawQuery
+	}
+	http.Redirect(w, req, toPath, code)
+	return true, nil
+}
+
+// requestFromContext returns the request attached by ServeHTTP.
+func requestFromContext(ctx context.Context) *http.Request {
+	req, _ := ctx.Value(requestCtxKey{}).(*http.Request)
+	return req
+}
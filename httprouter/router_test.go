@@ -0,0 +1,91 @@
+package httprouter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+func TestRouter(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", func(w http.ResponseWriter, r *http.Request, ps pathrouter.Params) {
+		fmt.Fprintf(w, "user %s", ps.ByName("name"))
+	})
+	router.POST("/user/:name", func(w http.ResponseWriter, r *http.Request, ps pathrouter.Params) {
+		fmt.Fprintf(w, "created %s", pathrouter.ParamsFromContext(r.Context()).ByName("name"))
+	})
+	router.HandlerFunc(http.MethodGet, "/files/*path", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "file %s", pathrouter.ParamsFromContext(r.Context()).ByName("path"))
+	})
+	router.GET("/dir/", func(w http.ResponseWriter, r *http.Request, ps pathrouter.Params) {
+		fmt.Fprint(w, "dir")
+	})
+
+	tests := []struct {
+		method   string
+		path     string
+		code     int
+		body     string
+		location string
+	}{
+		{http.MethodGet, "/user/gopher", http.StatusOK, "user gopher", ""},
+		{http.MethodPost, "/user/gopher", http.StatusOK, "created gopher", ""},
+		{http.MethodGet, "/files/a/b.txt", http.StatusOK, "file /a/b.txt", ""},
+		{http.MethodGet, "/dir", http.StatusMovedPermanently, "", "/dir/"},
+		{http.MethodGet, "/dir?x=1", http.StatusMovedPermanently, "", "/dir/?x=1"},
+		{http.MethodPost, "/user/gopher/", http.StatusPermanentRedirect, "", "/user/gopher"},
+		{http.MethodGet, "/USER/gopher", http.StatusMovedPermanently, "", "/user/gopher"},
+		{http.MethodGet, "/missing", http.StatusNotFound, "404 page not found\n", ""},
+		{http.MethodPut, "/user/gopher", http.StatusMethodNotAllowed, "Method Not Allowed\n", ""},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+		if w.Code != test.code {
+			t.Errorf("%s %s: expected code %d but got %d", test.method, test.path, test.code, w.Code)
+		}
+		if test.body != "" && w.Body.String() != test.body {
+			t.Errorf("%s %s: expected body %q but got %q", test.method, test.path, test.body, w.Body.String())
+		}
+		if test.code == http.StatusMethodNotAllowed && w.Header().Get("Allow") != "GET, POST" {
+			t.Errorf("%s %s: unexpected Allow header %q", test.method, test.path, w.Header().Get("Allow"))
+		}
+		if location := w.Header().Get("Location"); location != test.location {
+			t.Errorf("%s %s: expected location %q but got %q", test.method, test.path, test.location, location)
+		}
+	}
+}
+
+func TestRouterPanicHandler(t *testing.T) {
+	router := New()
+	var recovered interface{}
+	router.PanicHandler = func(w http.ResponseWriter, r *http.Request, rcv interface{}) {
+		recovered = rcv
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	router.GET("/panic", func(w http.ResponseWriter, r *http.Request, ps pathrouter.Params) {
+		panic("oops")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if recovered != "oops" || w.Code != http.StatusInternalServerError || w.Body.Len() != 0 {
+		t.Errorf("expected recovered panic, got %v %d", recovered, w.Code)
+	}
+}
+
+func TestRouterNotFound(t *testing.T) {
+	router := New()
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != http.StatusTeapot {
+		t.Errorf("expected custom not found handler, got %d", w.Code)
+	}
+}
@@ -0,0 +1,252 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// Overlapping routes
+//
+// A tree can only contain routes which do not conflict with each other: a path
+// matches exactly one route or none. When overlapping routes are allowed (see
+// RouterConfig.Fallthrough) each route is inserted into the first tree it does
+// not conflict with, adding a new tree if necessary. A path is then looked up
+// in each of the trees and the matches are ordered by precedence.
+
+// match is a route matched by a path.
+type match[W any] struct {
+	// route is the matched route.
+	route *route[W]
+	// handle is the matched handle.
+	handle Handle[W]
+	// ps contains the path params, if any.
+	ps *Params
+	// offsets contains the offsets of the params if they were not extracted,
+	// see LazyParams.
+	offsets paramOffsets
+}
+
+// partRank returns the precedence rank of a pattern part kind.
+// Static parts rank before named params which rank before catch-alls.
+func partRank(kind nodeType) int {
+	switch kind {
+	case param:
+		return 1
+	case catchAll:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// comparePatterns compares the precedence of two parsed patterns matching the
+// same path. Returns a negative value if a takes precedence over b.
+//
+// The patterns are compared part by part: a longer static part takes
+// precedence, then static parts take precedence over named params, which take
+// precedence over catch-alls.
+func comparePatterns(a, b []patternPart) int {
+	for i := 0; ; i++ {
+		if i >= len(a) || i >= len(b) {
+			// the end of the pattern ranks as a static part
+			var ra, rb int
+			if i < len(a) {
+				ra = partRank(a[i].kind)
+			}
+			if i < len(b) {
+				rb = partRank(b[i].kind)
+			}
+			return ra - rb
+		}
+
+		pa, pb := a[i], b[i]
+		if pa.kind == static && pb.kind == static {
+			if len(pa.value) != len(pb.value) {
+				return len(pb.value) - len(pa.value)
+			}
+			continue
+		}
+		if ra, rb := partRank(pa.kind), partRank(pb.kind); ra != rb {
+			return ra - rb
+		}
+	}
+}
+
+// compareRoutes compares the precedence of two routes matching the same path.
+// Routes with equal precedence are ordered by registration.
+func compareRoutes[W any](a, b *route[W]) int {
+	if cmp := comparePatterns(a.parts, b.parts); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case a.version < b.version:
+		return -1
+	case a.version > b.version:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sortMatches sorts the matches by precedence.
+func sortMatches[W any](matches []match[W]) {
+	if len(matches) < 2 {
+		return
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return compareRoutes(matches[i].route, matches[j].route) < 0
+	})
+}
+
+// tryInsertRoute inserts the route into a copy of the tree returning any
+// conflict as an error. Returns the updated tree, the tree is not changed.
+//
+// Only the nodes along the path of the route are copied, see node.copyPath.
+func tryInsertRoute[W any](tree *node[W], rt *route[W]) (updated *node[W], err error) {
+	defer recoverConflict(&err)
+	updated = tree.copyPath(rt.path)
+	updated.insertRoute(rt)
+	return updated, nil
+}
+
+// insertRouteLayered inserts the route into the first of the trees it does not
+// conflict with, adding a new tree if necessary. Returns the updated trees.
+func insertRouteLayered[W any](trees []*node[W], rt *route[W]) []*node[W] {
+	for i, tree := range trees {
+		if updated, err := tryInsertRoute(tree, rt); err == nil {
+			trees[i] = updated
+			return trees
+		}
+	}
+
+	tree := new(node[W])
+	tree.insertRoute(rt)
+	return append(trees, tree)
+}
+
+// matchAll looks up the path in all trees skipping the disabled routes.
+// Returns the matches in precedence order or if a TSR would find a match.
+func (r *Router[W]) matchAll(t *table[W], path string) (matches []match[W], tsr bool) {
+	return r.matchRoutes(t, path, false)
+}
+
+// matchRoutes looks up the path in all trees, see matchAll.
+// If withDisabled is set the disabled routes are matched as well.
+func (r *Router[W]) matchRoutes(t *table[W], path string, withDisabled bool) (matches []match[W], tsr bool) {
+	return r.matchRoutesParams(t, path, withDisabled, r.getParams)
+}
+
+// matchRoutesParams looks up the path in all trees getting the params with
+// the params func, see matchRoutes. If params is nil the params are not
+// extracted: their offsets are recorded instead.
+func (r *Router[W]) matchRoutesParams(t *table[W], path string, withDisabled bool, params func() *Params) (matches []match[W], tsr bool) {
+	var offsets paramOffsets
+	var record *paramOffsets
+	if params == nil {
+		record = &offsets
+	}
+	for _, tree := range t.trees {
+		offsets = paramOffsets{}
+		leaf, ps, ttsr := tree.getValue(path, params, record)
+		if leaf != nil && leaf.route.emptyTrailingDefault(hasTrailingSlash(path)) {
+			leaf, ttsr = nil, t.trailingSlashMatch(path)
+		}
+		if leaf != nil && !withDisabled && !leaf.route.enabled() {
+			r.putParams(ps)
+			continue
+		}
+		if leaf == nil {
+			r.putParams(ps)
+			tsr = tsr || ttsr
+			continue
+		}
+		matches = append(matches, match[W]{route: leaf.route, handle: leaf.handle, ps: ps, offsets: offsets})
+	}
+	sortMatches(matches)
+	return matches, tsr
+}
+
+// putMatches releases the params of the matches.
+func (r *Router[W]) putMatches(matches []match[W]) {
+	for _, m := range matches {
+		r.putParams(m.ps)
+	}
+}
+
+// ServeAll serves a request with every route matching the path.
+//
+// The handles are called in precedence order regardless of the result of the
+// previous handles. Returns true if any handle returned true and the errors
+// returned by the handles joined with errors.Join. Overlapping routes can be
+// registered with RouterConfig.Fallthrough.
+//
+// Trailing slash and fixed path redirects are not applied. If no route matches
+// the NotFound handler is called, if set.
+func (r *Router[W]) ServeAll(ctx context.Context, reqPath string, wr W) (bool, error) {
+	if r.conf.Observer != nil {
+		return r.observe(reqPath, func(matched *string) (bool, error) {
+			return r.serveAll(ctx, reqPath, wr, matched)
+		})
+	}
+	return r.serveAll(ctx, reqPath, wr, nil)
+}
+
+// serveAll serves a request with every route matching the path, see ServeAll.
+// Sets matched to the pattern of the first route handling the request, if not
+// nil.
+func (r *Router[W]) serveAll(ctx context.Context, reqPath string, wr W, matched *string) (found bool, err error) {
+	if r.recoverPanics() {
+		defer r.recoverPanic(ctx, reqPath, wr, &found, &err)
+	}
+	if err := r.ctxErr(ctx); err != nil {
+		return r.canceled(ctx, reqPath, wr, err)
+	}
+	if err := r.checkLimits(reqPath); err != nil {
+		return r.rejected(ctx, reqPath, wr, err)
+	}
+
+	reqPath, err = r.normalizePath(reqPath)
+	if err != nil {
+		return r.pathError(ctx, reqPath, wr, err)
+	}
+
+	t := r.load()
+	matches, tsr := r.matchAll(t, reqPath)
+	if toPath, ok := r.matchBothPath(reqPath, tsr); ok && len(matches) == 0 {
+		reqPath = toPath
+		matches, _ = r.matchAll(t, reqPath)
+	}
+	if len(matches) == 0 {
+		return r.notFound(ctx, reqPath, wr)
+	}
+	defer r.putMatches(matches)
+
+	var anyFound bool
+	var errs []error
+	for _, m := range matches {
+		if err := r.ctxErr(ctx); err != nil {
+			found, err := r.canceled(ctx, fromTree(reqPath, r.conf.Separator), wr, err)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			return anyFound || found, joinErrors(errs)
+		}
+		var handled string
+		found, err := r.callHandle(ctx, reqPath, m, wr, &handled)
+		if matched != nil && *matched == "" {
+			*matched = handled
+		}
+		anyFound = anyFound || found
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return anyFound, joinErrors(errs)
+}
+
+// joinErrors joins the errors returned by the handles of the matched routes,
+// see ServeAll. github.com/pkg/errors has no equivalent of errors.Join.
+func joinErrors(errs []error) error {
+	return errors.Join(errs...)
+}
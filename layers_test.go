@@ -0,0 +1,92 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestComparePatterns(t *testing.T) {
+	// pairs of patterns matching the same path ordered by precedence
+	pairs := [][2]string{
+		{"/user/new", "/user/:name"},
+		{"/user/new", "/user/*rest"},
+		{"/user/:name", "/user/*rest"},
+		{"/user/new/profile", "/user/:name/profile"},
+		{"/user/:name/profile", "/user/*rest"},
+		{"/user/new", "/:section/new"},
+		{"/:section/new", "/*all"},
+		{"/files/", "/files/*filepath"},
+		{"/src/v1/:file", "/src/:version/:file"},
+	}
+	parse := func(pattern string) []patternPart {
+		parts, _, err := parsePattern(pattern)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return parts
+	}
+	for _, pair := range pairs {
+		a, b := parse(pair[0]), parse(pair[1])
+		if cmp := comparePatterns(a, b); cmp >= 0 {
+			t.Errorf("expected %s to precede %s: %d", pair[0], pair[1], cmp)
+		}
+		if cmp := comparePatterns(b, a); cmp <= 0 {
+			t.Errorf("expected %s to follow %s: %d", pair[1], pair[0], cmp)
+		}
+		if cmp := comparePatterns(a, a); cmp != 0 {
+			t.Errorf("expected %s to equal itself: %d", pair[0], cmp)
+		}
+	}
+}
+
+func TestRouterServeAll(t *testing.T) {
+	var calls []string
+	handler := func(name string, found bool, err error) Handle[struct{}] {
+		return func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			calls = append(calls, name)
+			return found, err
+		}
+	}
+
+	conf := DefaultConfig[struct{}]()
+	conf.Fallthrough = true
+	var notFound bool
+	conf.NotFound = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		notFound = true
+		return false, nil
+	}
+	router := NewWithConfig(conf)
+	errA, errB := errors.New("a"), errors.New("b")
+	router.AddHandler("/events/*topic", handler("all", false, errA))
+	router.AddHandler("/events/user/:id", handler("user", true, nil))
+	router.AddHandler("/events/user/created", handler("created", false, errB))
+	router.AddHandler("/events/order/:id", handler("order", false, nil))
+
+	ctx := context.Background()
+	found, err := router.ServeAll(ctx, "/events/user/created", struct{}{})
+	if !found {
+		t.Error("expected found")
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("expected joined errors, got %v", err)
+	}
+	if want := []string{"created", "user", "all"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected calls %q but got %q", want, calls)
+	}
+
+	calls = nil
+	found, err = router.ServeAll(ctx, "/events/order/1", struct{}{})
+	if found || !errors.Is(err, errA) {
+		t.Errorf("unexpected result found=%v err=%v", found, err)
+	}
+	if want := []string{"order", "all"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected calls %q but got %q", want, calls)
+	}
+
+	calls = nil
+	if found, err := router.ServeAll(ctx, "/nope", struct{}{}); found || err != nil || !notFound {
+		t.Errorf("expected not found, got found=%v err=%v", found, err)
+	}
+}
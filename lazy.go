@@ -0,0 +1,144 @@
+package pathrouter
+
+import (
+	"math"
+	"strings"
+)
+
+// LookupOption configures Lookup.
+type LookupOption func(o *lookupOptions)
+
+// lookupOptions contains the options for Lookup.
+type lookupOptions struct {
+	// lazy defers extracting the params, see LazyParams.
+	lazy bool
+}
+
+// newLookupOptions applies the options.
+func newLookupOptions(opts []LookupOption) lookupOptions {
+	if len(opts) == 0 {
+		// avoid allocating the options passed to the funcs
+		return lookupOptions{}
+	}
+	o := new(lookupOptions)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return *o
+}
+
+// LazyParams configures Lookup to match the path without extracting the
+// params: LookupResult.Params is nil until LookupResult.LoadParams is called,
+// which decodes them from the offsets recorded while matching the path.
+//
+// This saves the params allocation for callers which usually ignore the
+// params. The params of redirect routes are always extracted.
+func LazyParams() LookupOption {
+	return func(o *lookupOptions) {
+		o.lazy = true
+	}
+}
+
+// maxLazyParams is the maximum number of param offsets recorded by a lookup.
+// The params of routes with more params are extracted by walking the tree
+// again, see LoadParams.
+const maxLazyParams = 8
+
+// paramOffsets contains the offsets of the param values in a path recorded by
+// getValue, see LazyParams.
+type paramOffsets struct {
+	// offsets contains the start offsets of the values.
+	offsets [maxLazyParams]uint32
+	// n is the number of params matched.
+	n int
+}
+
+// add records the offset of the next param value.
+func (o *paramOffsets) add(off int) {
+	if o.n < maxLazyParams && uint64(off) <= math.MaxUint32 {
+		o.offsets[o.n] = uint32(off)
+	} else {
+		// not decodable: see params
+		o.n = maxLazyParams
+	}
+	o.n++
+}
+
+// lazyParams contains the state to extract the params of a lookup result.
+type lazyParams[W any] struct {
+	router *Router[W]
+	// table is the route table the path was matched in.
+	table *table[W]
+	// route is the matched route.
+	route *route[W]
+	// path is the matched path in the form used by the trees.
+	path string
+	// offsets contains the offsets of the params in the path.
+	offsets paramOffsets
+}
+
+// params decodes the params from the offsets with the names of the wildcards
+// of the route. A param value ends at the next '/', a catch-all value at the end
+// of the path. Returns false if the offsets were not all recorded.
+func (l *lazyParams[W]) params() (Params, bool) {
+	n := l.offsets.n
+	if n == 0 {
+		return nil, true
+	}
+	if n > maxLazyParams || l.route == nil {
+		return nil, false
+	}
+	ps := make(Params, 0, n)
+	for _, part := range l.route.parts {
+		if part.kind != param && part.kind != catchAll {
+			continue
+		}
+		if len(ps) == n {
+			return nil, false
+		}
+		start, end := int(l.offsets.offsets[len(ps)]), len(l.path)
+		if part.kind == param {
+			if i := strings.IndexByte(l.path[start:], '/'); i >= 0 {
+				end = start + i
+			}
+		}
+		ps = append(ps, Param{Key: part.value, Value: l.path[start:end]})
+	}
+	if len(ps) != n {
+		return nil, false
+	}
+	return ps, true
+}
+
+// LoadParams returns the params of the matched route, extracting them if the
+// lookup used LazyParams, and sets Params.
+func (l *LookupResult[W]) LoadParams() Params {
+	lazy := l.lazy
+	if lazy.router == nil {
+		return l.Params
+	}
+	l.lazy = lazyParams[W]{}
+
+	r := lazy.router
+	if ps, ok := lazy.params(); ok {
+		l.Params = ps
+		paramsFromTree(l.Params, r.conf.Separator)
+	} else {
+		for _, tree := range lazy.table.trees {
+			leaf, ps, _ := tree.getValue(lazy.path, r.getParams, nil)
+			if leaf == nil || leaf.route != lazy.route {
+				r.putParams(ps)
+				continue
+			}
+			if ps != nil {
+				l.Params = *ps
+				paramsFromTree(l.Params, r.conf.Separator)
+			}
+			break
+		}
+	}
+	l.Params = r.afterMatch(lazy.route, lazy.route.applyDefaults(l.Params))
+	return l.Params
+}
@@ -0,0 +1,117 @@
+package pathrouter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRouterLookupLazyParams(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.AfterMatch = func(pattern string, ps Params) Params {
+		if pattern == "/shout/:word" {
+			ps = append(Params(nil), ps...)
+			ps[0].Value += "!"
+		}
+		return ps
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	router.AddHandler("/src/*filepath", fakeHandler("/src/*filepath"))
+	router.AddHandler("/shout/:word", fakeHandler("/shout/:word"))
+	router.AddHandler("/static", fakeHandler("/static"))
+	router.AddRedirect("/old/:name", "/user/:name")
+	router.AddRewrite("/me/:name", "/user/:name")
+
+	tests := []struct {
+		path         string
+		pattern      string
+		params       Params
+		redirectPath string
+	}{
+		{"/user/gopher", "/user/:name", Params{{"name", "gopher"}}, ""},
+		{"/src/a/b", "/src/*filepath", Params{{"filepath", "/a/b"}}, ""},
+		{"/shout/hey", "/shout/:word", Params{{"word", "hey!"}}, ""},
+		{"/static", "/static", nil, ""},
+		{"/old/gopher", "/old/:name", Params{{"name", "gopher"}}, "/user/gopher"},
+		{"/me/gopher", "/user/:name", Params{{"name", "gopher"}}, ""},
+		{"/nope", "", nil, ""},
+	}
+	for _, test := range tests {
+		res := router.Lookup(test.path, LazyParams())
+		if res.Pattern != test.pattern || res.RedirectPath != test.redirectPath {
+			t.Errorf("path %s: expected pattern %q redirect %q but got %q %q", test.path, test.pattern, test.redirectPath, res.Pattern, res.RedirectPath)
+		}
+		if ps := res.LoadParams(); !reflect.DeepEqual(ps, test.params) || !reflect.DeepEqual(res.Params, test.params) {
+			t.Errorf("path %s: expected params %v but got %v", test.path, test.params, ps)
+		}
+		if eager := router.Lookup(test.path); !reflect.DeepEqual(eager.Params, res.Params) {
+			t.Errorf("path %s: expected lazy params %v to equal eager params %v", test.path, res.Params, eager.Params)
+		}
+	}
+
+	res := router.Lookup("/user/gopher", LazyParams())
+	if res.Params != nil {
+		t.Errorf("expected no params before LoadParams, got %v", res.Params)
+	}
+	eager := testing.AllocsPerRun(100, func() { _ = router.Lookup("/user/gopher") })
+	lazy := testing.AllocsPerRun(100, func() { _ = router.Lookup("/user/gopher", LazyParams()) })
+	if lazy >= eager {
+		t.Errorf("expected lazy lookup to allocate less than eager lookup, got %v and %v allocations", lazy, eager)
+	}
+}
+
+func TestRouterLookupLazyParamsOffsets(t *testing.T) {
+	many := "/many/:a/:b/:c/:d/:e/:f/:g/:h/:i"
+	for _, sep := range []byte{'/', '.'} {
+		router := NewWithConfig(RouterConfig[struct{}]{Separator: sep, Fallthrough: true})
+		for _, pattern := range []string{"/user/:name/posts/:id", "/user/:name/*rest", "/files/*path", many} {
+			if sep != '/' {
+				pattern = strings.ReplaceAll(strings.TrimPrefix(pattern, "/"), "/", string(sep))
+			}
+			router.AddHandler(pattern, fakeHandler(pattern))
+		}
+		for _, path := range []string{
+			"/user/gopher/posts/42",
+			"/user/gopher/about/me",
+			"/files/a/b.txt",
+			"/many/1/2/3/4/5/6/7/8/9",
+		} {
+			if sep != '/' {
+				path = strings.TrimPrefix(path, "/")
+				path = strings.ReplaceAll(strings.ReplaceAll(path, ".", "-"), "/", string(sep))
+			}
+			lazy := router.Lookup(path, LazyParams())
+			eager := router.Lookup(path)
+			if lazy.Pattern != eager.Pattern || !reflect.DeepEqual(lazy.LoadParams(), eager.Params) {
+				t.Errorf("sep %q path %s: expected %q %v but got %q %v", sep, path, eager.Pattern, eager.Params, lazy.Pattern, lazy.Params)
+			}
+		}
+	}
+}
+
+func BenchmarkRouterLookupLazyParams(b *testing.B) {
+	router := New[struct{}]()
+	router.AddHandler("/api/:version/users/:id/posts/:post", fakeHandler("posts"))
+	const path = "/api/v1/users/42/posts/7"
+
+	b.Run("eager", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = router.Lookup(path)
+		}
+	})
+	b.Run("lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = router.Lookup(path, LazyParams())
+		}
+	})
+	b.Run("lazy/load", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			res := router.Lookup(path, LazyParams())
+			_ = res.LoadParams()
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package pathrouter
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pkg/errors"
+)
+
+// ErrLimited is returned for requests rejected by the Limiter if no
+// LimitedHandler is set.
+var ErrLimited = errors.New("request rejected by limiter")
+
+// Limiter decides if a request matching a route is handled, see
+// RouterConfig.Limiter.
+type Limiter interface {
+	// Allow checks if the request matching the route with the pattern and the
+	// params is handled. The params are only valid until Allow returns.
+	Allow(ctx context.Context, pattern string, params Params) bool
+}
+
+// LimiterFunc is a func implementing Limiter.
+type LimiterFunc func(ctx context.Context, pattern string, params Params) bool
+
+// Allow calls the func.
+func (f LimiterFunc) Allow(ctx context.Context, pattern string, params Params) bool {
+	return f(ctx, pattern, params)
+}
+
+// allow consults the Limiter for the match.
+func (r *Router[W]) allow(ctx context.Context, reqPath string, m match[W], params Params) bool {
+	var pattern string
+	if m.route != nil {
+		pattern = m.route.matchedPattern()
+	}
+	if r.conf.Limiter.Allow(ctx, pattern, params) {
+		return true
+	}
+	if r.debugEnabled(ctx) {
+		r.logDebug(ctx, "request limited", slog.String("path", reqPath), slog.String("pattern", pattern))
+	}
+	return false
+}
+
+// limited handles a request rejected by the Limiter.
+func (r *Router[W]) limited(ctx context.Context, reqPath string, params Params, wr W) (bool, error) {
+	if r.conf.LimitedHandler != nil {
+		return r.conf.LimitedHandler(ctx, reqPath, params, wr)
+	}
+	return false, ErrLimited
+}
+
+// _ is a type assertion
+var _ Limiter = LimiterFunc(nil)
@@ -0,0 +1,47 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRouterLimiter(t *testing.T) {
+	var checked []string
+	conf := DefaultConfig[struct{}]()
+	conf.Limiter = LimiterFunc(func(ctx context.Context, pattern string, params Params) bool {
+		checked = append(checked, pattern+" "+params.ByName("tenant"))
+		return params.ByName("tenant") != "noisy"
+	})
+	router := NewWithConfig(conf)
+	router.AddHandler("/t/:tenant/items", fakeHandler("items"))
+
+	ctx := context.Background()
+	fakeHandlerValue = ""
+	if found, err := router.Serve(ctx, "/t/quiet/items", struct{}{}); !found || err != nil || fakeHandlerValue != "items" {
+		t.Errorf("expected allowed request to be handled, got found=%v err=%v", found, err)
+	}
+	fakeHandlerValue = ""
+	if found, err := router.Serve(ctx, "/t/noisy/items", struct{}{}); found || !errors.Is(err, ErrLimited) || fakeHandlerValue != "" {
+		t.Errorf("expected limited request, got found=%v err=%v", found, err)
+	}
+	if want := []string{"/t/:tenant/items quiet", "/t/:tenant/items noisy"}; len(checked) != 2 || checked[0] != want[0] || checked[1] != want[1] {
+		t.Errorf("expected limiter calls %v but got %v", want, checked)
+	}
+
+	// not consulted for unmatched paths
+	checked = nil
+	_, _ = router.Serve(ctx, "/missing", struct{}{})
+	if len(checked) != 0 {
+		t.Errorf("unexpected limiter calls %v", checked)
+	}
+
+	conf.LimitedHandler = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return true, errors.New("slow down " + p.ByName("tenant"))
+	}
+	withHandler := NewWithConfig(conf)
+	withHandler.AddHandler("/t/:tenant", fakeHandler(""))
+	if found, err := withHandler.Serve(ctx, "/t/noisy", struct{}{}); !found || err == nil || err.Error() != "slow down noisy" {
+		t.Errorf("expected limited handler result, got found=%v err=%v", found, err)
+	}
+}
@@ -0,0 +1,52 @@
+package pathrouter
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPathRejected is returned for request paths exceeding the limits if no
+// RejectedHandler is set, see RouterConfig.MaxPathLength.
+var ErrPathRejected = errors.New("request path rejected by limits")
+
+// checkLimits checks the request path against MaxPathLength and MaxSegments.
+func (r *Router[W]) checkLimits(reqPath string) error {
+	segments := -1
+	if r.conf.MaxSegments > 0 {
+		sep := "/"
+		if customSeparator(r.conf.Separator) {
+			sep = string(r.conf.Separator)
+		}
+		segments = strings.Count(reqPath, sep)
+		if !strings.HasPrefix(reqPath, sep) {
+			segments++
+		}
+	}
+	return r.checkPathLimits(len(reqPath), segments)
+}
+
+// checkPathLimits checks the length and the number of segments of a path
+// against MaxPathLength and MaxSegments, see checkLimits.
+func (r *Router[W]) checkPathLimits(length, segments int) error {
+	if maxLen := r.conf.MaxPathLength; maxLen > 0 && length > maxLen {
+		return errors.Wrapf(ErrPathRejected, "path length %d exceeds %d", length, maxLen)
+	}
+	if maxSegments := r.conf.MaxSegments; maxSegments > 0 && segments > maxSegments {
+		return errors.Wrapf(ErrPathRejected, "path has %d segments, exceeds %d", segments, maxSegments)
+	}
+	return nil
+}
+
+// rejected handles a request path exceeding the limits.
+func (r *Router[W]) rejected(ctx context.Context, reqPath string, wr W, err error) (bool, error) {
+	if r.debugEnabled(ctx) {
+		r.logDebug(ctx, "rejected path", slog.Int("length", len(reqPath)), slog.Any("error", err))
+	}
+	if r.conf.RejectedHandler != nil {
+		return r.conf.RejectedHandler(ctx, reqPath, wr, err)
+	}
+	return false, err
+}
@@ -0,0 +1,62 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRouterLimits(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.MaxPathLength = 16
+	conf.MaxSegments = 3
+	router := NewWithConfig(conf)
+	router.AddHandler("/a/:b/*c", fakeHandler("abc"))
+
+	tests := []struct {
+		path     string
+		rejected bool
+	}{
+		{"/a/b/c", false},
+		{"/a/b/c/", true},
+		{"/a/b/c/d", true},
+		{"/a/" + strings.Repeat("b", 14), true},
+		{"/a/" + strings.Repeat("b", 13), false},
+	}
+	for _, tt := range tests {
+		found, err := router.Serve(context.Background(), tt.path, struct{}{})
+		if got := errors.Is(err, ErrPathRejected); got != tt.rejected {
+			t.Errorf("%s: expected rejected=%v, got found=%v err=%v", tt.path, tt.rejected, found, err)
+		}
+		if _, err := router.ServeAll(context.Background(), tt.path, struct{}{}); errors.Is(err, ErrPathRejected) != tt.rejected {
+			t.Errorf("%s: ServeAll: expected rejected=%v, got err=%v", tt.path, tt.rejected, err)
+		}
+		if tt.rejected {
+			if res := router.Lookup(tt.path); res.Handle != nil {
+				t.Errorf("%s: expected no lookup result", tt.path)
+			}
+			if handle, _, _ := router.LookupPath(tt.path); handle != nil {
+				t.Errorf("%s: expected no LookupPath result", tt.path)
+			}
+			if matches := router.LookupAll(tt.path); len(matches) != 0 {
+				t.Errorf("%s: expected no LookupAll result", tt.path)
+			}
+		}
+	}
+
+	var rejected error
+	conf.RejectedHandler = func(ctx context.Context, reqPath string, rw struct{}, err error) (bool, error) {
+		rejected = err
+		return true, nil
+	}
+	conf.Separator = '.'
+	router = NewWithConfig(conf)
+	router.AddHandler("a.b.c", fakeHandler("abc"))
+	if found, err := router.Serve(context.Background(), "a.b.c", struct{}{}); !found || err != nil || rejected != nil {
+		t.Errorf("expected a.b.c to be served, got found=%v err=%v rejected=%v", found, err, rejected)
+	}
+	if found, err := router.Serve(context.Background(), "a.b.c.d", struct{}{}); !found || err != nil || !errors.Is(rejected, ErrPathRejected) {
+		t.Errorf("expected the rejected handler result, got found=%v err=%v rejected=%v", found, err, rejected)
+	}
+}
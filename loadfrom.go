@@ -0,0 +1,246 @@
+package pathrouter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Format is the encoding of a route list read by LoadFrom.
+type Format int
+
+const (
+	// FormatLines is a route per line with the pattern and the handler name
+	// separated by whitespace:
+	//
+	//	# comment
+	//	/user/:name user
+	//	/files/*filepath files
+	//
+	// Empty lines and lines starting with # are ignored.
+	FormatLines Format = iota
+	// FormatJSON is the JSON encoding of MarshalRoutes:
+	//
+	//	{"routes": [{"pattern": "/user/:name", "handler": "user"}]}
+	FormatJSON
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatLines:
+		return "lines"
+	case FormatJSON:
+		return "json"
+	default:
+		return "unknown"
+	}
+}
+
+// LoadFrom registers the routes read from the route list in the given format,
+// looking up the handles by handler name in the registry.
+//
+// The routes are inserted while they are read without buffering the list, so
+// large generated lists can be loaded: other route changes are blocked until
+// the reader is drained. Errors are prefixed with the line number of the
+// route, which is the line the route ends on for FormatJSON. If any of the
+// routes are invalid, conflict or cannot be resolved an error is returned and
+// none of the routes are registered.
+func (r *Router[W]) LoadFrom(rd io.Reader, format Format, registry *HandlerRegistry[W]) error {
+	_, err := r.loadFrom(rd, format, registry, false)
+	return err
+}
+
+// ReplaceFrom replaces all routes with the routes read from the route list,
+// see LoadFrom. The routes are registered in the order of the list, including
+// aliases and redirects. Returns the new route table version.
+//
+// If any of the routes are invalid, conflict or cannot be resolved an error is
+// returned and the router is not changed.
+func (r *Router[W]) ReplaceFrom(rd io.Reader, format Format, registry *HandlerRegistry[W]) (uint64, error) {
+	return r.loadFrom(rd, format, registry, true)
+}
+
+// loadFrom registers the routes read from the route list, replacing the
+// routes if replace is set. Returns the new route table version.
+func (r *Router[W]) loadFrom(rd io.Reader, format Format, registry *HandlerRegistry[W], replace bool) (uint64, error) {
+	var next func() (RouteEntry, int, error)
+	switch format {
+	case FormatLines:
+		next = lineEntries(rd)
+	case FormatJSON:
+		next = jsonEntries(rd)
+	default:
+		return 0, errors.Errorf("unknown route list format %d", int(format))
+	}
+
+	var version uint64
+	err := r.update(func(old *table[W]) (*table[W], error) {
+		var t *table[W]
+		if replace {
+			t = old.empty()
+		} else {
+			t = old.clone()
+		}
+		for {
+			entry, line, err := next()
+			if err == io.EOF {
+				break
+			}
+			if err == nil {
+				var rt *route[W]
+				rt, err = r.entryRoute(entry, registry.Lookup)
+				if err == nil {
+					err = r.tryAddEntryRoute(t, rt)
+				}
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", line)
+			}
+		}
+		if replace {
+			t.removeMissing(old)
+		}
+		version = t.version
+		return t, nil
+	})
+	return version, err
+}
+
+// lineEntries returns a func reading the next entry and its line number from
+// a FormatLines route list. Returns io.EOF after the last entry.
+func lineEntries(rd io.Reader) func() (RouteEntry, int, error) {
+	sc := bufio.NewScanner(rd)
+	var line int
+	return func() (RouteEntry, int, error) {
+		for sc.Scan() {
+			line++
+			text := strings.TrimSpace(sc.Text())
+			if text == "" || text[0] == '#' {
+				continue
+			}
+			fields := strings.Fields(text)
+			if len(fields) != 2 {
+				return RouteEntry{}, line, errors.Errorf("expected pattern and handler, got %d fields", len(fields))
+			}
+			return RouteEntry{Pattern: fields[0], Handler: fields[1]}, line, nil
+		}
+		if err := sc.Err(); err != nil {
+			return RouteEntry{}, line + 1, err
+		}
+		return RouteEntry{}, line, io.EOF
+	}
+}
+
+// jsonEntries returns a func reading the next entry and its line number from
+// a FormatJSON route list. Returns io.EOF after the last entry.
+func jsonEntries(rd io.Reader) func() (RouteEntry, int, error) {
+	lc := &lineCounter{rd: rd}
+	dec := json.NewDecoder(lc)
+	// inRoutes indicates the decoder is within the routes array
+	var inRoutes bool
+	return func() (RouteEntry, int, error) {
+		if !inRoutes {
+			if err := seekRoutes(dec); err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return RouteEntry{}, lc.line(dec.InputOffset()), err
+			}
+			inRoutes = true
+		}
+		if !dec.More() {
+			// skip the closing bracket of the routes and the trailing fields
+			_, err := dec.Token()
+			for err == nil && dec.More() {
+				if _, err = dec.Token(); err == nil {
+					err = dec.Decode(new(json.RawMessage))
+				}
+			}
+			if err == nil {
+				_, err = dec.Token()
+			}
+			if err == nil {
+				err = io.EOF
+			} else if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return RouteEntry{}, lc.line(dec.InputOffset()), err
+		}
+
+		var entry RouteEntry
+		err := dec.Decode(&entry)
+		line := lc.line(dec.InputOffset())
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return entry, line, err
+	}
+}
+
+// seekRoutes reads the tokens up to the opening bracket of the routes array.
+func seekRoutes(dec *json.Decoder) error {
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return errors.New("expected route table object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok != "routes" {
+			if err := dec.Decode(new(json.RawMessage)); err != nil {
+				return err
+			}
+			continue
+		}
+		if tok, err := dec.Token(); err != nil {
+			return err
+		} else if tok != json.Delim('[') {
+			return errors.New("expected routes array")
+		}
+		return nil
+	}
+	return errors.New("missing routes array")
+}
+
+// lineCounter counts the lines read from a reader to map the input offsets of
+// a json.Decoder to line numbers.
+type lineCounter struct {
+	rd io.Reader
+	// read is the number of bytes read.
+	read int64
+	// lines is the number of newlines before the first pending newline.
+	lines int
+	// newlines contains the offsets of the newlines read but not passed yet.
+	newlines []int64
+}
+
+// Read reads from the underlying reader recording the newline offsets.
+func (c *lineCounter) Read(p []byte) (int, error) {
+	n, err := c.rd.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			c.newlines = append(c.newlines, c.read+int64(i))
+		}
+	}
+	c.read += int64(n)
+	return n, err
+}
+
+// line returns the line number of the offset.
+// The offsets must be passed in increasing order.
+func (c *lineCounter) line(offset int64) int {
+	var i int
+	for i < len(c.newlines) && c.newlines[i] < offset {
+		i++
+	}
+	c.lines += i
+	c.newlines = c.newlines[:copy(c.newlines, c.newlines[i:])]
+	return c.lines + 1
+}
@@ -0,0 +1,93 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRouterLoadFrom(t *testing.T) {
+	registry := NewHandlerRegistry[struct{}]()
+	registry.Register("user", fakeHandler("user"))
+	registry.Register("files", fakeHandler("files"))
+
+	lines := "# routes\n\n/user/:name user\n  /files/*filepath\tfiles  \n"
+	source := New[struct{}]()
+	source.AddHandler("/user/:name", registry.Lookup("user"), WithName("user"), WithMetadata("scope", "admin"))
+	source.AddHandler("/files/*filepath", registry.Lookup("files"), WithName("files"))
+	data, err := source.MarshalRoutes()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, test := range []struct {
+		format Format
+		list   string
+	}{
+		{FormatLines, lines},
+		{FormatJSON, string(data)},
+		{FormatJSON, "{\"version\": 1, \"routes\": [\n" +
+			"{\"pattern\": \"/user/:name\", \"handler\": \"user\"},\n" +
+			"{\"pattern\": \"/files/*filepath\", \"handler\": \"files\"}\n" +
+			"], \"extra\": {\"a\": [1]}}"},
+	} {
+		router := New[struct{}]()
+		if err := router.LoadFrom(strings.NewReader(test.list), test.format, registry); err != nil {
+			t.Fatalf("%v: %v", test.format, err)
+		}
+		if patterns := router.RoutesWithPrefix("/"); !reflect.DeepEqual(patterns, []string{"/user/:name", "/files/*filepath"}) {
+			t.Errorf("%v: unexpected routes %v", test.format, patterns)
+		}
+		fakeHandlerValue = ""
+		if found, _ := router.Serve(context.Background(), "/files/a", struct{}{}); !found || fakeHandlerValue != "files" {
+			t.Errorf("%v: loaded route not served", test.format)
+		}
+	}
+
+	// the router is not changed if any route fails
+	for _, test := range []struct {
+		format Format
+		list   string
+		line   string
+	}{
+		{FormatLines, "/a user\n\n/b unknown\n", "line 3:"},
+		{FormatLines, "/a user\n/user/:id user\n/user/:name user\n", "line 2:"},
+		{FormatLines, "/a user\n/b/: user\n", "line 2:"},
+		{FormatLines, "/a user extra\n", "line 1:"},
+		{FormatJSON, "{\"routes\": [\n{\"pattern\": \"/a\", \"handler\": \"user\"},\n{\"pattern\": \"/b\", \"handler\": \"unknown\"}\n]}", "line 3:"},
+		{FormatJSON, "{\"routes\": [\n{\"pattern\": \"/a\", \"handler\": \"user\"},\n", "line 2:"},
+		{FormatJSON, "[]", "line 1:"},
+		{FormatJSON, "{}", "line 1:"},
+		{Format(-1), "", ""},
+	} {
+		router := New[struct{}]()
+		router.AddHandler("/user/:name", fakeHandler("user"))
+		version := router.Version()
+		err := router.LoadFrom(strings.NewReader(test.list), test.format, registry)
+		if err == nil || !strings.HasPrefix(err.Error(), test.line) {
+			t.Errorf("%v %q: expected error with prefix %q, got %v", test.format, test.list, test.line, err)
+		}
+		if router.Version() != version || router.HasRoute("/a") {
+			t.Errorf("%v %q: expected the router not to change", test.format, test.list)
+		}
+	}
+}
+
+func BenchmarkRouterLoadFrom(b *testing.B) {
+	registry := NewHandlerRegistry[struct{}]()
+	registry.Register("item", fakeHandler("item"))
+	var sb strings.Builder
+	for i := 0; i < 10000; i++ {
+		sb.WriteString("/api/item" + strconv.Itoa(i) + "/:id item\n")
+	}
+	list := sb.String()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := New[struct{}]().LoadFrom(strings.NewReader(list), FormatLines, registry); err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package pathrouter
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// AddLocaleAlias registers the alias pattern as the translation for the locale
+// of the route registered with the canonical pattern, for example
+// /de/produkte/:id for /products/:id, see AddAlias.
+//
+// Paths matched by the alias are reported with the locale and the canonical
+// pattern by Lookup and LookupAll, and the locale is attached to the context
+// passed to the handle, see LocaleFromContext.
+//
+// Panics if the alias is invalid as for AddAlias or if the canonical route
+// already has an alias for the locale.
+func (r *Router[W]) AddLocaleAlias(locale, aliasPattern, canonicalPattern string) {
+	if err := r.AddLocaleAliases(canonicalPattern, map[string]string{locale: aliasPattern}); err != nil {
+		panic(err.Error())
+	}
+}
+
+// AddLocaleAliases registers the alias patterns by locale as translations of
+// the route registered with the canonical pattern, see AddLocaleAlias.
+//
+// The aliases are validated before changing the route table: if any alias is
+// invalid or conflicts an error is returned and the router is not changed.
+func (r *Router[W]) AddLocaleAliases(canonicalPattern string, aliases map[string]string) error {
+	locales := make([]string, 0, len(aliases))
+	for locale := range aliases {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	routes := make([]*route[W], 0, len(locales))
+	for _, locale := range locales {
+		if locale == "" {
+			return errors.Errorf("empty locale for alias '%s'", aliases[locale])
+		}
+		alias, err := newRoute[W](r.conf.UnicodeForm.Normalize(aliases[locale]), nil, r.conf.Separator)
+		if err != nil {
+			return err
+		}
+		alias.locale = locale
+		routes = append(routes, alias)
+	}
+
+	return r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		for _, alias := range routes {
+			if err := r.tryAddAlias(t, alias, canonicalPattern); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	})
+}
+
+// LocalePattern returns the alias pattern registered for the locale of the
+// route with the canonical pattern, for example to build links to the
+// translated page.
+func (r *Router[W]) LocalePattern(canonicalPattern, locale string) (string, bool) {
+	t := r.load()
+	canonical := r.routeByPattern(t, canonicalPattern)
+	if canonical == nil {
+		return "", false
+	}
+	if alias := localeAlias(t, canonical.pattern, locale); alias != nil {
+		return alias.pattern, true
+	}
+	return "", false
+}
+
+// localeAlias returns the alias of the canonical pattern for the locale or nil.
+func localeAlias[W any](t *table[W], canonicalPattern, locale string) *route[W] {
+	for _, rt := range t.routes {
+		if rt.locale == locale && rt.canonical == canonicalPattern {
+			return rt
+		}
+	}
+	return nil
+}
+
+// paramNames returns the sorted wildcard names of the pattern parts.
+func paramNames(parts []patternPart) []string {
+	var names []string
+	for _, part := range parts {
+		if part.kind != static {
+			names = append(names, part.value)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
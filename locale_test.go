@@ -0,0 +1,74 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRouterAddLocaleAlias(t *testing.T) {
+	router := New[struct{}]()
+
+	var got string
+	router.AddHandler("/products/:id", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		got = LocaleFromContext(ctx) + " " + p.ByName("id")
+		return true, nil
+	}, WithName("product"))
+	router.AddLocaleAlias("en", "/en/products/:id", "/products/:id")
+	if err := router.AddLocaleAliases("/products/:id", map[string]string{
+		"de": "/de/produkte/:id",
+		"fr": "/fr/produits/{id}",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		locale string
+		want   string
+	}{
+		{"/products/1", "", " 1"},
+		{"/en/products/2", "en", "en 2"},
+		{"/de/produkte/3", "de", "de 3"},
+		{"/fr/produits/4", "fr", "fr 4"},
+	}
+	for _, tt := range tests {
+		got = ""
+		if found, err := router.Serve(context.Background(), tt.path, struct{}{}); !found || err != nil || got != tt.want {
+			t.Errorf("%s: expected %q, got %q found=%v err=%v", tt.path, tt.want, got, found, err)
+		}
+		res := router.Lookup(tt.path)
+		if res.Pattern != "/products/:id" || res.Locale != tt.locale || res.Name != "product" {
+			t.Errorf("%s: unexpected lookup result %+v", tt.path, res)
+		}
+		if matches := router.LookupAll(tt.path); len(matches) != 1 || matches[0].Locale != tt.locale {
+			t.Errorf("%s: unexpected LookupAll result %+v", tt.path, matches)
+		}
+	}
+
+	if pattern, ok := router.LocalePattern("/products/{id}", "de"); !ok || pattern != "/de/produkte/:id" {
+		t.Errorf("expected de pattern, got %q %v", pattern, ok)
+	}
+	if _, ok := router.LocalePattern("/products/:id", "it"); ok {
+		t.Error("expected no it pattern")
+	}
+
+	// the aliases are added atomically
+	version := router.Version()
+	errs := []map[string]string{
+		{"it": "/it/prodotti/:id", "es": "/es/productos/:slug"},
+		{"it": "/it/prodotti/:id", "de": "/de/artikel/:id"},
+		{"it": "/it/prodotti/:id", "nl": "/de/produkte/:id"},
+		{"": "/xx/:id"},
+	}
+	for _, aliases := range errs {
+		if err := router.AddLocaleAliases("/products/:id", aliases); err == nil {
+			t.Errorf("%v: expected error", aliases)
+		}
+	}
+	if router.Version() != version || router.HasRoute("/it/prodotti/:id") {
+		t.Error("expected the router not to be changed")
+	}
+	if err := router.AddLocaleAliases("/missing/:id", map[string]string{"it": "/it/:id"}); err == nil {
+		t.Error("expected error for missing canonical route")
+	}
+}
@@ -0,0 +1,25 @@
+package pathrouter
+
+import (
+	"context"
+	"log/slog"
+)
+
+// debugEnabled checks if debug logging is enabled, see RouterConfig.Logger.
+func (r *Router[W]) debugEnabled(ctx context.Context) bool {
+	return r.conf.Logger != nil && r.conf.Logger.Enabled(ctx, slog.LevelDebug)
+}
+
+// logDebug logs a message at the debug level.
+func (r *Router[W]) logDebug(ctx context.Context, msg string, attrs ...slog.Attr) {
+	r.conf.Logger.LogAttrs(ctx, slog.LevelDebug, msg, attrs...)
+}
+
+// paramsAttr returns a group attribute with the params.
+func paramsAttr(ps Params) slog.Attr {
+	attrs := make([]any, len(ps))
+	for i, p := range ps {
+		attrs[i] = slog.String(p.Key, p.Value)
+	}
+	return slog.Group("params", attrs...)
+}
@@ -0,0 +1,53 @@
+package pathrouter
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRouterLogger(t *testing.T) {
+	var buf bytes.Buffer
+	conf := DefaultConfig[struct{}]()
+	conf.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	conf.PathNormalizer = strings.ToLower
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", fakeHandler("user"))
+	router.AddHandler("/dir/", fakeHandler("dir"))
+
+	ctx := context.Background()
+	for _, path := range []string{"/USER/Gopher", "/dir", "/missing"} {
+		_, _ = router.Serve(ctx, path, struct{}{})
+	}
+
+	expected := []string{
+		`level=DEBUG msg="normalized path" path=/USER/Gopher normalized=/user/gopher`,
+		`level=DEBUG msg="matched route" path=/user/gopher pattern=/user/:name params.name=gopher handled=true`,
+		`level=DEBUG msg="corrected path" path=/dir corrected=/dir/ reason="trailing slash" redirect=false`,
+		`level=DEBUG msg="matched route" path=/dir/ pattern=/dir/ handled=true`,
+		`level=DEBUG msg="no route handled path" path=/missing`,
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected log output:\n%s\nexpected:\n%s", buf.String(), strings.Join(expected, "\n"))
+	}
+
+	// debug logging disabled by level
+	buf.Reset()
+	conf.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+	router = NewWithConfig(conf)
+	router.AddHandler("/", fakeHandler("/"))
+	_, _ = router.Serve(ctx, "/", struct{}{})
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %s", buf.String())
+	}
+}
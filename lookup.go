@@ -0,0 +1,266 @@
+package pathrouter
+
+// LookupResult is the result of looking up a path with Lookup.
+type LookupResult[W any] struct {
+	// Handle is the matched handle or nil if not found.
+	Handle Handle[W]
+	// Params contains the path params of the matched route.
+	// Not pooled: safe to retain. Nil until LoadParams is called if looked
+	// up with LazyParams.
+	Params Params
+	// Pattern is the pattern of the matched route or the canonical pattern if
+	// the route is an alias, see AddAlias.
+	Pattern string
+	// Locale is the locale of the matched alias, if any, see AddLocaleAlias.
+	Locale string
+	// Name is the handler name of the matched route, if any, see WithName.
+	Name string
+	// Metadata contains the metadata of the matched route, if any, see
+	// WithMetadata. Shared with the route: must not be modified.
+	Metadata map[string]any
+	// TSR indicates a handle exists for the path with an extra (without the)
+	// trailing slash.
+	TSR bool
+	// RedirectPath is the corrected path Serve would redirect to if the path
+	// was not found, according to the trailing slash and fixed path settings,
+	// or the target path of the matched redirect route, see AddRedirect.
+	RedirectPath string
+
+	// lazy contains the state to extract the params, see LazyParams.
+	lazy lazyParams[W]
+}
+
+// Found checks if a handle was found for the path.
+func (l *LookupResult[W]) Found() bool {
+	return l.Handle != nil
+}
+
+// Lookup looks up a path returning the matched route and any redirect the
+// router would apply if the route was not found.
+//
+// If overlapping routes are registered returns the route with precedence.
+// If the path cannot be unescaped returns an empty result. Rewrite routes are
+// followed, see AddRewrite.
+func (r *Router[W]) Lookup(path string, opts ...LookupOption) LookupResult[W] {
+	return r.lookup(path, 0, newLookupOptions(opts))
+}
+
+// lookup looks up a path following at most the maximum number of redirects
+// minus depth rewrites, see Lookup.
+func (r *Router[W]) lookup(path string, depth int, o lookupOptions) LookupResult[W] {
+	var res LookupResult[W]
+	t := r.load()
+	if len(t.trees) == 0 || r.checkLimits(path) != nil {
+		return res
+	}
+	path, err := r.normalizePath(path)
+	if err != nil {
+		return res
+	}
+	return r.lookupNormalized(t, path, depth, o)
+}
+
+// lookupNormalized looks up a path in the form used by the trees, see lookup.
+func (r *Router[W]) lookupNormalized(t *table[W], path string, depth int, o lookupOptions) LookupResult[W] {
+	var res LookupResult[W]
+	var matches []match[W]
+	var tsr bool
+	params := r.getParams
+	if o.lazy {
+		params = nil
+	}
+	matches, tsr = r.matchRoutesParams(t, path, false, params)
+	if len(matches) != 0 {
+		r.putMatches(matches[1:])
+		return r.lookupMatch(t, matches[0], path, depth, o)
+	}
+
+	if toPath, ok := r.matchBothPath(path, tsr); ok && depth < r.maxRedirects() {
+		if res := r.lookupNormalized(t, toPath, depth+1, o); res.Handle != nil {
+			return res
+		}
+	}
+
+	res.TSR = tsr
+	if redirectPath, ok := r.correctPath(t, path, tsr); ok {
+		res.RedirectPath = fromTree(redirectPath, r.conf.Separator)
+		return res
+	}
+	// defaults only apply if the path cannot be corrected, see serveNormalized
+	if fullPath, m, ok := r.matchTrailingDefault(t, path, params); ok {
+		return r.lookupMatch(t, m, fullPath, depth, o)
+	}
+	return res
+}
+
+// lookupMatch builds the result for the route matching the path, see
+// lookupNormalized.
+func (r *Router[W]) lookupMatch(t *table[W], m match[W], path string, depth int, o lookupOptions) LookupResult[W] {
+	var res LookupResult[W]
+	res.Handle = m.handle
+	r.countLookup(m.route)
+	if m.route != nil {
+		res.Pattern, res.Locale = m.route.matchedPattern(), m.route.locale
+		res.Name, res.Metadata = m.route.name, m.route.metadata
+	}
+	if m.ps != nil {
+		res.Params = *m.ps
+		paramsFromTree(res.Params, r.conf.Separator)
+	}
+	if o.lazy {
+		res.lazy = lazyParams[W]{router: r, table: t, route: m.route, path: path, offsets: m.offsets}
+		if m.route != nil && m.route.redirect != "" {
+			// the target path is built from the params
+			res.LoadParams()
+		}
+	} else {
+		res.Params = r.afterMatch(m.route, m.route.applyDefaults(res.Params))
+	}
+	if m.route != nil && m.route.redirect != "" {
+		toPath, err := r.redirectTreePath(m.route.redirect, res.Params)
+		if m.route.rewrite {
+			if err != nil || depth >= r.maxRedirects() {
+				return LookupResult[W]{}
+			}
+			// the params are already normalized
+			return r.lookupNormalized(t, toPath, depth+1, o)
+		}
+		res.RedirectPath = fromTree(toPath, r.conf.Separator)
+	}
+	return res
+}
+
+// routeByPattern returns the route registered with the pattern or nil.
+// The pattern is normalized as when registered.
+func (r *Router[W]) routeByPattern(t *table[W], pattern string) *route[W] {
+	rt, err := newRoute[W](r.conf.UnicodeForm.Normalize(pattern), nil, r.conf.Separator)
+	if err != nil {
+		return nil
+	}
+	return t.routes[rt.pattern]
+}
+
+// HasRoute checks if a route is registered with the pattern.
+//
+// The pattern is compared literally after normalizing it as when registered:
+// /user/:name is registered if /user/:name or /user/{name} was registered, but
+// not if /user/:id was registered.
+func (r *Router[W]) HasRoute(pattern string) bool {
+	return r.routeByPattern(r.load(), pattern) != nil
+}
+
+// GetHandler returns the handle registered with the pattern, see HasRoute.
+func (r *Router[W]) GetHandler(pattern string) (Handle[W], bool) {
+	rt := r.routeByPattern(r.load(), pattern)
+	if rt == nil {
+		return nil, false
+	}
+	return rt.handle, true
+}
+
+// Match is a route matching a path, see LookupAll.
+type Match[W any] struct {
+	// Handle is the handle of the route.
+	Handle Handle[W]
+	// Params contains the path params matched by the route.
+	// Not pooled: safe to retain.
+	Params Params
+	// Pattern is the pattern of the route or the canonical pattern if the
+	// route is an alias, see AddAlias.
+	Pattern string
+	// Locale is the locale of the alias, if any, see AddLocaleAlias.
+	Locale string
+	// Name is the handler name of the route, if any, see WithName.
+	Name string
+	// Metadata contains the metadata of the route, if any, see WithMetadata.
+	// Shared with the route: must not be modified.
+	Metadata map[string]any
+}
+
+// LookupAll looks up a path returning every route matching the path in
+// precedence order, for example /files/readme, /files/:name and /*path.
+//
+// Routes only overlap if RouterConfig.Fallthrough is set: otherwise at most one
+// route is returned. Redirects are not applied. The param defaults are applied
+// as by Lookup, see WithDefault. If the path cannot be unescaped returns nil.
+func (r *Router[W]) LookupAll(path string) []Match[W] {
+	t := r.load()
+	if len(t.trees) == 0 || r.checkLimits(path) != nil {
+		return nil
+	}
+	path, err := r.normalizePath(path)
+	if err != nil {
+		return nil
+	}
+
+	matches, tsr := r.matchAll(t, path)
+	if len(matches) == 0 {
+		if _, corrected := r.correctPath(t, path, tsr); corrected {
+			return nil
+		}
+		_, m, ok := r.matchTrailingDefault(t, path, r.getParams)
+		if !ok {
+			return nil
+		}
+		matches = []match[W]{m}
+	}
+	out := make([]Match[W], len(matches))
+	for i, m := range matches {
+		out[i].Handle = m.handle
+		r.countLookup(m.route)
+		if m.route != nil {
+			out[i].Pattern, out[i].Locale = m.route.matchedPattern(), m.route.locale
+			out[i].Name, out[i].Metadata = m.route.name, m.route.metadata
+		}
+		if m.ps != nil {
+			out[i].Params = append(Params(nil), *m.ps...)
+			paramsFromTree(out[i].Params, r.conf.Separator)
+		}
+		out[i].Params = r.afterMatch(m.route, m.route.applyDefaults(out[i].Params))
+	}
+	r.putMatches(matches)
+	return out
+}
+
+// routesWithPrefix returns the routes with patterns starting with the prefix
+// in registration order.
+func (r *Router[W]) routesWithPrefix(prefix string) []*route[W] {
+	prefix = toTree(r.conf.UnicodeForm.Normalize(prefix), r.conf.Separator)
+	routes := make(map[string]*route[W])
+	for _, tree := range r.load().trees {
+		if n := tree.findPrefix(prefix); n != nil {
+			n.walkRoutes(func(rt *route[W]) {
+				routes[rt.pattern] = rt
+			})
+		}
+	}
+	return sortRoutes(routes)
+}
+
+// RoutesWithPrefix returns the patterns of the routes starting with the literal
+// prefix in registration order, for example /api/billing/invoices and
+// /api/billing/:id for the prefix /api/billing/.
+//
+// Wildcards in the prefix are compared literally: the prefix /user/: returns
+// the routes starting with a named param after /user/. Only the subtree below
+// the prefix is walked.
+func (r *Router[W]) RoutesWithPrefix(prefix string) []string {
+	rts := r.routesWithPrefix(prefix)
+	patterns := make([]string, len(rts))
+	for i, rt := range rts {
+		patterns[i] = rt.pattern
+	}
+	return patterns
+}
+
+// RouteDefsWithPrefix returns the definitions of the routes starting with the
+// literal prefix in registration order, see RoutesWithPrefix.
+// The metadata maps are shared with the routes and must not be modified.
+func (r *Router[W]) RouteDefsWithPrefix(prefix string) []RouteDef[W] {
+	rts := r.routesWithPrefix(prefix)
+	defs := make([]RouteDef[W], len(rts))
+	for i, rt := range rts {
+		defs[i] = rt.routeDef()
+	}
+	return defs
+}
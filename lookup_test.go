@@ -0,0 +1,219 @@
+package pathrouter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouterLookupResult(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	router.AddHandler("/dir/", fakeHandler("/dir/"))
+	router.AddHandler("/src/*filepath", fakeHandler("/src/*filepath"))
+
+	tests := []struct {
+		path         string
+		pattern      string
+		params       Params
+		tsr          bool
+		redirectPath string
+	}{
+		{"/user/gopher", "/user/:name", Params{{"name", "gopher"}}, false, ""},
+		{"/src/a/b", "/src/*filepath", Params{{"filepath", "/a/b"}}, false, ""},
+		{"/dir/", "/dir/", nil, false, ""},
+		{"/dir", "", nil, true, "/dir/"},
+		{"/user/gopher/", "", nil, true, "/user/gopher"},
+		{"/DIR/", "", nil, false, "/dir/"},
+		{"/nope", "", nil, false, ""},
+		{"", "", nil, false, ""},
+	}
+	for _, test := range tests {
+		res := router.Lookup(test.path)
+		if res.Found() != (test.pattern != "") || res.Pattern != test.pattern {
+			t.Errorf("path %s: expected pattern %q but got %q", test.path, test.pattern, res.Pattern)
+		}
+		if !reflect.DeepEqual(res.Params, test.params) {
+			t.Errorf("path %s: expected params %v but got %v", test.path, test.params, res.Params)
+		}
+		if res.TSR != test.tsr || res.RedirectPath != test.redirectPath {
+			t.Errorf("path %s: expected tsr=%v redirect=%q but got tsr=%v redirect=%q", test.path, test.tsr, test.redirectPath, res.TSR, res.RedirectPath)
+		}
+	}
+
+	if res := New[struct{}]().Lookup("/"); res.Found() {
+		t.Error("found route in empty router")
+	}
+}
+
+func TestRouterLookupMetadata(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/admin/:page", fakeHandler("admin"), WithName("admin"), WithMetadata("scopes", []string{"admin"}), WithMetadata("rate", "low"))
+	router.AddHandler("/public", fakeHandler("public"))
+
+	res := router.Lookup("/admin/users")
+	if !res.Found() || res.Name != "admin" {
+		t.Fatalf("unexpected lookup result %+v", res)
+	}
+	if want := map[string]any{"scopes": []string{"admin"}, "rate": "low"}; !reflect.DeepEqual(res.Metadata, want) {
+		t.Errorf("expected metadata %v but got %v", want, res.Metadata)
+	}
+	if res := router.Lookup("/public"); !res.Found() || res.Name != "" || res.Metadata != nil {
+		t.Errorf("unexpected lookup result %+v", res)
+	}
+}
+
+func TestRouterWalk(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/b", fakeHandler("b"), WithMetadata("doc", "second"))
+	router.AddHandler("/a", fakeHandler("a"), WithMetadata("doc", "first"))
+	router.AddHandler("/c", fakeHandler("c"))
+
+	var docs []any
+	err := router.Walk(func(def RouteDef[struct{}]) error {
+		// registering routes while walking does not affect the walk
+		router.AddHandler(def.Pattern+"/x", fakeHandler(""))
+		docs = append(docs, def.Metadata["doc"])
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(docs, []any{"second", "first", nil}) {
+		t.Errorf("unexpected walk result %v %v", docs, err)
+	}
+
+	errStop := errors.New("stop")
+	var n int
+	if err := router.Walk(func(def RouteDef[struct{}]) error {
+		n++
+		return errStop
+	}); err != errStop || n != 1 {
+		t.Errorf("expected walk to stop with the error, got %v after %d routes", err, n)
+	}
+}
+
+func TestRouterHasRoute(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", fakeHandler("user"))
+	router.AddHandler("dir/{$}", fakeHandler("dir"))
+
+	tests := []struct {
+		pattern string
+		exists  bool
+	}{
+		{"/user/:name", true},
+		{"user/{name}", true},
+		{"/user/:id", false},
+		{"/user/gopher", false},
+		{"/dir/{$}", true},
+		{"/dir/", false},
+		{"/invalid/:", false},
+	}
+	for _, test := range tests {
+		if exists := router.HasRoute(test.pattern); exists != test.exists {
+			t.Errorf("pattern %s: expected exists=%v but got %v", test.pattern, test.exists, exists)
+		}
+		handle, ok := router.GetHandler(test.pattern)
+		if ok != test.exists || (handle != nil) != test.exists {
+			t.Errorf("pattern %s: unexpected GetHandler result %v", test.pattern, ok)
+		}
+	}
+
+	handle, _ := router.GetHandler("/user/:name")
+	fakeHandlerValue = ""
+	if _, _ = handle(nil, "/user/gopher", nil, struct{}{}); fakeHandlerValue != "user" {
+		t.Errorf("expected the registered handle, got value %q", fakeHandlerValue)
+	}
+}
+
+func TestRouterLookupAll(t *testing.T) {
+	router := NewWithConfig(RouterConfig[struct{}]{Fallthrough: true})
+	router.AddHandler("/*path", fakeHandler("all"))
+	router.AddHandler("/files/:name", fakeHandler("name"), WithName("file"))
+	router.AddHandler("/files/readme", fakeHandler("readme"))
+	router.AddHandler("/files/*rest", fakeHandler("rest"))
+
+	type result struct {
+		pattern string
+		params  Params
+	}
+	tests := []struct {
+		path    string
+		results []result
+	}{
+		{"/files/readme", []result{
+			{"/files/readme", nil},
+			{"/files/:name", Params{{"name", "readme"}}},
+			{"/files/*rest", Params{{"rest", "/readme"}}},
+			{"/*path", Params{{"path", "/files/readme"}}},
+		}},
+		{"/files/a/b", []result{
+			{"/files/*rest", Params{{"rest", "/a/b"}}},
+			{"/*path", Params{{"path", "/files/a/b"}}},
+		}},
+		{"/other", []result{
+			{"/*path", Params{{"path", "/other"}}},
+		}},
+	}
+	for _, test := range tests {
+		var results []result
+		for _, m := range router.LookupAll(test.path) {
+			if m.Handle == nil {
+				t.Errorf("path %s: nil handle for %s", test.path, m.Pattern)
+			}
+			results = append(results, result{m.Pattern, m.Params})
+		}
+		if !reflect.DeepEqual(results, test.results) {
+			t.Errorf("path %s: expected %v but got %v", test.path, test.results, results)
+		}
+	}
+
+	if matches := router.LookupAll("/files/x"); len(matches) != 3 || matches[0].Name != "file" {
+		t.Errorf("unexpected matches %+v", matches)
+	}
+	if matches := New[struct{}]().LookupAll("/"); matches != nil {
+		t.Errorf("expected no matches in empty router, got %v", matches)
+	}
+	noFallthrough := New[struct{}]()
+	noFallthrough.AddHandler("/dir/", fakeHandler("dir"))
+	if matches := noFallthrough.LookupAll("/dir"); matches != nil {
+		t.Errorf("expected redirects not to be applied, got %v", matches)
+	}
+}
+
+func TestRouterRoutesWithPrefix(t *testing.T) {
+	for _, compiled := range []bool{false, true} {
+		router := NewWithConfig(RouterConfig[struct{}]{Fallthrough: true})
+		router.AddHandler("/api/billing/invoices", fakeHandler(""))
+		router.AddHandler("/api/billing/:id", fakeHandler(""))
+		router.AddHandler("/api/billing", fakeHandler(""))
+		router.AddHandler("/api/users/:name/avatar", fakeHandler(""))
+		router.AddHandler("/api/billing/*rest", fakeHandler(""))
+		router.AddHandler("/static/*filepath", fakeHandler(""))
+		if compiled {
+			router = router.Compile()
+		}
+
+		tests := []struct {
+			prefix   string
+			patterns []string
+		}{
+			{"/api/billing/", []string{"/api/billing/invoices", "/api/billing/:id", "/api/billing/*rest"}},
+			{"/api/bill", []string{"/api/billing/invoices", "/api/billing/:id", "/api/billing", "/api/billing/*rest"}},
+			{"/api/users/:name/", []string{"/api/users/:name/avatar"}},
+			{"/api/users/:id", []string{}},
+			{"/static/*", []string{"/static/*filepath"}},
+			{"/api/billing/invoices/", []string{}},
+			{"/nope", []string{}},
+			{"", []string{"/api/billing/invoices", "/api/billing/:id", "/api/billing", "/api/users/:name/avatar", "/api/billing/*rest", "/static/*filepath"}},
+		}
+		for _, test := range tests {
+			if patterns := router.RoutesWithPrefix(test.prefix); !reflect.DeepEqual(patterns, test.patterns) {
+				t.Errorf("compiled=%v prefix %q: expected %v but got %v", compiled, test.prefix, test.patterns, patterns)
+			}
+		}
+
+		defs := router.RouteDefsWithPrefix("/api/users/")
+		if len(defs) != 1 || defs[0].Pattern != "/api/users/:name/avatar" || defs[0].Handle == nil {
+			t.Errorf("compiled=%v: unexpected definitions %+v", compiled, defs)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package pathrouter
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// routeTableJSON is the JSON encoding of a route table.
+//
+//	{"routes": [{"pattern": "/user/:name", "handler": "user", "metadata": {}}]}
+//
+// The routes are in registration order. Unknown fields are ignored.
+type routeTableJSON struct {
+	// Routes contains the routes.
+	Routes []RouteEntry `json:"routes"`
+}
+
+// MarshalRoutes encodes the route table as JSON.
+//
+// The routes are encoded as RouteEntry in registration order, including the
+// aliases and the redirect and rewrite routes. Every other route must have been
+// registered with a handler name, see WithName. The route table can be
+// re-created with LoadRoutes.
+func (r *Router[W]) MarshalRoutes() ([]byte, error) {
+	t := r.load()
+	out := routeTableJSON{Routes: make([]RouteEntry, 0, len(t.routes))}
+	for _, rt := range sortRoutes(t.routes) {
+		entry, err := r.routeEntry(rt)
+		if err != nil {
+			return nil, err
+		}
+		out.Routes = append(out.Routes, entry)
+	}
+	return json.Marshal(out)
+}
+
+// LoadRoutes registers the routes from a route table encoded by MarshalRoutes.
+//
+// The handles are looked up by handler name with resolve, which returns nil if
+// the name is unknown. If any of the routes are invalid, conflict or cannot be
+// resolved an error is returned and none of the routes are registered.
+func (r *Router[W]) LoadRoutes(data []byte, resolve func(name string) Handle[W]) error {
+	var in routeTableJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return errors.Wrap(err, "parse routes")
+	}
+
+	return r.AddEntries(in.Routes, resolve)
+}
@@ -0,0 +1,128 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRouterMarshalRoutes(t *testing.T) {
+	handles := map[string]Handle[struct{}]{
+		"user":  fakeHandler("user"),
+		"files": fakeHandler("files"),
+	}
+	resolve := func(name string) Handle[struct{}] {
+		return handles[name]
+	}
+
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", handles["user"], WithName("user"), WithMetadata("scope", "admin"))
+	router.AddHandler("/files/*filepath", handles["files"], WithName("files"))
+	router.AddHandler("/exact/{$}", handles["files"], WithName("files"))
+
+	data, err := router.MarshalRoutes()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	want := `{"routes":[` +
+		`{"pattern":"/user/:name","handler":"user","metadata":{"scope":"admin"}},` +
+		`{"pattern":"/files/*filepath","handler":"files"},` +
+		`{"pattern":"/exact/{$}","handler":"files"}]}`
+	if string(data) != want {
+		t.Fatalf("unexpected JSON:\n%s\nexpected:\n%s", data, want)
+	}
+
+	loaded := New[struct{}]()
+	if err := loaded.LoadRoutes(data, resolve); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(loaded.DeltaSince(0).Added[0].Metadata, map[string]any{"scope": "admin"}) {
+		t.Errorf("metadata not loaded: %v", loaded.DeltaSince(0).Added[0])
+	}
+	fakeHandlerValue = ""
+	if found, _ := loaded.Serve(context.Background(), "/user/gopher", struct{}{}); !found || fakeHandlerValue != "user" {
+		t.Errorf("loaded route not served: %v %q", found, fakeHandlerValue)
+	}
+	if again, err := loaded.MarshalRoutes(); err != nil || string(again) != want {
+		t.Errorf("routes do not round-trip: %s %v", again, err)
+	}
+
+	// the router is not changed if any route fails
+	for _, data := range []string{
+		`{"routes":[{"pattern":"/a","handler":"user"},{"pattern":"/b","handler":"unknown"}]}`,
+		`{"routes":[{"pattern":"/a","handler":"user"},{"pattern":"/user/:id","handler":"user"}]}`,
+		`{"routes":[{"pattern":"/a","handler":"user"},{"pattern":"/b/:","handler":"user"}]}`,
+		`{"routes":`,
+	} {
+		version := loaded.Version()
+		if err := loaded.LoadRoutes([]byte(data), resolve); err == nil {
+			t.Errorf("expected error loading %s", data)
+		}
+		if loaded.Version() != version {
+			t.Errorf("router changed by failed load of %s", data)
+		}
+	}
+
+	router.AddHandler("/unnamed", handles["user"])
+	if _, err := router.MarshalRoutes(); err == nil {
+		t.Error("expected error marshaling route without handler name")
+	}
+}
+
+func TestRouterMarshalRoutesAliasRedirect(t *testing.T) {
+	handles := map[string]Handle[struct{}]{
+		"user": fakeHandler("user"),
+	}
+	resolve := func(name string) Handle[struct{}] {
+		return handles[name]
+	}
+
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", handles["user"], WithName("user"), WithTags("public"), WithTimeout(time.Second))
+	router.AddAlias("/u/:name", "/user/:name")
+	router.AddLocaleAlias("de", "/benutzer/:name", "/user/:name")
+	router.AddRedirect("/people/:name", "/user/:name")
+	router.AddRewrite("/p/:name", "/user/:name", WithName("short"))
+
+	data, err := router.MarshalRoutes()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	want := `{"routes":[` +
+		`{"pattern":"/user/:name","handler":"user","tags":["public"],"timeout":"1s"},` +
+		`{"pattern":"/u/:name","alias":"/user/:name"},` +
+		`{"pattern":"/benutzer/:name","alias":"/user/:name","locale":"de"},` +
+		`{"pattern":"/people/:name","redirect":"/user/:name"},` +
+		`{"pattern":"/p/:name","handler":"short","redirect":"/user/:name","rewrite":true}]}`
+	if string(data) != want {
+		t.Fatalf("unexpected JSON:\n%s\nexpected:\n%s", data, want)
+	}
+
+	loaded := New[struct{}]()
+	if err := loaded.LoadRoutes(data, resolve); err != nil {
+		t.Fatal(err.Error())
+	}
+	if loaded.Hash() != router.Hash() {
+		t.Error("expected the loaded routes to have the same hash")
+	}
+	if res := loaded.Lookup("/u/gopher"); res.Pattern != "/user/:name" {
+		t.Errorf("expected the alias to be loaded, got %+v", res)
+	}
+	if res := loaded.Lookup("/people/gopher"); res.RedirectPath != "/user/gopher" {
+		t.Errorf("expected the redirect to be loaded, got %+v", res)
+	}
+
+	for _, data := range []string{
+		`{"routes":[{"pattern":"/a","alias":"/missing"}]}`,
+		`{"routes":[{"pattern":"/a","handler":"user"},{"pattern":"/b","handler":"user","alias":"/a"}]}`,
+		`{"routes":[{"pattern":"/a","handler":"user","locale":"de"}]}`,
+		`{"routes":[{"pattern":"/a","handler":"user","rewrite":true}]}`,
+		`{"routes":[{"pattern":"/a","handler":"user","timeout":"soon"}]}`,
+		`{"routes":[{"pattern":"/a/:id","redirect":"/b/:other"}]}`,
+	} {
+		if err := New[struct{}]().LoadRoutes([]byte(data), resolve); err == nil {
+			t.Errorf("expected error loading %s", data)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package pathrouter
+
+import (
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// ConflictPolicy is how Merge resolves conflicting routes.
+type ConflictPolicy int
+
+const (
+	// ConflictError returns an error if any route conflicts.
+	ConflictError ConflictPolicy = iota
+	// ConflictPreferReceiver keeps the routes of the receiver and skips the
+	// conflicting routes of the other router.
+	ConflictPreferReceiver
+	// ConflictPreferOther replaces the conflicting routes of the receiver with
+	// the routes of the other router.
+	ConflictPreferOther
+)
+
+// String returns the name of the policy.
+func (p ConflictPolicy) String() string {
+	switch p {
+	case ConflictError:
+		return "error"
+	case ConflictPreferReceiver:
+		return "prefer-receiver"
+	case ConflictPreferOther:
+		return "prefer-other"
+	default:
+		return "unknown"
+	}
+}
+
+// Merge registers the routes of the other router with the router, for example
+// to compose the route table from the routers of multiple plugins.
+//
+// Routes conflict if they have the same pattern or, unless
+// RouterConfig.Fallthrough is set, overlap. Conflicts are resolved according
+// to the policy. The routes of the other router are registered in their
+// registration order after the routes of the router with their handles,
+// names, metadata, tags and timeouts. Redirect and rewrite routes redirect to
+// the paths of the router. The merged routes are enabled and their statistics
+// start at zero. The NotFound handles of groups are not merged.
+//
+// Returns an error naming the conflicting route if the policy is
+// ConflictError. If an error is returned the router is not changed. The
+// routers must have the same separator.
+func (r *Router[W]) Merge(other *Router[W], policy ConflictPolicy) error {
+	if r.conf.Separator != other.conf.Separator {
+		return errors.New("cannot merge routers with different separators")
+	}
+	switch policy {
+	case ConflictError, ConflictPreferReceiver, ConflictPreferOther:
+	default:
+		return errors.Errorf("unknown conflict policy %d", int(policy))
+	}
+
+	others := sortRoutes(other.load().routes)
+	return r.update(func(old *table[W]) (*table[W], error) {
+		version := old.version
+		routes := make(map[string]*route[W], len(old.routes)+len(others))
+		for pattern, rt := range old.routes {
+			routes[pattern] = rt
+		}
+
+		// merged contains the copies of the routes of the other router
+		merged := make([]*route[W], 0, len(others))
+		for _, ort := range others {
+			if _, exists := routes[ort.pattern]; exists {
+				switch policy {
+				case ConflictError:
+					return nil, errors.Errorf("merge route '%s': a route is already registered with the pattern", ort.pattern)
+				case ConflictPreferReceiver:
+					continue
+				}
+			}
+			rt := *ort
+			rt.counters, rt.disabled = nil, new(atomic.Bool)
+			if rt.redirect != "" {
+				// redirect to the path in the receiver
+				rt.handle = r.redirectHandle(rt.redirect, rt.rewrite)
+			}
+			version++
+			rt.version = version
+			routes[rt.pattern] = &rt
+			merged = append(merged, &rt)
+		}
+		if len(merged) == 0 {
+			return old, nil
+		}
+
+		if !r.conf.Fallthrough {
+			if err := dropOverlapping(routes, merged, policy); err != nil {
+				return nil, err
+			}
+		}
+		return r.replaceRoutes(old, routes, version)
+	})
+}
+
+// dropOverlapping removes the overlapping routes from routes according to the
+// policy, see Merge. merged contains the routes of the other router.
+func dropOverlapping[W any](routes map[string]*route[W], merged []*route[W], policy ConflictPolicy) error {
+	isMerged := make(map[*route[W]]bool, len(merged))
+	for _, rt := range merged {
+		isMerged[rt] = true
+	}
+
+	// insert the preferred routes first, then try the others
+	var preferred, rest []*route[W]
+	for _, rt := range sortRoutes(routes) {
+		if isMerged[rt] == (policy == ConflictPreferOther) {
+			preferred = append(preferred, rt)
+		} else {
+			rest = append(rest, rt)
+		}
+	}
+	tree := new(node[W])
+	for _, rt := range preferred {
+		updated, err := tryInsertRoute(tree, rt)
+		if err != nil {
+			return errors.Wrapf(err, "merge route '%s'", rt.pattern)
+		}
+		tree = updated
+	}
+	for _, rt := range rest {
+		updated, err := tryInsertRoute(tree, rt)
+		if err == nil {
+			tree = updated
+			continue
+		}
+		if policy == ConflictError {
+			return errors.Wrapf(err, "merge route '%s'", rt.pattern)
+		}
+		delete(routes, rt.pattern)
+	}
+	return nil
+}
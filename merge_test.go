@@ -0,0 +1,120 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRouterMerge(t *testing.T) {
+	build := func() (*Router[struct{}], *Router[struct{}]) {
+		receiver := New[struct{}]()
+		receiver.AddHandler("/shared", fakeHandler("receiver"), WithName("receiver"))
+		receiver.AddHandler("/user/:id", fakeHandler("receiver"), WithName("receiver"))
+		receiver.AddHandler("/a", fakeHandler("a"))
+
+		other := New[struct{}]()
+		other.AddHandler("/plugin/*path", fakeHandler("plugin"), WithName("plugin"), WithTags("plugin"))
+		other.AddHandler("/shared", fakeHandler("other"), WithName("other"))
+		other.AddHandler("/user/:name", fakeHandler("other"), WithName("other"))
+		other.AddRedirect("/old", "/a")
+		return receiver, other
+	}
+
+	tests := []struct {
+		policy   ConflictPolicy
+		patterns []string
+		names    map[string]string
+		err      string
+	}{
+		{ConflictError, nil, nil, "merge route '/shared'"},
+		{
+			ConflictPreferReceiver,
+			[]string{"/shared", "/user/:id", "/a", "/plugin/*path", "/old"},
+			map[string]string{"/shared": "receiver", "/user/:id": "receiver", "/plugin/*path": "plugin"},
+			"",
+		},
+		{
+			ConflictPreferOther,
+			[]string{"/a", "/plugin/*path", "/shared", "/user/:name", "/old"},
+			map[string]string{"/shared": "other", "/user/:name": "other", "/plugin/*path": "plugin"},
+			"",
+		},
+	}
+	for _, test := range tests {
+		receiver, other := build()
+		version := receiver.Version()
+		err := receiver.Merge(other, test.policy)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v: expected error %q, got %v", test.policy, test.err, err)
+			}
+			if receiver.Version() != version {
+				t.Errorf("%v: expected the router not to change", test.policy)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: %v", test.policy, err)
+		}
+
+		var patterns []string
+		for _, def := range receiver.Routes() {
+			patterns = append(patterns, def.Pattern)
+			if name, ok := test.names[def.Pattern]; ok && def.Name != name {
+				t.Errorf("%v: expected route %s from %s, got %s", test.policy, def.Pattern, name, def.Name)
+			}
+		}
+		if !reflect.DeepEqual(patterns, test.patterns) {
+			t.Errorf("%v: expected routes %v but got %v", test.policy, test.patterns, patterns)
+		}
+		if tags := receiver.RoutesByTag("plugin"); !reflect.DeepEqual(tags, []string{"/plugin/*path"}) {
+			t.Errorf("%v: expected tags to be merged, got %v", test.policy, tags)
+		}
+
+		// the merged redirect redirects within the receiver
+		if res := receiver.Lookup("/old"); res.RedirectPath != "/a" {
+			t.Errorf("%v: expected the merged redirect, got %+v", test.policy, res)
+		}
+		fakeHandlerValue = ""
+		if found, err := receiver.Serve(context.Background(), "/plugin/x", struct{}{}); !found || err != nil || fakeHandlerValue != "plugin" {
+			t.Errorf("%v: expected the merged route to be served", test.policy)
+		}
+
+		// the routes are not shared with the other router
+		other.SetRouteEnabled("/plugin/*path", false)
+		if !receiver.RouteEnabled("/plugin/*path") {
+			t.Errorf("%v: expected the merged route state not to be shared", test.policy)
+		}
+	}
+
+	base, _ := build()
+	overlapping := New[struct{}]()
+	overlapping.AddHandler("/user/:name", fakeHandler("name"))
+	if err := base.Merge(overlapping, ConflictError); err == nil || !strings.Contains(err.Error(), "merge route '/user/:name'") {
+		t.Errorf("expected overlapping route error, got %v", err)
+	}
+
+	// overlapping routes are allowed with Fallthrough
+	conf := DefaultConfig[struct{}]()
+	conf.Fallthrough = true
+	receiver, other := NewWithConfig(conf), New[struct{}]()
+	receiver.AddHandler("/user/:id", fakeHandler("id"))
+	other.AddHandler("/user/:name", fakeHandler("name"))
+	if err := receiver.Merge(other, ConflictError); err != nil {
+		t.Fatal(err.Error())
+	}
+	if matches := receiver.LookupAll("/user/gopher"); len(matches) != 2 {
+		t.Errorf("expected both overlapping routes, got %v", matches)
+	}
+
+	sep := DefaultConfig[struct{}]()
+	sep.Separator = '.'
+	if err := NewWithConfig(sep).Merge(other, ConflictError); err == nil {
+		t.Error("expected error merging routers with different separators")
+	}
+	if err := receiver.Merge(other, ConflictPolicy(-1)); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
@@ -0,0 +1,160 @@
+package pathrouter
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MethodNotAllowedHandle is called when no route matches the request method
+// but routes for other methods match the path. allowed contains the methods
+// with a matching route in sorted order.
+type MethodNotAllowedHandle[W any] func(ctx context.Context, method, reqPath string, allowed []string, rw W) (bool, error)
+
+// MethodRouterConfig is the configuration for a MethodRouter.
+type MethodRouterConfig[W any] struct {
+	// RouterConfig is the configuration for the per-method routers.
+	//
+	// The NotFound handler is called by the MethodRouter after checking for
+	// routes with other methods.
+	RouterConfig[W]
+
+	// MethodNotAllowed is called when no route matches the method but routes
+	// for other methods match the path. If nil, NotFound is called instead.
+	MethodNotAllowed MethodNotAllowedHandle[W]
+}
+
+// MethodRouter dispatches requests to a Router per request method.
+//
+// The methods are arbitrary strings, for example GET and POST.
+// Safe to use concurrently.
+type MethodRouter[W any] struct {
+	conf MethodRouterConfig[W]
+
+	mtx     sync.RWMutex
+	routers map[string]*Router[W]
+}
+
+// NewMethodRouter constructs a new MethodRouter with the given config.
+func NewMethodRouter[W any](conf MethodRouterConfig[W]) *MethodRouter[W] {
+	return &MethodRouter[W]{conf: conf}
+}
+
+// AddHandler registers a new request handle with the given method and path.
+func (m *MethodRouter[W]) AddHandler(method, path string, handle Handle[W], opts ...RouteOption) {
+	if method == "" {
+		panic("method must not be empty")
+	}
+	m.Router(method).AddHandler(path, handle, opts...)
+}
+
+// HandlePattern registers a new request handle with a ServeMux style pattern
+// consisting of the method and the path, for example "GET /user/{name}".
+//
+// Panics if the pattern has no method.
+func (m *MethodRouter[W]) HandlePattern(pattern string, handle Handle[W], opts ...RouteOption) {
+	method, path := SplitMethodPattern(pattern)
+	if method == "" {
+		panic("pattern must start with a method: '" + pattern + "'")
+	}
+	m.Router(method).AddHandler(path, handle, opts...)
+}
+
+// Router returns the Router for the method, creating it if necessary.
+func (m *MethodRouter[W]) Router(method string) *Router[W] {
+	if router := m.getRouter(method); router != nil {
+		return router
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if router := m.routers[method]; router != nil {
+		return router
+	}
+	conf := m.conf.RouterConfig
+	conf.NotFound = nil
+	router := NewWithConfig(conf)
+	if m.routers == nil {
+		m.routers = make(map[string]*Router[W])
+	}
+	m.routers[method] = router
+	return router
+}
+
+// Methods returns the methods with a Router in sorted order.
+func (m *MethodRouter[W]) Methods() []string {
+	m.mtx.RLock()
+	methods := make([]string, 0, len(m.routers))
+	for method := range m.routers {
+		methods = append(methods, method)
+	}
+	m.mtx.RUnlock()
+	sort.Strings(methods)
+	return methods
+}
+
+// Allowed returns the methods with a route matching the path in sorted order.
+func (m *MethodRouter[W]) Allowed(path string) []string {
+	return m.allowed(path, "")
+}
+
+// allowed returns the methods other than except with a route matching the
+// path, see Allowed.
+func (m *MethodRouter[W]) allowed(path, except string) []string {
+	var allowed []string
+	for _, method := range m.Methods() {
+		if method == except {
+			continue
+		}
+		if res := m.getRouter(method).Lookup(path); res.Found() {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+// Lookup looks up a path with the Router for the method, see Router.Lookup.
+func (m *MethodRouter[W]) Lookup(method, path string) LookupResult[W] {
+	router := m.getRouter(method)
+	if router == nil {
+		return LookupResult[W]{}
+	}
+	return router.Lookup(path)
+}
+
+// Serve serves a request with the Router for the method.
+//
+// If no route matches or the matched route declines the request, the
+// MethodNotAllowed handler is called if routes for other methods match the
+// path, otherwise the NotFound handler is called.
+func (m *MethodRouter[W]) Serve(ctx context.Context, method, reqPath string, rw W) (bool, error) {
+	if router := m.getRouter(method); router != nil {
+		found, err := router.Serve(ctx, reqPath, rw)
+		if found || err != nil {
+			return found, err
+		}
+	}
+
+	if m.conf.MethodNotAllowed != nil {
+		// the route for the method, if any, declined the request
+		if allowed := m.allowed(reqPath, method); len(allowed) != 0 {
+			return m.conf.MethodNotAllowed(ctx, method, reqPath, allowed, rw)
+		}
+	}
+
+	if m.conf.NotFound == nil {
+		return false, nil
+	}
+	found, err := m.conf.NotFound(ctx, reqPath, nil, rw)
+	if err != nil && m.conf.ErrorHandler != nil {
+		return m.conf.ErrorHandler(ctx, reqPath, nil, rw, err)
+	}
+	return found, err
+}
+
+// getRouter returns the Router for the method or nil if none.
+func (m *MethodRouter[W]) getRouter(method string) *Router[W] {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.routers[method]
+}
@@ -0,0 +1,101 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMethodRouter(t *testing.T) {
+	var notAllowed []string
+	var notFound bool
+	conf := MethodRouterConfig[struct{}]{
+		RouterConfig: DefaultConfig[struct{}](),
+		MethodNotAllowed: func(ctx context.Context, method, reqPath string, allowed []string, rw struct{}) (bool, error) {
+			notAllowed = allowed
+			return true, nil
+		},
+	}
+	conf.NotFound = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		notFound = true
+		return true, nil
+	}
+	router := NewMethodRouter(conf)
+	router.AddHandler("GET", "/user/:name", fakeHandler("GET /user/:name"))
+	router.AddHandler("POST", "/user/:name", fakeHandler("POST /user/:name"))
+	router.AddHandler("PURGE", "/cache/*key", fakeHandler("PURGE /cache/*key"))
+	decline := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, nil
+	}
+	router.AddHandler("GET", "/drafts/:id", decline)
+	router.AddHandler("POST", "/drafts/:id", fakeHandler("POST /drafts/:id"))
+	router.AddHandler("GET", "/private", decline)
+
+	if methods := router.Methods(); !reflect.DeepEqual(methods, []string{"GET", "POST", "PURGE"}) {
+		t.Errorf("unexpected methods: %v", methods)
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		method     string
+		path       string
+		value      string
+		notAllowed []string
+		notFound   bool
+	}{
+		{"GET", "/user/gopher", "GET /user/:name", nil, false},
+		{"POST", "/user/gopher", "POST /user/:name", nil, false},
+		{"PURGE", "/cache/a/b", "PURGE /cache/*key", nil, false},
+		{"DELETE", "/user/gopher", "", []string{"GET", "POST"}, false},
+		{"GET", "/cache/a", "", []string{"PURGE"}, false},
+		{"GET", "/missing", "", nil, true},
+		{"GET", "/drafts/1", "", []string{"POST"}, false},
+		{"GET", "/private", "", nil, true},
+	}
+	for _, test := range tests {
+		fakeHandlerValue, notAllowed, notFound = "", nil, false
+		if found, err := router.Serve(ctx, test.method, test.path, struct{}{}); !found || err != nil {
+			t.Errorf("%s %s: expected found, got found=%v err=%v", test.method, test.path, found, err)
+		}
+		if fakeHandlerValue != test.value || !reflect.DeepEqual(notAllowed, test.notAllowed) || notFound != test.notFound {
+			t.Errorf("%s %s: expected %q %v %v but got %q %v %v", test.method, test.path, test.value, test.notAllowed, test.notFound, fakeHandlerValue, notAllowed, notFound)
+		}
+	}
+}
+
+func TestMethodRouterLookup(t *testing.T) {
+	router := NewMethodRouter(MethodRouterConfig[struct{}]{RouterConfig: DefaultConfig[struct{}]()})
+	router.AddHandler("GET", "/user/:name", fakeHandler("GET /user/:name"))
+
+	if res := router.Lookup("GET", "/user/gopher"); res.Pattern != "/user/:name" {
+		t.Errorf("expected match but got %+v", res)
+	}
+	if res := router.Lookup("POST", "/user/gopher"); res.Found() {
+		t.Errorf("expected no match but got %+v", res)
+	}
+	if methods := router.Methods(); !reflect.DeepEqual(methods, []string{"GET"}) {
+		t.Errorf("lookup created a router: %v", methods)
+	}
+}
+
+func TestMethodRouterHandlePattern(t *testing.T) {
+	router := NewMethodRouter(MethodRouterConfig[struct{}]{RouterConfig: DefaultConfig[struct{}]()})
+	router.HandlePattern("GET /user/{name}", fakeHandler("GET /user/{name}"))
+	router.HandlePattern("DELETE /files/{path...}", fakeHandler("DELETE /files/{path...}"))
+
+	res := router.Lookup("GET", "/user/gopher")
+	if !res.Found() || res.Pattern != "/user/:name" || res.Params.ByName("name") != "gopher" {
+		t.Errorf("unexpected lookup result: %+v", res)
+	}
+	res = router.Lookup("DELETE", "/files/a/b")
+	if !res.Found() || res.Params.ByName("path") != "/a/b" {
+		t.Errorf("unexpected lookup result: %+v", res)
+	}
+
+	recv := catchPanic(func() {
+		router.HandlePattern("/no/method", fakeHandler("/no/method"))
+	})
+	if recv == nil {
+		t.Error("expected panic for pattern without method")
+	}
+}
@@ -0,0 +1,46 @@
+package pathrouter
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// Middleware wraps a handle, for example to recover panics, see Recover.
+type Middleware[W any] func(next Handle[W]) Handle[W]
+
+// Recover returns a middleware recovering panics in the handle and returning
+// false and a *PanicError including the params and the stack trace instead.
+//
+// Unlike the PanicHandler, the error is handled like any other handle error:
+// the ErrorHandler is called, if set, and the error is counted in the route
+// statistics. The pattern of the error is set if RouterConfig.PatternInContext
+// is set.
+func Recover[W any]() Middleware[W] {
+	return func(next Handle[W]) Handle[W] {
+		return func(ctx context.Context, reqPath string, p Params, rw W) (found bool, err error) {
+			defer func() {
+				if rcv := recover(); rcv != nil {
+					found, err = false, &PanicError{
+						Value:   rcv,
+						Path:    reqPath,
+						Pattern: PatternFromContext(ctx),
+						Params:  p.Clone(),
+						Stack:   debug.Stack(),
+					}
+				}
+			}()
+			return next(ctx, reqPath, p, rw)
+		}
+	}
+}
+
+// applyMiddleware wraps the handle with the middleware.
+// The first middleware is the outermost.
+func applyMiddleware[W any](handle Handle[W], mws []Middleware[W]) Handle[W] {
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] != nil {
+			handle = mws[i](handle)
+		}
+	}
+	return handle
+}
@@ -0,0 +1,43 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	var handled error
+	conf := DefaultConfig[struct{}]()
+	conf.PatternInContext = true
+	conf.RouteStats = true
+	conf.ErrorHandler = func(ctx context.Context, reqPath string, p Params, rw struct{}, err error) (bool, error) {
+		handled = err
+		return true, nil
+	}
+	router := NewWithConfig(conf)
+	recoverer := Recover[struct{}]()
+	router.AddHandler("/user/:name", recoverer(panicHandle))
+	router.AddHandler("/ok", recoverer(fakeHandler("ok")))
+
+	found, err := router.Serve(context.Background(), "/user/gopher", struct{}{})
+	if !found || err != nil {
+		t.Errorf("expected the error handler result, got found=%v err=%v", found, err)
+	}
+	var perr *PanicError
+	if !errors.As(handled, &perr) || !errors.Is(perr, errPanicValue) {
+		t.Fatalf("expected panic error, got %v", handled)
+	}
+	if perr.Pattern != "/user/:name" || perr.Params.ByName("name") != "gopher" || !strings.Contains(string(perr.Stack), "panicHandle") {
+		t.Errorf("unexpected panic error %+v", perr)
+	}
+	if stats := router.RouteStats(); stats[0].Errors != 1 {
+		t.Errorf("expected the panic to be counted as error, got %+v", stats[0])
+	}
+
+	handled = nil
+	if found, err := router.Serve(context.Background(), "/ok", struct{}{}); !found || err != nil || handled != nil {
+		t.Errorf("expected handle result, got found=%v err=%v", found, err)
+	}
+}
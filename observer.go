@@ -0,0 +1,30 @@
+package pathrouter
+
+import "time"
+
+// Observer observes the requests served by a router.
+//
+// The methods are called synchronously from the serving goroutine and must be
+// safe to call concurrently.
+type Observer interface {
+	// OnLookup is called after a request was served by Serve or ServeAll with
+	// the request path, the pattern of the route handling the request, the
+	// duration of the lookup and the handles in nanoseconds and if the request
+	// was handled.
+	//
+	// matchedPattern is empty if no route handled the request.
+	OnLookup(path, matchedPattern string, durationNanos int64, found bool)
+	// OnHandlerError is called when the handle of the route with the pattern
+	// returns an error, before the ErrorHandler.
+	OnHandlerError(pattern string, err error)
+}
+
+// observe calls serve and reports the request to the Observer.
+func (r *Router[W]) observe(reqPath string, serve func(matched *string) (bool, error)) (found bool, err error) {
+	var matched string
+	start := time.Now()
+	defer func() {
+		r.conf.Observer.OnLookup(reqPath, matched, time.Since(start).Nanoseconds(), found)
+	}()
+	return serve(&matched)
+}
@@ -0,0 +1,73 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// testObserver records the observed requests.
+type testObserver struct {
+	mtx     sync.Mutex
+	lookups []string
+	errs    []string
+}
+
+func (o *testObserver) OnLookup(path, matchedPattern string, durationNanos int64, found bool) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	if durationNanos < 0 {
+		matchedPattern = "negative duration"
+	}
+	var res string
+	if found {
+		res = " found"
+	}
+	o.lookups = append(o.lookups, path+" "+matchedPattern+res)
+}
+
+func (o *testObserver) OnHandlerError(pattern string, err error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.errs = append(o.errs, pattern+" "+err.Error())
+}
+
+func TestRouterObserver(t *testing.T) {
+	obs := &testObserver{}
+	conf := DefaultConfig[struct{}]()
+	conf.Observer = obs
+	conf.Fallthrough = true
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/new", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, nil
+	})
+	router.AddHandler("/user/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		if p.ByName("name") == "error" {
+			return false, errors.New("failed")
+		}
+		return true, nil
+	})
+	router.AddHandler("/dir/", fakeHandler("/dir/"))
+
+	ctx := context.Background()
+	for _, path := range []string{"/user/new", "/user/error", "/missing", "/dir"} {
+		_, _ = router.Serve(ctx, path, struct{}{})
+	}
+	_, _ = router.ServeAll(ctx, "/user/new", struct{}{})
+
+	lookups := []string{
+		"/user/new /user/:name found",
+		"/user/error /user/:name",
+		"/missing ",
+		"/dir /dir/ found",
+		"/user/new /user/:name found",
+	}
+	if !reflect.DeepEqual(obs.lookups, lookups) {
+		t.Errorf("expected lookups %q but got %q", lookups, obs.lookups)
+	}
+	if errs := []string{"/user/:name failed"}; !reflect.DeepEqual(obs.errs, errs) {
+		t.Errorf("expected errors %q but got %q", errs, obs.errs)
+	}
+}
@@ -0,0 +1,190 @@
+// Package openapi generates OpenAPI 3 path items from the routes of a router.
+//
+// Route patterns are converted to OpenAPI path templates: named params and
+// catch-all params become {name} template expressions with a path parameter.
+// The path items are populated from the route metadata, see MetaPathItem.
+package openapi
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/aperturerobotics/pathrouter"
+	"github.com/pkg/errors"
+)
+
+// Route metadata keys read when generating path items.
+const (
+	// MetaPathItem is the metadata key for a path item template: a PathItem,
+	// *PathItem or a value with the same JSON encoding.
+	MetaPathItem = "openapi"
+	// MetaSummary is the metadata key for the path item summary string.
+	MetaSummary = "summary"
+	// MetaDescription is the metadata key for the path item description string.
+	MetaDescription = "description"
+)
+
+// Paths maps OpenAPI path templates to path items.
+type Paths map[string]*PathItem
+
+// PathItem is an OpenAPI 3 path item object.
+type PathItem struct {
+	Summary     string      `json:"summary,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Get         *Operation  `json:"get,omitempty"`
+	Put         *Operation  `json:"put,omitempty"`
+	Post        *Operation  `json:"post,omitempty"`
+	Delete      *Operation  `json:"delete,omitempty"`
+	Options     *Operation  `json:"options,omitempty"`
+	Head        *Operation  `json:"head,omitempty"`
+	Patch       *Operation  `json:"patch,omitempty"`
+	Trace       *Operation  `json:"trace,omitempty"`
+	Parameters  []Parameter `json:"parameters,omitempty"`
+}
+
+// Operation is an OpenAPI 3 operation object.
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	Responses   map[string]*Response `json:"responses,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// Parameter is an OpenAPI 3 parameter object.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// Schema is a minimal OpenAPI 3 schema object.
+type Schema struct {
+	Type   string `json:"type,omitempty"`
+	Format string `json:"format,omitempty"`
+}
+
+// Response is an OpenAPI 3 response object.
+type Response struct {
+	Description string `json:"description"`
+}
+
+// ConvertPattern converts a route pattern to an OpenAPI path template.
+// Returns the template and the names of the path params in order.
+//
+// For example /users/:id/files/*path becomes /users/{id}/files/{path}.
+// The {$} end marker is removed.
+func ConvertPattern(pattern string) (string, []string) {
+	pattern = strings.TrimSuffix(pattern, "{$}")
+	var sb strings.Builder
+	var names []string
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != ':' && c != '*' {
+			sb.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(pattern[i:], '/')
+		if end < 0 {
+			end = len(pattern) - i
+		}
+		name := pattern[i+1 : i+end]
+		names = append(names, name)
+		sb.WriteString("{" + name + "}")
+		i += end - 1
+	}
+	return sb.String(), names
+}
+
+// RouterPaths generates the path items for the routes of the router.
+func RouterPaths[W any](router *pathrouter.Router[W]) (Paths, error) {
+	return RoutePaths(router.Routes())
+}
+
+// RoutePaths generates the path items for the route definitions.
+//
+// If multiple routes convert to the same path template the first is used.
+// A path parameter is added for each route param unless the path item
+// template already defines a parameter with the same name.
+func RoutePaths[W any](routes []pathrouter.RouteDef[W]) (Paths, error) {
+	paths := make(Paths, len(routes))
+	for _, route := range routes {
+		path, names := ConvertPattern(route.Pattern)
+		if _, exists := paths[path]; exists {
+			continue
+		}
+
+		item, err := pathItemFromMetadata(route.Metadata)
+		if err != nil {
+			return nil, errors.Wrapf(err, "route '%s'", route.Pattern)
+		}
+		for _, name := range names {
+			if !hasParameter(item.Parameters, name) {
+				item.Parameters = append(item.Parameters, Parameter{
+					Name:     name,
+					In:       "path",
+					Required: true,
+					Schema:   &Schema{Type: "string"},
+				})
+			}
+		}
+		paths[path] = item
+	}
+	return paths, nil
+}
+
+// SortedPaths returns the path templates in sorted order.
+func (p Paths) SortedPaths() []string {
+	out := make([]string, 0, len(p))
+	for path := range p {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// pathItemFromMetadata builds the path item template from the route metadata.
+func pathItemFromMetadata(metadata map[string]any) (*PathItem, error) {
+	item := &PathItem{}
+	if tmpl, ok := metadata[MetaPathItem]; ok {
+		switch v := tmpl.(type) {
+		case *PathItem:
+			*item = *v
+			item.Parameters = append([]Parameter(nil), v.Parameters...)
+		case PathItem:
+			*item = v
+			item.Parameters = append([]Parameter(nil), v.Parameters...)
+		default:
+			// decoded from JSON or an equivalent value
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, item); err != nil {
+				return nil, errors.Wrap(err, "invalid path item")
+			}
+		}
+	}
+	if summary, ok := metadata[MetaSummary].(string); ok && item.Summary == "" {
+		item.Summary = summary
+	}
+	if description, ok := metadata[MetaDescription].(string); ok && item.Description == "" {
+		item.Description = description
+	}
+	return item, nil
+}
+
+// hasParameter checks if a path parameter with the name exists.
+func hasParameter(params []Parameter, name string) bool {
+	for _, param := range params {
+		if param.In == "path" && param.Name == name {
+			return true
+		}
+	}
+	return false
+}
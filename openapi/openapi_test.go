@@ -0,0 +1,75 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+func TestConvertPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		names   []string
+	}{
+		{"/", "/", nil},
+		{"/users/:id", "/users/{id}", []string{"id"}},
+		{"/users/:id/files/*path", "/users/{id}/files/{path}", []string{"id", "path"}},
+		{"/user_:name/about", "/user_{name}/about", []string{"name"}},
+		{"/dir/{$}", "/dir/", nil},
+	}
+	for _, test := range tests {
+		path, names := ConvertPattern(test.pattern)
+		if path != test.path || !reflect.DeepEqual(names, test.names) {
+			t.Errorf("ConvertPattern(%q) = %q, %v, want %q, %v", test.pattern, path, names, test.path, test.names)
+		}
+	}
+}
+
+func TestRouterPaths(t *testing.T) {
+	handle := func(ctx context.Context, reqPath string, p pathrouter.Params, rw struct{}) (bool, error) {
+		return true, nil
+	}
+	router := pathrouter.New[struct{}]()
+	router.AddHandler(
+		"/users/:id",
+		handle,
+		pathrouter.WithMetadata(MetaSummary, "A user"),
+		pathrouter.WithMetadata(MetaPathItem, &PathItem{
+			Get: &Operation{
+				OperationID: "getUser",
+				Responses:   map[string]*Response{"200": {Description: "the user"}},
+			},
+			Parameters: []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer"}}},
+		}),
+	)
+	router.AddHandler(
+		"/files/*path",
+		handle,
+		pathrouter.WithMetadata(MetaPathItem, map[string]any{
+			"description": "Files",
+			"get":         map[string]any{"operationId": "getFile"},
+		}),
+	)
+
+	paths, err := RouterPaths(router)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if got := paths.SortedPaths(); !reflect.DeepEqual(got, []string{"/files/{path}", "/users/{id}"}) {
+		t.Fatalf("unexpected paths: %v", got)
+	}
+
+	data, err := json.Marshal(paths)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	want := `{"/files/{path}":{"description":"Files","get":{"operationId":"getFile"},"parameters":[{"name":"path","in":"path","required":true,"schema":{"type":"string"}}]},` +
+		`"/users/{id}":{"summary":"A user","get":{"operationId":"getUser","responses":{"200":{"description":"the user"}}},"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"integer"}}]}}`
+	if string(data) != want {
+		t.Errorf("unexpected path items:\n%s\nexpected:\n%s", data, want)
+	}
+}
@@ -0,0 +1,226 @@
+package pathrouter
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RouteOption configures a route when it is registered.
+type RouteOption func(o *routeOptions)
+
+// routeOptions contains the options for a route.
+type routeOptions struct {
+	// name is the handler name.
+	name string
+	// metadata contains the route metadata.
+	metadata map[string]any
+	// defaults contains the default param values by name.
+	defaults map[string]string
+	// timeout is the handle timeout.
+	timeout time.Duration
+	// tags contains the route tags.
+	tags []string
+}
+
+// WithName sets the name of the handler registered for the route.
+//
+// The name identifies the handler when the route table is serialized, see
+// MarshalRoutes and LoadRoutes.
+func WithName(name string) RouteOption {
+	return func(o *routeOptions) {
+		o.name = name
+	}
+}
+
+// WithMetadata attaches a metadata value to the route.
+//
+// Values should be JSON serializable if the route table is serialized.
+func WithMetadata(key string, value any) RouteOption {
+	return func(o *routeOptions) {
+		if o.metadata == nil {
+			o.metadata = make(map[string]any)
+		}
+		o.metadata[key] = value
+	}
+}
+
+// WithTags tags the route, for example "public" or "deprecated", see
+// RoutesByTag.
+func WithTags(tags ...string) RouteOption {
+	return func(o *routeOptions) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// WithDefault sets the value passed to the handle for the named param if the
+// matched value is empty, for example "all" for :category when /list//3 is
+// matched by /list/:category/:page.
+//
+// If the param is the last segment of the pattern the route also matches the
+// paths without the segment: /list/books and /list/books/ are matched by
+// /list/:category/:page with a default for :page if no other route matches
+// them and the path cannot be corrected, for example by redirecting
+// /list/books/ to /list/:category. The defaults are applied to the params
+// passed to the handle and returned by Lookup and LookupAll.
+//
+// Only applies to named params: catch-all values contain at least the leading
+// separator.
+func WithDefault(name, value string) RouteOption {
+	return func(o *routeOptions) {
+		if o.defaults == nil {
+			o.defaults = make(map[string]string)
+		}
+		o.defaults[name] = value
+	}
+}
+
+// WithTimeout sets the timeout of the context passed to the handle.
+//
+// If the handle returns an error after the timeout expired the TimeoutHandler
+// of the config is called, if set. Zero or negative disables the timeout.
+func WithTimeout(timeout time.Duration) RouteOption {
+	return func(o *routeOptions) {
+		o.timeout = timeout
+	}
+}
+
+// applyRouteOptions applies the options to the route.
+// Returns an error if a default is set for a param not in the pattern.
+func applyRouteOptions[W any](rt *route[W], opts []RouteOption) error {
+	if len(opts) == 0 {
+		return nil
+	}
+	var o routeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	rt.name = o.name
+	rt.metadata = o.metadata
+	rt.timeout = o.timeout
+	rt.tags = o.tags
+	return rt.setDefaults(o.defaults)
+}
+
+// hasParam checks if the route has a named param with the name.
+func (rt *route[W]) hasParam(name string) bool {
+	for _, part := range rt.parts {
+		if part.kind == param && part.value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setDefaults sets the default param values of the route, see WithDefault.
+// Returns an error if a default is set for a param not in the pattern.
+func (rt *route[W]) setDefaults(defaults map[string]string) error {
+	for name := range defaults {
+		if !rt.hasParam(name) {
+			return errors.Errorf("default for unknown param '%s' in path '%s'", name, rt.pattern)
+		}
+	}
+	if len(defaults) != 0 {
+		rt.defaults = defaults
+	}
+	return nil
+}
+
+// applyDefaults sets the empty params with a default, see WithDefault.
+// The params are modified in place.
+func (rt *route[W]) applyDefaults(ps Params) Params {
+	if rt == nil || len(rt.defaults) == 0 {
+		return ps
+	}
+	for i := range ps {
+		if ps[i].Value == "" {
+			if value, ok := rt.defaults[ps[i].Key]; ok {
+				ps[i].Value = value
+			}
+		}
+	}
+	return ps
+}
+
+// trailingDefault returns the name of the param of the last segment of the
+// pattern if it has a default, see WithDefault.
+func (rt *route[W]) trailingDefault() (string, bool) {
+	n := len(rt.parts)
+	if len(rt.defaults) == 0 || n < 2 {
+		return "", false
+	}
+	last, prev := rt.parts[n-1], rt.parts[n-2]
+	if last.kind != param || prev.kind != static || !strings.HasSuffix(prev.value, "/") {
+		return "", false
+	}
+	_, ok := rt.defaults[last.value]
+	return last.value, ok
+}
+
+// emptyTrailingDefault checks if the route matched a path with a trailing slash
+// with the param of the last segment empty, see trailingDefault. The default is
+// then applied by matchTrailingDefault, only if the path cannot be corrected.
+func (rt *route[W]) emptyTrailingDefault(trailingSlash bool) bool {
+	if rt == nil || !trailingSlash {
+		return false
+	}
+	_, ok := rt.trailingDefault()
+	return ok
+}
+
+// hasTrailingSlash checks if the path other than "/" ends with a slash.
+func hasTrailingSlash(path string) bool {
+	return len(path) > 1 && path[len(path)-1] == '/'
+}
+
+// trailingSlashMatch checks if a handle exists for the path without the
+// trailing slash, as recommended by getValue.
+func (t *table[W]) trailingSlashMatch(path string) bool {
+	for _, tree := range t.trees {
+		if leaf, _, _ := tree.getValue(path[:len(path)-1], nil, nil); leaf != nil && !leaf.anchored {
+			return true
+		}
+	}
+	return false
+}
+
+// trailingDefaults returns the routes with a default for the param of the last
+// segment in precedence order. Computed once per route table.
+func (t *table[W]) trailingDefaults() []*route[W] {
+	if rts := t.defaults.Load(); rts != nil {
+		return *rts
+	}
+	var rts []*route[W]
+	for _, rt := range t.routes {
+		if _, ok := rt.trailingDefault(); ok {
+			rts = append(rts, rt)
+		}
+	}
+	slices.SortFunc(rts, compareRoutes[W])
+	t.defaults.Store(&rts)
+	return rts
+}
+
+// matchTrailingDefault matches a path not matched by any route with the routes
+// with a default for the param of the last segment, see WithDefault. Returns
+// the path with the default appended as the last segment and the match.
+func (r *Router[W]) matchTrailingDefault(t *table[W], path string, params func() *Params) (string, match[W], bool) {
+	for _, rt := range t.trailingDefaults() {
+		name, _ := rt.trailingDefault()
+		fullPath := strings.TrimSuffix(path, "/") + "/" + toTree(rt.defaults[name], r.conf.Separator)
+		matches, _ := r.matchRoutesParams(t, fullPath, false, params)
+		for i, m := range matches {
+			if m.route == rt {
+				r.putMatches(matches[:i])
+				r.putMatches(matches[i+1:])
+				return fullPath, m, true
+			}
+		}
+		r.putMatches(matches)
+	}
+	return "", match[W]{}, false
+}
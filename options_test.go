@@ -0,0 +1,118 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestWithDefault(t *testing.T) {
+	router := New[struct{}]()
+	var got Params
+	router.AddHandler("/list/:category/:page", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		got = append(Params(nil), p...)
+		return true, nil
+	}, WithDefault("category", "all"), WithDefault("page", "1"))
+
+	tests := []struct {
+		path   string
+		params Params
+	}{
+		{"/list/books/3", Params{{"category", "books"}, {"page", "3"}}},
+		{"/list//3", Params{{"category", "all"}, {"page", "3"}}},
+		{"/list/books", Params{{"category", "books"}, {"page", "1"}}},
+		{"/list/books/", Params{{"category", "books"}, {"page", "1"}}},
+	}
+	for _, test := range tests {
+		got = nil
+		if found, err := router.Serve(context.Background(), test.path, struct{}{}); !found || err != nil {
+			t.Fatalf("path %s: expected found, got found=%v err=%v", test.path, found, err)
+		}
+		if !reflect.DeepEqual(got, test.params) {
+			t.Errorf("path %s: expected params %v but got %v", test.path, test.params, got)
+		}
+	}
+	if found, _ := router.Serve(context.Background(), "/list", struct{}{}); found {
+		t.Error("expected /list not to be found")
+	}
+
+	for _, path := range []string{"/list//3", "/list/books"} {
+		res := router.Lookup(path)
+		if res.Pattern != "/list/:category/:page" {
+			t.Fatalf("path %s: expected lookup to match, got %q", path, res.Pattern)
+		}
+		if res.Params.ByName("category") == "" || res.Params.ByName("page") == "" {
+			t.Errorf("path %s: expected lookup to return the defaults, got %v", path, res.Params)
+		}
+		res = router.Lookup(path, LazyParams())
+		if params := res.LoadParams(); params.ByName("category") == "" || params.ByName("page") == "" {
+			t.Errorf("path %s: expected lazy lookup to return the defaults, got %v", path, params)
+		}
+	}
+
+	wantDefaults := map[string]string{"category": "all", "page": "1"}
+	for _, def := range router.Routes() {
+		if def.Pattern == "/list/:category/:page" && !reflect.DeepEqual(def.Defaults, wantDefaults) {
+			t.Errorf("expected the route definition to contain the defaults, got %v", def.Defaults)
+		}
+	}
+	clone := New[struct{}]()
+	if err := clone.AddRoutes(router.Routes()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if res := clone.Lookup("/list/books"); res.Params.ByName("page") != "1" {
+		t.Errorf("expected the defaults to be registered from the route definitions, got %v", res.Params)
+	}
+
+	for _, test := range []struct {
+		path string
+		name string
+	}{
+		{"/list/:page", "category"},
+		{"/files/*filepath", "filepath"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("path %s: expected panic for default of %s", test.path, test.name)
+				}
+			}()
+			router.AddHandler(test.path, fakeHandler(""), WithDefault(test.name, "x"))
+		}()
+	}
+}
+
+func TestWithDefaultCorrection(t *testing.T) {
+	var redirected string
+	router := NewWithConfig(RouterConfig[struct{}]{
+		RedirectTrailingSlash: true,
+		RedirectHandler: func(ctx context.Context, fromPath, toPath string, rw struct{}) (bool, error) {
+			redirected = toPath
+			return true, nil
+		},
+	})
+	router.AddHandler("/list/:category", fakeHandler("category"))
+	router.AddHandler("/list/:category/:page", fakeHandler("page"), WithDefault("category", "all"), WithDefault("page", "1"))
+
+	// the trailing slash is corrected before the default is applied
+	if found, err := router.Serve(context.Background(), "/list/books/", struct{}{}); !found || err != nil {
+		t.Fatalf("expected found, got found=%v err=%v", found, err)
+	}
+	if redirected != "/list/books" {
+		t.Errorf("expected redirect to /list/books, got %q", redirected)
+	}
+	if res := router.Lookup("/list/books/"); res.Found() || res.RedirectPath != "/list/books" {
+		t.Errorf("expected lookup to redirect to /list/books, got %q %q", res.Pattern, res.RedirectPath)
+	}
+	if res := router.LookupSegments([]string{"list", "books", ""}); res.Found() || res.RedirectPath != "/list/books" {
+		t.Errorf("expected segment lookup to redirect to /list/books, got %q %q", res.Pattern, res.RedirectPath)
+	}
+	if matches := router.LookupAll("/list/books/"); len(matches) != 0 {
+		t.Errorf("expected LookupAll not to match the corrected path, got %v", matches)
+	}
+
+	matches := router.LookupAll("/list//3")
+	if len(matches) != 1 || !reflect.DeepEqual(matches[0].Params, Params{{"category", "all"}, {"page", "3"}}) {
+		t.Errorf("expected LookupAll to apply the defaults, got %v", matches)
+	}
+}
@@ -0,0 +1,92 @@
+// Package otelroute traces pathrouter handles with OpenTelemetry.
+//
+// The spans are named by the route pattern instead of the request path, which
+// keeps the number of span names bounded:
+//
+//	tracer := otelroute.NewTracer(otel.GetTracerProvider())
+//	otelroute.AddHandler(tracer, router, "/user/:name", getUser)
+//	// span "/user/:name" with the attribute route.param.name=gopher
+//	router.Serve(ctx, "/user/gopher", rw)
+package otelroute
+
+import (
+	"context"
+
+	"github.com/aperturerobotics/pathrouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of the tracer.
+const TracerName = "github.com/aperturerobotics/pathrouter/otelroute"
+
+// Attribute keys set on the spans.
+const (
+	// PatternKey is the route pattern.
+	PatternKey = attribute.Key("route.pattern")
+	// PathKey is the request path.
+	PathKey = attribute.Key("route.path")
+	// HandledKey is set to false if the handle did not handle the request.
+	HandledKey = attribute.Key("route.handled")
+	// ParamKeyPrefix is the prefix of the param attribute keys.
+	ParamKeyPrefix = "route.param."
+)
+
+// Tracer starts spans for the handles.
+type Tracer struct {
+	tracer trace.Tracer
+	kind   trace.SpanKind
+}
+
+// NewTracer constructs a new Tracer with the tracer provider.
+// The spans have the internal span kind.
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	return NewTracerWithKind(tp, trace.SpanKindInternal)
+}
+
+// NewTracerWithKind constructs a new Tracer starting spans of the given kind,
+// for example trace.SpanKindServer if the router is the entry point of the
+// request.
+func NewTracerWithKind(tp trace.TracerProvider, kind trace.SpanKind) *Tracer {
+	return &Tracer{tracer: tp.Tracer(TracerName), kind: kind}
+}
+
+// WrapHandle wraps the handle registered with the pattern to start a span
+// named by the pattern for each call.
+//
+// The span name is the pattern attached to the context if any, see
+// RouterConfig.PatternInContext, so it matches the pattern as registered.
+// The params are set as attributes with the ParamKeyPrefix. Errors are recorded
+// and set the span status.
+func WrapHandle[W any](t *Tracer, pattern string, handle pathrouter.Handle[W]) pathrouter.Handle[W] {
+	return func(ctx context.Context, reqPath string, p pathrouter.Params, rw W) (bool, error) {
+		name := pattern
+		if ctxPattern := pathrouter.PatternFromContext(ctx); ctxPattern != "" {
+			name = ctxPattern
+		}
+
+		attrs := make([]attribute.KeyValue, 0, len(p)+2)
+		attrs = append(attrs, PatternKey.String(name), PathKey.String(reqPath))
+		for _, param := range p {
+			attrs = append(attrs, attribute.String(ParamKeyPrefix+param.Key, param.Value))
+		}
+		ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(t.kind), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		found, err := handle(ctx, reqPath, p, rw)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		if !found {
+			span.SetAttributes(HandledKey.Bool(false))
+		}
+		return found, err
+	}
+}
+
+// AddHandler registers the handle wrapped with WrapHandle with the router.
+func AddHandler[W any](t *Tracer, r *pathrouter.Router[W], pattern string, handle pathrouter.Handle[W], opts ...pathrouter.RouteOption) {
+	r.AddHandler(pattern, WrapHandle(t, pattern, handle), opts...)
+}
@@ -0,0 +1,68 @@
+package otelroute
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aperturerobotics/pathrouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWrapHandle(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewTracerWithKind(tp, trace.SpanKindServer)
+
+	conf := pathrouter.DefaultConfig[struct{}]()
+	conf.PatternInContext = true
+	router := pathrouter.NewWithConfig(conf)
+	AddHandler(tracer, router, "user/:name", func(ctx context.Context, reqPath string, p pathrouter.Params, rw struct{}) (bool, error) {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			t.Error("expected span in handle context")
+		}
+		if p.ByName("name") == "error" {
+			return true, errors.New("failed")
+		}
+		return p.ByName("name") != "nobody", nil
+	})
+
+	ctx := context.Background()
+	for _, path := range []string{"/user/gopher", "/user/error", "/user/nobody", "/missing"} {
+		_, _ = router.Serve(ctx, path, struct{}{})
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans but got %d", len(spans))
+	}
+	for i, span := range spans {
+		if span.Name() != "/user/:name" || span.SpanKind() != trace.SpanKindServer {
+			t.Errorf("span %d: unexpected name %q kind %v", i, span.Name(), span.SpanKind())
+		}
+	}
+
+	attrs := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		out := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes() {
+			out[kv.Key] = kv.Value
+		}
+		return out
+	}
+	if a := attrs(spans[0]); a[ParamKeyPrefix+"name"].AsString() != "gopher" || a[PathKey].AsString() != "/user/gopher" || a[PatternKey].AsString() != "/user/:name" {
+		t.Errorf("unexpected attributes: %v", a)
+	}
+	if _, ok := attrs(spans[0])[HandledKey]; ok {
+		t.Error("expected no handled attribute for a handled request")
+	}
+	if spans[1].Status().Code != codes.Error || len(spans[1].Events()) != 1 {
+		t.Errorf("expected error status and event, got %v %v", spans[1].Status(), spans[1].Events())
+	}
+	if v, ok := attrs(spans[2])[HandledKey]; !ok || v.AsBool() {
+		t.Errorf("expected handled=false attribute, got %v", v)
+	}
+}
@@ -0,0 +1,87 @@
+package pathrouter
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is a panic recovered from a handle, see
+// RouterConfig.PanicErrorHandler and RouterConfig.PanicErrors.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Path is the request path.
+	Path string
+	// Pattern is the pattern of the route of the panicking handle, if any.
+	Pattern string
+	// Params contains the params of the route, if any.
+	// Not pooled: safe to retain.
+	Params Params
+	// Stack is the stack trace captured when the panic was recovered.
+	Stack []byte
+}
+
+// Error returns the error string.
+func (e *PanicError) Error() string {
+	if e.Pattern != "" {
+		return fmt.Sprintf("panic serving %s with route %s: %v", e.Path, e.Pattern, e.Value)
+	}
+	return fmt.Sprintf("panic serving %s: %v", e.Path, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recoverPanics checks if panics are recovered.
+func (r *Router[W]) recoverPanics() bool {
+	return r.conf.PanicHandler != nil || r.conf.PanicErrorHandler != nil || r.conf.PanicErrors
+}
+
+// wrapPanic recovers a panic from the handle of a route and panics with a
+// *PanicError including the params and the stack. Deferred by callHandle.
+func (r *Router[W]) wrapPanic(reqPath string, m match[W], params Params) {
+	rcv := recover()
+	if rcv == nil {
+		return
+	}
+	if _, ok := rcv.(*PanicError); ok {
+		// already wrapped by a nested router
+		panic(rcv)
+	}
+	perr := &PanicError{Value: rcv, Path: reqPath, Params: params.Clone(), Stack: debug.Stack()}
+	if m.route != nil {
+		perr.Pattern = m.route.matchedPattern()
+	}
+	panic(perr)
+}
+
+// recoverPanic recovers from a panic while processing a path and sets the
+// result of the request.
+func (r *Router[W]) recoverPanic(ctx context.Context, reqPath string, wr W, found *bool, err *error) {
+	rcv := recover()
+	if rcv == nil {
+		return
+	}
+	perr, ok := rcv.(*PanicError)
+	if !ok {
+		perr = &PanicError{Value: rcv, Path: reqPath, Stack: debug.Stack()}
+	}
+
+	*found, *err = false, nil
+	defer func() {
+		// if the panic handler panics, just give up :)
+		_ = recover()
+	}()
+	switch {
+	case r.conf.PanicErrorHandler != nil:
+		*found, *err = r.conf.PanicErrorHandler(ctx, wr, perr)
+	case r.conf.PanicHandler != nil:
+		r.conf.PanicHandler(ctx, reqPath, wr, perr.Value)
+	default:
+		*err = perr
+	}
+}
@@ -0,0 +1,76 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errPanicValue = errors.New("boom")
+
+func panicHandle(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+	panic(errPanicValue)
+}
+
+func TestRouterPanicErrorHandler(t *testing.T) {
+	var got *PanicError
+	conf := DefaultConfig[struct{}]()
+	conf.PanicHandler = func(ctx context.Context, reqPath string, rw struct{}, panicErr interface{}) {
+		t.Error("expected the panic error handler to be called instead")
+	}
+	conf.PanicErrorHandler = func(ctx context.Context, rw struct{}, err *PanicError) (bool, error) {
+		got = err
+		return true, errors.New("recovered")
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", panicHandle)
+
+	found, err := router.Serve(context.Background(), "/user/gopher", struct{}{})
+	if !found || err == nil || err.Error() != "recovered" {
+		t.Errorf("expected panic error handler result, got found=%v err=%v", found, err)
+	}
+	if got == nil {
+		t.Fatal("expected panic error handler to be called")
+	}
+	if got.Path != "/user/gopher" || got.Pattern != "/user/:name" || got.Params.ByName("name") != "gopher" {
+		t.Errorf("unexpected panic error %+v", got)
+	}
+	if !errors.Is(got, errPanicValue) {
+		t.Errorf("expected panic error to wrap the panic value, got %v", got.Value)
+	}
+	if !strings.Contains(string(got.Stack), "panicHandle") {
+		t.Errorf("expected stack to contain the panicking handle:\n%s", got.Stack)
+	}
+}
+
+func TestRouterPanicErrors(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.PanicErrors = true
+	conf.NotFound = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		panic("not found")
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/files/*path", panicHandle)
+
+	ctx := context.Background()
+	for _, serve := range []func(ctx context.Context, reqPath string, rw struct{}) (bool, error){router.Serve, router.ServeAll} {
+		found, err := serve(ctx, "/files/a/b", struct{}{})
+		var perr *PanicError
+		if found || !errors.As(err, &perr) {
+			t.Fatalf("expected panic error, got found=%v err=%v", found, err)
+		}
+		if perr.Pattern != "/files/*path" || perr.Params.ByName("path") != "/a/b" {
+			t.Errorf("unexpected panic error %+v", perr)
+		}
+		if want := "panic serving /files/a/b with route /files/*path: boom"; perr.Error() != want {
+			t.Errorf("expected error %q but got %q", want, perr.Error())
+		}
+	}
+
+	_, err := router.Serve(ctx, "/missing", struct{}{})
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value != "not found" || perr.Pattern != "" || perr.Params != nil {
+		t.Errorf("unexpected panic error %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package pathrouter
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// paramTag is the struct field tag used by Decode.
+const paramTag = "path"
+
+// ErrParamNotFound is returned if a param was not found by name.
+var ErrParamNotFound = errors.New("param not found")
+
+// Get returns the value of the first Param which key matches the given name.
+// Returns false if no matching Param is found.
+func (ps Params) Get(name string) (string, bool) {
+	for _, p := range ps {
+		if p.Key == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// Clone returns a copy of the params which is safe to retain after the handle
+// has returned. Returns nil if ps is empty.
+func (ps Params) Clone() Params {
+	if len(ps) == 0 {
+		return nil
+	}
+	out := make(Params, len(ps))
+	copy(out, ps)
+	return out
+}
+
+// Map returns the params as a map from key to value.
+// If a key is repeated the first value is used, matching ByName.
+// The map is safe to retain after the handle has returned.
+func (ps Params) Map() map[string]string {
+	out := make(map[string]string, len(ps))
+	for i := len(ps) - 1; i >= 0; i-- {
+		out[ps[i].Key] = ps[i].Value
+	}
+	return out
+}
+
+// SegmentsOf splits the value of the named param into path segments on '/',
+// as ps.Segments('/').Of(name), see Segments for routers with a custom
+// separator.
+//
+// The leading slash of a catch-all value is removed before splitting, so the
+// value of *filepath for /src/a/b.go is split into ["a", "b.go"]. A trailing
+// slash results in an empty last segment. Returns nil if the param was not
+// found or has no segments. The value is split on each call: use Segments to
+// split it once.
+func (ps Params) SegmentsOf(name string) []string {
+	return ps.Segments('/').Of(name)
+}
+
+// Segments returns the segments of the param values split on the separator of
+// the router, '/' if zero, see RouterConfig.Separator. The values are split on
+// first use and cached.
+func (ps Params) Segments(sep byte) *ParamSegments {
+	if sep == 0 {
+		sep = '/'
+	}
+	return &ParamSegments{ps: ps, sep: sep}
+}
+
+// ParamSegments contains the lazily split segments of the param values, see
+// Params.Segments. Not safe to use concurrently.
+type ParamSegments struct {
+	ps  Params
+	sep byte
+	// cache contains the segments by param name.
+	cache map[string][]string
+}
+
+// Of returns the segments of the named param, splitting the value on the
+// separator on the first call for the name, see Params.SegmentsOf.
+// The returned slice is shared between calls and must not be modified.
+func (s *ParamSegments) Of(name string) []string {
+	if segs, ok := s.cache[name]; ok {
+		return segs
+	}
+	val, ok := s.ps.Get(name)
+	if !ok {
+		return nil
+	}
+	segs := splitSegments(val, s.sep)
+	if s.cache == nil {
+		s.cache = make(map[string][]string, 1)
+	}
+	s.cache[name] = segs
+	return segs
+}
+
+// splitSegments splits the param value on the separator removing the leading
+// separator of catch-all values.
+func splitSegments(val string, sep byte) []string {
+	if len(val) != 0 && val[0] == sep {
+		val = val[1:]
+	}
+	if len(val) == 0 {
+		return nil
+	}
+	return strings.Split(val, string(sep))
+}
+
+// lookup returns the value of the named param or ErrParamNotFound.
+func (ps Params) lookup(name string) (string, error) {
+	val, ok := ps.Get(name)
+	if !ok {
+		return "", errors.Wrap(ErrParamNotFound, name)
+	}
+	return val, nil
+}
+
+// Int parses the named param as an int.
+func (ps Params) Int(name string) (int, error) {
+	val, err := ps.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, errors.Wrapf(err, "param %s", name)
+	}
+	return v, nil
+}
+
+// Int64 parses the named param as an int64.
+func (ps Params) Int64(name string) (int64, error) {
+	val, err := ps.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "param %s", name)
+	}
+	return v, nil
+}
+
+// Uint parses the named param as a uint64.
+func (ps Params) Uint(name string) (uint64, error) {
+	val, err := ps.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "param %s", name)
+	}
+	return v, nil
+}
+
+// Bool parses the named param as a bool.
+// Accepts the values accepted by strconv.ParseBool.
+func (ps Params) Bool(name string) (bool, error) {
+	val, err := ps.lookup(name)
+	if err != nil {
+		return false, err
+	}
+	v, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, errors.Wrapf(err, "param %s", name)
+	}
+	return v, nil
+}
+
+// Float parses the named param as a float64.
+func (ps Params) Float(name string) (float64, error) {
+	val, err := ps.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "param %s", name)
+	}
+	return v, nil
+}
+
+// Decode sets the fields of the struct pointed to by dst from the params.
+//
+// Fields are matched by the `path:"name"` struct tag. Untagged fields and
+// fields tagged with "-" are ignored, embedded structs are decoded
+// recursively. Fields with no matching param are left unchanged.
+//
+// Supported field types are strings, bools, integers, floats, pointers to those
+// and types implementing encoding.TextUnmarshaler.
+func (ps Params) Decode(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("decode target must be a non-nil struct pointer, got %T", dst)
+	}
+	return ps.decodeStruct(rv.Elem())
+}
+
+// decodeStruct decodes the params into the fields of the struct value.
+func (ps Params) decodeStruct(rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, tagged := field.Tag.Lookup(paramTag)
+		if !tagged {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := ps.decodeStruct(rv.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		val, ok := ps.Get(name)
+		if !ok {
+			continue
+		}
+		if err := decodeValue(rv.Field(i), val); err != nil {
+			return errors.Wrapf(err, "param %s", name)
+		}
+	}
+	return nil
+}
+
+// decodeValue parses val into the settable value rv.
+func decodeValue(rv reflect.Value, val string) error {
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeValue(rv.Elem(), val)
+	}
+
+	if rv.CanAddr() {
+		if tu, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return tu.UnmarshalText([]byte(val))
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(val)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(val, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(val, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(val, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(v)
+	default:
+		return errors.Errorf("unsupported field type %s", rv.Type())
+	}
+	return nil
+}
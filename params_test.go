@@ -0,0 +1,226 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParamsGetters(t *testing.T) {
+	ps := Params{
+		Param{"id", "42"},
+		Param{"big", "-9000000000"},
+		Param{"ok", "true"},
+		Param{"ratio", "0.5"},
+		Param{"name", "gopher"},
+		Param{"empty", ""},
+	}
+
+	if val, ok := ps.Get("name"); !ok || val != "gopher" {
+		t.Errorf("Get(name): got %q, %v", val, ok)
+	}
+	if val, ok := ps.Get("empty"); !ok || val != "" {
+		t.Errorf("Get(empty): got %q, %v", val, ok)
+	}
+	if _, ok := ps.Get("nope"); ok {
+		t.Error("Get(nope): expected not found")
+	}
+
+	if v, err := ps.Int("id"); err != nil || v != 42 {
+		t.Errorf("Int(id): got %v, %v", v, err)
+	}
+	if v, err := ps.Int64("big"); err != nil || v != -9000000000 {
+		t.Errorf("Int64(big): got %v, %v", v, err)
+	}
+	if v, err := ps.Uint("id"); err != nil || v != 42 {
+		t.Errorf("Uint(id): got %v, %v", v, err)
+	}
+	if v, err := ps.Bool("ok"); err != nil || !v {
+		t.Errorf("Bool(ok): got %v, %v", v, err)
+	}
+	if v, err := ps.Float("ratio"); err != nil || v != 0.5 {
+		t.Errorf("Float(ratio): got %v, %v", v, err)
+	}
+
+	if _, err := ps.Int("nope"); !errors.Is(err, ErrParamNotFound) {
+		t.Errorf("Int(nope): expected ErrParamNotFound, got %v", err)
+	}
+	if _, err := ps.Int("name"); err == nil || errors.Is(err, ErrParamNotFound) {
+		t.Errorf("Int(name): expected parse error, got %v", err)
+	}
+	if _, err := ps.Uint("big"); err == nil {
+		t.Error("Uint(big): expected parse error")
+	}
+	if _, err := ps.Bool("id"); err == nil {
+		t.Error("Bool(id): expected parse error")
+	}
+}
+
+type decodeSlug string
+
+func (s *decodeSlug) UnmarshalText(text []byte) error {
+	*s = decodeSlug(strings.ToLower(string(text)))
+	return nil
+}
+
+type decodeEmbedded struct {
+	Page uint16 `path:"page"`
+}
+
+func TestParamsDecode(t *testing.T) {
+	ps := Params{
+		Param{"id", "42"},
+		Param{"name", "gopher"},
+		Param{"ok", "1"},
+		Param{"ratio", "0.25"},
+		Param{"slug", "Hello-World"},
+		Param{"page", "3"},
+		Param{"filepath", "/a/b"},
+	}
+
+	var dst struct {
+		decodeEmbedded
+		ID       int64      `path:"id"`
+		Name     string     `path:"name"`
+		OK       bool       `path:"ok"`
+		Ratio    float32    `path:"ratio"`
+		Slug     decodeSlug `path:"slug"`
+		Filepath *string    `path:"filepath"`
+		Missing  string     `path:"missing"`
+		Skipped  string     `path:"-"`
+		Untagged string
+	}
+	dst.Missing = "unchanged"
+	if err := ps.Decode(&dst); err != nil {
+		t.Fatal(err.Error())
+	}
+	if dst.ID != 42 || dst.Name != "gopher" || !dst.OK || dst.Ratio != 0.25 {
+		t.Errorf("decoded wrong values: %+v", dst)
+	}
+	if dst.Slug != "hello-world" {
+		t.Errorf("TextUnmarshaler not used: %q", dst.Slug)
+	}
+	if dst.Page != 3 {
+		t.Errorf("embedded struct not decoded: %d", dst.Page)
+	}
+	if dst.Filepath == nil || *dst.Filepath != "/a/b" {
+		t.Errorf("pointer field not decoded: %v", dst.Filepath)
+	}
+	if dst.Missing != "unchanged" || dst.Skipped != "" || dst.Untagged != "" {
+		t.Errorf("unexpected fields set: %+v", dst)
+	}
+
+	var bad struct {
+		Name int `path:"name"`
+	}
+	if err := ps.Decode(&bad); err == nil {
+		t.Error("expected parse error decoding name as int")
+	}
+
+	var overflow struct {
+		Small int8 `path:"id"`
+	}
+	ps[0].Value = "4200"
+	if err := ps.Decode(&overflow); err == nil {
+		t.Error("expected range error decoding into int8")
+	}
+
+	if err := ps.Decode(dst); err == nil {
+		t.Error("expected error decoding into non-pointer")
+	}
+}
+
+func TestParamsCloneMap(t *testing.T) {
+	ps := Params{
+		Param{"id", "42"},
+		Param{"name", "gopher"},
+		Param{"id", "43"},
+	}
+
+	cloned := ps.Clone()
+	m := ps.Map()
+	ps[1].Value = "changed"
+	if cloned.ByName("name") != "gopher" {
+		t.Errorf("clone shares backing array: %v", cloned)
+	}
+	if len(m) != 2 || m["name"] != "gopher" || m["id"] != "42" {
+		t.Errorf("unexpected map: %v", m)
+	}
+	if Params(nil).Clone() != nil {
+		t.Error("expected nil clone of empty params")
+	}
+}
+
+func TestRouterParamsReuse(t *testing.T) {
+	var retained, cloned Params
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		if retained == nil {
+			retained, cloned = p, p.Clone()
+		}
+		return true, nil
+	})
+
+	ctx := context.Background()
+	_, _ = router.Serve(ctx, "/user/gopher", struct{}{})
+	_, _ = router.Serve(ctx, "/user/other", struct{}{})
+	if got := retained.ByName("name"); got == "other" {
+		t.Errorf("retained params observed a later request: %q", got)
+	}
+	if got := cloned.ByName("name"); got != "gopher" {
+		t.Errorf("cloned params changed: %q", got)
+	}
+}
+
+func TestParamsSegmentsOf(t *testing.T) {
+	ps := Params{
+		Param{"filepath", "/a/b/c.go"},
+		Param{"dir", "/a/b/"},
+		Param{"root", "/"},
+		Param{"name", "gopher"},
+	}
+	tests := map[string][]string{
+		"filepath": {"a", "b", "c.go"},
+		"dir":      {"a", "b", ""},
+		"root":     nil,
+		"name":     {"gopher"},
+		"nope":     nil,
+	}
+	for name, want := range tests {
+		if got := ps.SegmentsOf(name); !reflect.DeepEqual(got, want) {
+			t.Errorf("SegmentsOf(%s): expected %q but got %q", name, want, got)
+		}
+	}
+}
+
+func TestParamsSegments(t *testing.T) {
+	router := NewWithConfig(RouterConfig[struct{}]{Separator: '.'})
+	var ps Params
+	router.AddHandler("orders.:id.*rest", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		ps = p.Clone()
+		return true, nil
+	})
+	if found, _ := router.Serve(context.Background(), "orders.42.items.a/b", struct{}{}); !found {
+		t.Fatal("expected orders.42.items.a/b to be found")
+	}
+
+	segs := ps.Segments('.')
+	if got, want := segs.Of("rest"), []string{"items", "a/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected segments %q but got %q", want, got)
+	}
+	if got, want := segs.Of("id"), []string{"42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected segments %q but got %q", want, got)
+	}
+	if got := segs.Of("nope"); got != nil {
+		t.Errorf("expected no segments for a missing param, got %q", got)
+	}
+	if first, second := segs.Of("rest"), segs.Of("rest"); &first[0] != &second[0] {
+		t.Error("expected the segments to be cached")
+	}
+	if got, want := (Params{{"filepath", "/a/b"}}).Segments(0).Of("filepath"), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the default separator to split %q but got %q", want, got)
+	}
+}
@@ -0,0 +1,47 @@
+// Package pathroutertest provides utilities for testing code using pathrouter.
+package pathroutertest
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+// Unexercised returns the patterns of the routes not matched by Serve,
+// ServeAll, Lookup or LookupPath in registration order.
+//
+// Requires RouterConfig.RouteStats to be set: otherwise all routes are
+// reported as unexercised.
+func Unexercised[W any](r *pathrouter.Router[W]) []string {
+	var out []string
+	for _, st := range r.RouteStats() {
+		if st.Matches == 0 && st.Lookups == 0 {
+			out = append(out, st.Pattern)
+		}
+	}
+	return out
+}
+
+// ReportCoverage logs the number of exercised routes and the unexercised
+// patterns, see Unexercised.
+func ReportCoverage[W any](t testing.TB, r *pathrouter.Router[W]) {
+	t.Helper()
+	total := len(r.RouteStats())
+	unexercised := Unexercised(r)
+	msg := "route coverage: " + strconv.Itoa(total-len(unexercised)) + "/" + strconv.Itoa(total) + " routes exercised"
+	if len(unexercised) != 0 {
+		msg += ", unexercised:\n\t" + strings.Join(unexercised, "\n\t")
+	}
+	t.Log(msg)
+}
+
+// AssertCoverage fails the test if any route was not exercised, listing the
+// unexercised patterns, see Unexercised.
+func AssertCoverage[W any](t testing.TB, r *pathrouter.Router[W]) {
+	t.Helper()
+	if unexercised := Unexercised(r); len(unexercised) != 0 {
+		t.Errorf("%d of %d routes not exercised:\n\t%s", len(unexercised), len(r.RouteStats()), strings.Join(unexercised, "\n\t"))
+	}
+}
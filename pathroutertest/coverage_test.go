@@ -0,0 +1,66 @@
+package pathroutertest
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+// recordingTB records the errors and logs of a test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, format)
+}
+
+func (r *recordingTB) Log(args ...any) {
+	for _, arg := range args {
+		r.logs = append(r.logs, arg.(string))
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	handle := func(ctx context.Context, reqPath string, p pathrouter.Params, rw struct{}) (bool, error) {
+		return true, nil
+	}
+	conf := pathrouter.DefaultConfig[struct{}]()
+	conf.RouteStats = true
+	router := pathrouter.NewWithConfig(conf)
+	for _, pattern := range []string{"/", "/user/:name", "/admin", "/files/*path"} {
+		router.AddHandler(pattern, handle)
+	}
+
+	_, _ = router.Serve(context.Background(), "/user/gopher", struct{}{})
+	_ = router.Lookup("/files/a.txt")
+
+	if got, want := Unexercised(router), []string{"/", "/admin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected unexercised %v but got %v", want, got)
+	}
+
+	rec := &recordingTB{TB: t}
+	ReportCoverage(rec, router)
+	if len(rec.logs) != 1 || !strings.HasPrefix(rec.logs[0], "route coverage: 2/4 routes exercised, unexercised:") {
+		t.Errorf("unexpected report: %q", rec.logs)
+	}
+	AssertCoverage(rec, router)
+	if len(rec.errors) != 1 {
+		t.Errorf("expected a coverage error, got %q", rec.errors)
+	}
+
+	_, _, _ = router.LookupPath("/")
+	_, _ = router.Serve(context.Background(), "/admin", struct{}{})
+	rec = &recordingTB{TB: t}
+	AssertCoverage(rec, router)
+	if len(rec.errors) != 0 {
+		t.Errorf("expected full coverage, got %q", rec.errors)
+	}
+}
@@ -0,0 +1,264 @@
+package pathrouter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// patternPart is a parsed element of a route pattern.
+type patternPart struct {
+	// kind is static, param or catchAll.
+	kind nodeType
+	// value is the literal text for static parts or the name for wildcards.
+	value string
+}
+
+// cleanPattern adds a missing leading slash to the pattern, removes the {$} end
+// marker and converts ServeMux style wildcards to the native syntax. Returns the
+// path to insert into the tree and if it was anchored.
+func cleanPattern(pattern string) (string, bool, error) {
+	path := pattern
+	if len(path) == 0 {
+		path = "/"
+	} else if path[0] != '/' {
+		path = "/" + path
+	}
+
+	anchored := strings.HasSuffix(path, anchorSuffix)
+	if anchored {
+		path = path[:len(path)-len(anchorSuffix)]
+		if path[len(path)-1] != '/' {
+			return "", false, errors.Errorf("the {$} end marker must follow a trailing slash in path '%s'", pattern)
+		}
+	}
+
+	path, err := convertBraces(path, pattern)
+	if err != nil {
+		return "", false, err
+	}
+	return path, anchored, nil
+}
+
+// convertBraces converts the ServeMux style {name} and {name...} wildcards in
+// the path to the native :name and *name wildcards. The wildcards must be full
+// path segments.
+func convertBraces(path, pattern string) (string, error) {
+	if strings.IndexAny(path, "{}") < 0 {
+		return path, nil
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.IndexAny(path, "{}")
+		if start < 0 {
+			sb.WriteString(path)
+			return sb.String(), nil
+		}
+		if path[start] == '}' {
+			return "", errors.Errorf("unmatched '}' in path '%s'", pattern)
+		}
+		end := strings.IndexAny(path[start+1:], "{}")
+		if end < 0 || path[start+1+end] != '}' {
+			return "", errors.Errorf("unmatched '{' in path '%s'", pattern)
+		}
+		end += start + 1
+
+		name := path[start+1 : end]
+		prefix := byte(':')
+		if name == "$" {
+			return "", errors.Errorf("the {$} end marker must be at the end of path '%s'", pattern)
+		}
+		if strings.HasSuffix(name, "...") {
+			name = name[:len(name)-3]
+			prefix = '*'
+		}
+		if name == "" || strings.ContainsAny(name, "/:*.") {
+			return "", errors.Errorf("invalid wildcard name '%s' in path '%s'", path[start:end+1], pattern)
+		}
+		// as with ServeMux a wildcard must be a full path segment
+		if (start != 0 && path[start-1] != '/') || (end+1 < len(path) && path[end+1] != '/') {
+			return "", errors.Errorf("wildcard '%s' must be a full path segment in path '%s'", path[start:end+1], pattern)
+		}
+
+		sb.WriteString(path[:start])
+		sb.WriteByte(prefix)
+		sb.WriteString(name)
+		path = path[end+1:]
+	}
+}
+
+// SplitMethodPattern splits a ServeMux style "METHOD /path" pattern into the
+// method and the path. The method is empty if the pattern has no method.
+func SplitMethodPattern(pattern string) (method, path string) {
+	i := strings.IndexAny(pattern, " \t")
+	if i < 0 || strings.Contains(pattern[:i], "/") {
+		return "", pattern
+	}
+	return pattern[:i], strings.TrimLeft(pattern[i+1:], " \t")
+}
+
+// ValidatePattern checks if the pattern is valid, applying the same checks as
+// AddHandler: wildcard placement, empty or duplicate wildcard names and the
+// {$} end marker.
+//
+// Conflicts with other routes depend on the routes already registered and are
+// not checked.
+func ValidatePattern(pattern string) error {
+	_, _, err := parsePattern(pattern)
+	return err
+}
+
+// parsePattern parses and validates a route pattern.
+//
+// Returns the parts of the pattern and if the pattern was anchored with the {$}
+// end marker.
+func parsePattern(pattern string) ([]patternPart, bool, error) {
+	path, anchored, err := cleanPattern(pattern)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var parts []patternPart
+	for {
+		wildcard, i, valid := findWildcard(path)
+		if i < 0 {
+			break
+		}
+		if !valid {
+			return nil, false, errors.Errorf("only one wildcard per path segment is allowed, has: '%s' in path '%s'", wildcard, pattern)
+		}
+		if len(wildcard) < 2 {
+			return nil, false, errors.Errorf("wildcards must be named with a non-empty name in path '%s'", pattern)
+		}
+
+		if wildcard[0] == '*' {
+			if i+len(wildcard) != len(path) {
+				return nil, false, errors.Errorf("catch-all routes are only allowed at the end of the path in path '%s'", pattern)
+			}
+			if i == 0 || path[i-1] != '/' {
+				return nil, false, errors.Errorf("no / before catch-all in path '%s'", pattern)
+			}
+		}
+
+		if i > 0 {
+			parts = append(parts, patternPart{kind: static, value: path[:i]})
+		}
+		for _, part := range parts {
+			if part.kind != static && part.value == wildcard[1:] {
+				return nil, false, errors.Errorf("duplicate wildcard name '%s' in path '%s'", wildcard[1:], pattern)
+			}
+		}
+
+		kind := param
+		if wildcard[0] == '*' {
+			kind = catchAll
+		}
+		parts = append(parts, patternPart{kind: kind, value: wildcard[1:]})
+		path = path[i+len(wildcard):]
+	}
+	if len(path) != 0 {
+		parts = append(parts, patternPart{kind: static, value: path})
+	}
+
+	return parts, anchored, nil
+}
+
+// PatternRegexp returns an anchored regular expression equivalent to the
+// pattern, for use with systems only accepting regular expression routes.
+//
+// Named parameters are converted to named groups matching a non-empty path
+// segment. Catch-all parameters are converted to named groups matching the
+// rest of the path including the leading slash, the same as the router.
+//
+// Param names which are not valid group names have the invalid characters
+// replaced with '_', for example :user-id is converted to the group user_id.
+//
+// Trailing slash and case-insensitive corrections are not represented.
+func PatternRegexp(pattern string) (*regexp.Regexp, error) {
+	parts, _, err := parsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('^')
+	for i, part := range parts {
+		switch part.kind {
+		case static:
+			lit := part.value
+			// the catch-all group includes the preceding slash
+			if i+1 < len(parts) && parts[i+1].kind == catchAll {
+				lit = lit[:len(lit)-1]
+			}
+			sb.WriteString(regexp.QuoteMeta(lit))
+		case param:
+			sb.WriteString("(?P<" + regexpGroupName(part.value) + ">[^/]+)")
+		case catchAll:
+			sb.WriteString("(?P<" + regexpGroupName(part.value) + ">/.*)")
+		}
+	}
+	sb.WriteByte('$')
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "pattern '%s'", pattern)
+	}
+	return re, nil
+}
+
+// regexpGroupName converts the param name to a valid regexp group name by
+// replacing the characters other than ASCII letters, digits and '_' with '_'.
+func regexpGroupName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// BuildPath builds a path from the pattern by substituting the params.
+//
+// Named param values must not be empty or contain a slash. Catch-all param
+// values contain the rest of the path including the leading slash, which is
+// added if missing.
+func BuildPath(pattern string, ps Params) (string, error) {
+	parts, _, err := parsePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i, part := range parts {
+		switch part.kind {
+		case static:
+			lit := part.value
+			// the catch-all value includes the preceding slash
+			if i+1 < len(parts) && parts[i+1].kind == catchAll {
+				lit = lit[:len(lit)-1]
+			}
+			sb.WriteString(lit)
+		case param:
+			val, err := ps.lookup(part.value)
+			if err != nil {
+				return "", err
+			}
+			if len(val) == 0 || strings.IndexByte(val, '/') >= 0 {
+				return "", errors.Errorf("invalid value for param %s: %q", part.value, val)
+			}
+			sb.WriteString(val)
+		case catchAll:
+			val, err := ps.lookup(part.value)
+			if err != nil {
+				return "", err
+			}
+			if !strings.HasPrefix(val, "/") {
+				sb.WriteByte('/')
+			}
+			sb.WriteString(val)
+		}
+	}
+	return sb.String(), nil
+}
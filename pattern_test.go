@@ -0,0 +1,237 @@
+package pathrouter
+
+import (
+	"testing"
+)
+
+func TestPatternRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		regexp  string
+		matches map[string]Params
+		misses  []string
+	}{{
+		pattern: "/",
+		regexp:  `^/$`,
+		matches: map[string]Params{"/": nil},
+		misses:  []string{"", "/a"},
+	}, {
+		pattern: "/blog/:category/:post",
+		regexp:  `^/blog/(?P<category>[^/]+)/(?P<post>[^/]+)$`,
+		matches: map[string]Params{
+			"/blog/go/request-routers": {{"category", "go"}, {"post", "request-routers"}},
+		},
+		misses: []string{"/blog/go/request-routers/", "/blog/go/", "/blog/go/request-routers/comments"},
+	}, {
+		pattern: "/files/*filepath",
+		regexp:  `^/files(?P<filepath>/.*)$`,
+		matches: map[string]Params{
+			"/files/":                       {{"filepath", "/"}},
+			"/files/LICENSE":                {{"filepath", "/LICENSE"}},
+			"/files/templates/article.html": {{"filepath", "/templates/article.html"}},
+		},
+		misses: []string{"/files"},
+	}, {
+		pattern: "src/v1.0/:file",
+		regexp:  `^/src/v1\.0/(?P<file>[^/]+)$`,
+		matches: map[string]Params{"/src/v1.0/main.go": {{"file", "main.go"}}},
+		misses:  []string{"/src/v100/main.go"},
+	}, {
+		pattern: "/dir/{$}",
+		regexp:  `^/dir/$`,
+		matches: map[string]Params{"/dir/": nil},
+		misses:  []string{"/dir"},
+	}, {
+		pattern: "/catch-all/:user-id/*catch-all",
+		regexp:  `^/catch-all/(?P<user_id>[^/]+)(?P<catch_all>/.*)$`,
+		matches: map[string]Params{"/catch-all/1/x": {{"user_id", "1"}, {"catch_all", "/x"}}},
+		misses:  []string{"/catch-all/1"},
+	}}
+
+	for _, test := range tests {
+		re, err := PatternRegexp(test.pattern)
+		if err != nil {
+			t.Fatalf("pattern %s: %v", test.pattern, err)
+		}
+		if re.String() != test.regexp {
+			t.Errorf("pattern %s: expected regexp %s but got %s", test.pattern, test.regexp, re.String())
+		}
+		for path, want := range test.matches {
+			m := re.FindStringSubmatch(path)
+			if m == nil {
+				t.Errorf("pattern %s: expected match for %s", test.pattern, path)
+				continue
+			}
+			var got Params
+			for i, name := range re.SubexpNames() {
+				if name != "" {
+					got = append(got, Param{Key: name, Value: m[i]})
+				}
+			}
+			if len(got) != len(want) {
+				t.Errorf("pattern %s: wrong params for %s: %v", test.pattern, path, got)
+				continue
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("pattern %s: wrong params for %s: %v", test.pattern, path, got)
+				}
+			}
+		}
+		for _, path := range test.misses {
+			if re.MatchString(path) {
+				t.Errorf("pattern %s: unexpected match for %s", test.pattern, path)
+			}
+		}
+	}
+}
+
+func TestPatternRegexpInvalid(t *testing.T) {
+	patterns := []string{
+		"/user/:",
+		"/user/:a:b",
+		"/src/*filepath/x",
+		"/src*filepath",
+		"/dir{$}",
+		"/user/{name",
+		"/user/name}",
+		"/user/{}",
+		"/user/{a.b}",
+		"/src/{path...}/x",
+		"/dir/{$}/x",
+	}
+	for _, pattern := range patterns {
+		if _, err := PatternRegexp(pattern); err == nil {
+			t.Errorf("pattern %s: expected error", pattern)
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	valid := []string{
+		"",
+		"/",
+		"/user/:name",
+		"/src/*filepath",
+		"/user/{name}/files/{path...}",
+		"/dir/{$}",
+		"/catch-all/*catch-all",
+	}
+	for _, pattern := range valid {
+		if err := ValidatePattern(pattern); err != nil {
+			t.Errorf("pattern %s: unexpected error %v", pattern, err)
+		}
+		func() {
+			defer func() {
+				if rerr := recover(); rerr != nil {
+					t.Errorf("pattern %s: valid pattern failed to register: %v", pattern, rerr)
+				}
+			}()
+			New[struct{}]().AddHandler(pattern, fakeHandler(pattern))
+		}()
+	}
+
+	invalid := []string{
+		"/user/:",
+		"/user/:a:b",
+		"/src/*filepath/x",
+		"/src*filepath",
+		"/dir{$}",
+		"/user/{name",
+		"/user/{}",
+		"/src/{path...}/x",
+		"/a/:id/b/:id",
+		"/a/:id/*id",
+		"/a/{id}/b/{id...}",
+		"static/{file}.json",
+		"/static/x{file}",
+		"/static/{a}{b}",
+	}
+	for _, pattern := range invalid {
+		if err := ValidatePattern(pattern); err == nil {
+			t.Errorf("pattern %s: expected error", pattern)
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("pattern %s: invalid pattern was registered", pattern)
+				}
+			}()
+			New[struct{}]().AddHandler(pattern, fakeHandler(pattern))
+		}()
+	}
+}
+
+func TestBuildPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		ps      Params
+		path    string
+		err     bool
+	}{
+		{"/", nil, "/", false},
+		{"/blog/:category/:post", Params{{"post", "routers"}, {"category", "go"}}, "/blog/go/routers", false},
+		{"/files/*filepath", Params{{"filepath", "/a/b"}}, "/files/a/b", false},
+		{"/files/*filepath", Params{{"filepath", "a/b"}}, "/files/a/b", false},
+		{"/files/*filepath", Params{{"filepath", "/"}}, "/files/", false},
+		{"/dir/{$}", nil, "/dir/", false},
+		{"/user/:name", nil, "", true},
+		{"/user/:name", Params{{"name", "a/b"}}, "", true},
+		{"/user/:name", Params{{"name", ""}}, "", true},
+		{"/user/:", nil, "", true},
+	}
+	for _, test := range tests {
+		path, err := BuildPath(test.pattern, test.ps)
+		if (err != nil) != test.err {
+			t.Errorf("pattern %s: unexpected error: %v", test.pattern, err)
+			continue
+		}
+		if path != test.path {
+			t.Errorf("pattern %s: expected path %s but got %s", test.pattern, test.path, path)
+		}
+	}
+}
+
+func TestServeMuxPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		anchored bool
+	}{
+		{"/user/{name}", "/user/:name", false},
+		{"/user/{name}/posts/{id}", "/user/:name/posts/:id", false},
+		{"/files/{path...}", "/files/*path", false},
+		{"/dir/{$}", "/dir/", true},
+		{"/user/{name}/{$}", "/user/:name/", true},
+		{"/user/:name", "/user/:name", false},
+	}
+	for _, test := range tests {
+		path, anchored, err := cleanPattern(test.pattern)
+		if err != nil {
+			t.Errorf("pattern %s: unexpected error: %v", test.pattern, err)
+			continue
+		}
+		if path != test.path || anchored != test.anchored {
+			t.Errorf("pattern %s: expected %q %v but got %q %v", test.pattern, test.path, test.anchored, path, anchored)
+		}
+	}
+}
+
+func TestSplitMethodPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		method  string
+		path    string
+	}{
+		{"GET /user/{name}", "GET", "/user/{name}"},
+		{"POST  /upload", "POST", "/upload"},
+		{"/user/{name}", "", "/user/{name}"},
+		{"/a b", "", "/a b"},
+	}
+	for _, test := range tests {
+		method, path := SplitMethodPattern(test.pattern)
+		if method != test.method || path != test.path {
+			t.Errorf("pattern %q: expected %q %q but got %q %q", test.pattern, test.method, test.path, method, path)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package pathrouter
+
+// ParamsAllocator allocates the params passed to the handles, see
+// RouterConfig.ParamsAllocator.
+type ParamsAllocator interface {
+	// Get returns params with length zero and at least the capacity.
+	Get(capacity int) *Params
+	// Put releases params returned by Get once the handle has returned.
+	// Params returned by LookupPath and Lookup are not released.
+	Put(ps *Params)
+}
+
+// UnpooledParams is a ParamsAllocator allocating new params for each request
+// which the handles own and can retain without Params.Clone.
+type UnpooledParams struct{}
+
+// Get allocates new params.
+func (UnpooledParams) Get(capacity int) *Params {
+	ps := make(Params, 0, capacity)
+	return &ps
+}
+
+// Put does nothing: the params are left to the garbage collector.
+func (UnpooledParams) Put(ps *Params) {}
+
+// getParams returns params with the capacity for the registered routes.
+func (r *Router[W]) getParams() *Params {
+	maxParams := int(r.maxParams.Load())
+	if alloc := r.conf.ParamsAllocator; alloc != nil {
+		return alloc.Get(maxParams)
+	}
+	ps := r.paramsPool.Get().(*Params)
+	if cap(*ps) < maxParams {
+		// pooled before maxParams increased
+		*ps = make(Params, 0, maxParams)
+	}
+	*ps = (*ps)[0:0] // reset slice
+	return ps
+}
+
+// putParams releases params returned by getParams.
+func (r *Router[W]) putParams(ps *Params) {
+	if ps == nil {
+		return
+	}
+	if alloc := r.conf.ParamsAllocator; alloc != nil {
+		alloc.Put(ps)
+		return
+	}
+	// clear the values so retained references don't see later requests
+	for i := range *ps {
+		(*ps)[i] = Param{}
+	}
+	r.paramsPool.Put(ps)
+}
+
+// _ is a type assertion
+var _ ParamsAllocator = UnpooledParams{}
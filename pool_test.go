@@ -0,0 +1,62 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+// countingAllocator is a ParamsAllocator counting the params in use.
+type countingAllocator struct {
+	UnpooledParams
+	inUse int
+	gets  int
+}
+
+func (a *countingAllocator) Get(capacity int) *Params {
+	a.inUse++
+	a.gets++
+	return a.UnpooledParams.Get(capacity)
+}
+
+func (a *countingAllocator) Put(ps *Params) {
+	a.inUse--
+}
+
+func TestRouterParamsAllocator(t *testing.T) {
+	var retained []Params
+	handle := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		retained = append(retained, p)
+		return p.ByName("name") != "decline", nil
+	}
+
+	conf := DefaultConfig[struct{}]()
+	conf.ParamsAllocator = UnpooledParams{}
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", handle)
+	for _, name := range []string{"a", "b", "c"} {
+		if found, err := router.Serve(context.Background(), "/user/"+name, struct{}{}); !found || err != nil {
+			t.Fatalf("expected found, got found=%v err=%v", found, err)
+		}
+	}
+	for i, name := range []string{"a", "b", "c"} {
+		if got := retained[i].ByName("name"); got != name {
+			t.Errorf("expected retained param %q, got %q", name, got)
+		}
+	}
+
+	alloc := &countingAllocator{}
+	conf = DefaultConfig[struct{}]()
+	conf.ParamsAllocator = alloc
+	conf.Fallthrough = true
+	router = NewWithConfig(conf)
+	router.AddHandler("/user/:name", handle)
+	router.AddHandler("/user/*rest", handle)
+	router.AddHandler("/files/*path", handle)
+	for _, path := range []string{"/user/a", "/user/decline", "/files/x", "/user/a/b", "/missing"} {
+		_, _ = router.Serve(context.Background(), path, struct{}{})
+		_, _ = router.ServeAll(context.Background(), path, struct{}{})
+	}
+	if alloc.gets == 0 || alloc.inUse != 0 {
+		t.Errorf("expected the params to be released, got gets=%d in use=%d", alloc.gets, alloc.inUse)
+	}
+}
@@ -0,0 +1,117 @@
+// Package promroute exposes Prometheus metrics labeled by route pattern.
+//
+// Observer implements pathrouter.Observer and prometheus.Collector:
+//
+//	obs := promroute.NewObserver(promroute.Config{})
+//	prometheus.MustRegister(obs)
+//	conf := pathrouter.DefaultConfig[W]()
+//	conf.Observer = obs
+//	router := pathrouter.NewWithConfig(conf)
+package promroute
+
+import (
+	"strconv"
+
+	"github.com/aperturerobotics/pathrouter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UnmatchedPattern is the pattern label of requests not handled by any route.
+const UnmatchedPattern = "<unmatched>"
+
+// Config is the configuration for an Observer.
+type Config struct {
+	// Namespace is the metric namespace. Defaults to "pathrouter".
+	Namespace string
+	// Subsystem is the metric subsystem, if any.
+	Subsystem string
+	// Buckets are the latency histogram buckets in seconds.
+	// Defaults to prometheus.DefBuckets.
+	Buckets []float64
+	// ConstLabels are added to all metrics, for example to identify the router.
+	ConstLabels prometheus.Labels
+}
+
+// Observer records the requests, handler errors and latencies of a router by
+// route pattern.
+type Observer struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// NewObserver constructs a new Observer with the given config.
+//
+// The metrics are:
+//
+//	<namespace>_requests_total{pattern, handled}
+//	<namespace>_handler_errors_total{pattern}
+//	<namespace>_request_duration_seconds{pattern}
+func NewObserver(conf Config) *Observer {
+	namespace := conf.Namespace
+	if namespace == "" {
+		namespace = "pathrouter"
+	}
+	buckets := conf.Buckets
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+	return &Observer{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   conf.Subsystem,
+			Name:        "requests_total",
+			Help:        "Number of requests served by route pattern.",
+			ConstLabels: conf.ConstLabels,
+		}, []string{"pattern", "handled"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   conf.Subsystem,
+			Name:        "handler_errors_total",
+			Help:        "Number of errors returned by the handles by route pattern.",
+			ConstLabels: conf.ConstLabels,
+		}, []string{"pattern"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   conf.Subsystem,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of the lookup and handling of requests by route pattern.",
+			Buckets:     buckets,
+			ConstLabels: conf.ConstLabels,
+		}, []string{"pattern"}),
+	}
+}
+
+// OnLookup records a served request.
+func (o *Observer) OnLookup(path, matchedPattern string, durationNanos int64, found bool) {
+	if matchedPattern == "" {
+		matchedPattern = UnmatchedPattern
+	}
+	o.requests.WithLabelValues(matchedPattern, strconv.FormatBool(found)).Inc()
+	o.latency.WithLabelValues(matchedPattern).Observe(float64(durationNanos) / 1e9)
+}
+
+// OnHandlerError records a handler error.
+func (o *Observer) OnHandlerError(pattern string, err error) {
+	o.errors.WithLabelValues(pattern).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (o *Observer) Describe(ch chan<- *prometheus.Desc) {
+	o.requests.Describe(ch)
+	o.errors.Describe(ch)
+	o.latency.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (o *Observer) Collect(ch chan<- prometheus.Metric) {
+	o.requests.Collect(ch)
+	o.errors.Collect(ch)
+	o.latency.Collect(ch)
+}
+
+// _ is a type assertion
+var (
+	_ pathrouter.Observer  = ((*Observer)(nil))
+	_ prometheus.Collector = ((*Observer)(nil))
+)
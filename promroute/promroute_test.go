@@ -0,0 +1,49 @@
+package promroute
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/pathrouter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserver(t *testing.T) {
+	obs := NewObserver(Config{ConstLabels: prometheus.Labels{"router": "test"}})
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(obs)
+
+	conf := pathrouter.DefaultConfig[struct{}]()
+	conf.Observer = obs
+	router := pathrouter.NewWithConfig(conf)
+	router.AddHandler("/user/:name", func(ctx context.Context, reqPath string, p pathrouter.Params, rw struct{}) (bool, error) {
+		if p.ByName("name") == "error" {
+			return true, errors.New("failed")
+		}
+		return true, nil
+	})
+
+	ctx := context.Background()
+	for _, path := range []string{"/user/a", "/user/b", "/user/error", "/missing"} {
+		_, _ = router.Serve(ctx, path, struct{}{})
+	}
+
+	expected := `
+# HELP pathrouter_handler_errors_total Number of errors returned by the handles by route pattern.
+# TYPE pathrouter_handler_errors_total counter
+pathrouter_handler_errors_total{pattern="/user/:name",router="test"} 1
+# HELP pathrouter_requests_total Number of requests served by route pattern.
+# TYPE pathrouter_requests_total counter
+pathrouter_requests_total{handled="false",pattern="<unmatched>",router="test"} 1
+pathrouter_requests_total{handled="true",pattern="/user/:name",router="test"} 3
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "pathrouter_requests_total", "pathrouter_handler_errors_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(obs, "pathrouter_request_duration_seconds"); n != 2 {
+		t.Errorf("expected 2 latency histograms but got %d", n)
+	}
+}
@@ -0,0 +1,124 @@
+package pathrouter
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ToProto returns a snapshot of the route table.
+//
+// Every route must have been registered with a handler name, see WithName,
+// unless it is an alias or a redirect. The metadata values are encoded as
+// JSON. The route table can be re-created with FromProto.
+func (r *Router[W]) ToProto() (*RouteTableSnapshot, error) {
+	t := r.load()
+	snap := &RouteTableSnapshot{
+		Version: t.version,
+		Routes:  make([]*RouteSnapshot, 0, len(t.routes)),
+	}
+	for _, rt := range sortRoutes(t.routes) {
+		entry, err := r.routeEntry(rt)
+		if err != nil {
+			return nil, err
+		}
+		var metadata map[string]string
+		if len(entry.Metadata) != 0 {
+			metadata = make(map[string]string, len(entry.Metadata))
+			for key, value := range entry.Metadata {
+				data, err := json.Marshal(value)
+				if err != nil {
+					return nil, errors.Wrapf(err, "route '%s': metadata '%s'", rt.pattern, key)
+				}
+				metadata[key] = string(data)
+			}
+		}
+		rs := &RouteSnapshot{
+			Pattern:  entry.Pattern,
+			Handler:  entry.Handler,
+			Metadata: metadata,
+			Version:  rt.version,
+			Tags:     entry.Tags,
+			Defaults: entry.Defaults,
+			Alias:    entry.Alias,
+			Locale:   entry.Locale,
+			Redirect: entry.Redirect,
+			Rewrite:  entry.Rewrite,
+		}
+		if entry.Timeout != "" {
+			rs.TimeoutNanos = int64(rt.timeout)
+		}
+		snap.Routes = append(snap.Routes, rs)
+	}
+	return snap, nil
+}
+
+// FromProto replaces the route table with the routes from the snapshot.
+//
+// The handles are looked up by handler name with resolve, which returns nil if
+// the name is unknown. The versions of the routes are kept: the version of the
+// snapshot must be newer than the current version, otherwise
+// ErrVersionMismatch is returned. If any of the routes are invalid, conflict
+// or cannot be resolved an error is returned and the router is not changed.
+func (r *Router[W]) FromProto(snap *RouteTableSnapshot, resolve func(name string) Handle[W]) error {
+	version := snap.GetVersion()
+	routes := make(map[string]*route[W], len(snap.GetRoutes()))
+	// aliases are resolved with the routes of the snapshot
+	snapTable := &table[W]{routes: routes}
+	for _, rs := range snap.GetRoutes() {
+		entry := RouteEntry{
+			Pattern:  rs.GetPattern(),
+			Handler:  rs.GetHandler(),
+			Tags:     rs.GetTags(),
+			Defaults: rs.GetDefaults(),
+			Alias:    rs.GetAlias(),
+			Locale:   rs.GetLocale(),
+			Redirect: rs.GetRedirect(),
+			Rewrite:  rs.GetRewrite(),
+		}
+		if timeout := rs.GetTimeoutNanos(); timeout != 0 {
+			entry.Timeout = time.Duration(timeout).String()
+		}
+		if len(rs.GetMetadata()) != 0 {
+			entry.Metadata = make(map[string]any, len(rs.GetMetadata()))
+			for key, data := range rs.GetMetadata() {
+				var value any
+				if err := json.Unmarshal([]byte(data), &value); err != nil {
+					return errors.Wrapf(err, "route '%s': metadata '%s'", rs.GetPattern(), key)
+				}
+				entry.Metadata[key] = value
+			}
+		}
+
+		rt, err := r.entryRoute(entry, resolve)
+		if err != nil {
+			return err
+		}
+		if _, exists := routes[rt.pattern]; exists {
+			return errors.Errorf("duplicate route '%s'", rt.pattern)
+		}
+		if rt.canonical != "" {
+			if _, err := r.aliasOf(snapTable, rt, rt.canonical); err != nil {
+				return errors.Wrapf(err, "route '%s'", rt.pattern)
+			}
+		}
+		rt.version = rs.GetVersion()
+		if rt.version > version {
+			version = rt.version
+		}
+		routes[rt.pattern] = rt
+	}
+
+	return r.update(func(old *table[W]) (*table[W], error) {
+		if version <= old.version {
+			return nil, errors.Wrapf(
+				ErrVersionMismatch,
+				"snapshot version %d is not newer than version %d",
+				version,
+				old.version,
+			)
+		}
+		return r.replaceRoutes(old, routes, version)
+	})
+}
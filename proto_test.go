@@ -0,0 +1,117 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestRouterProto(t *testing.T) {
+	registry := NewHandlerRegistry[struct{}]()
+	registry.Register("user", fakeHandler("user"))
+	registry.Register("any", fakeHandler("any"))
+
+	conf := DefaultConfig[struct{}]()
+	conf.Fallthrough = true
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", registry.Lookup("user"), WithName("user"), WithMetadata("scopes", []any{"admin"}))
+	router.AddHandler("/user/*rest", registry.Lookup("any"), WithName("any"))
+
+	snap, err := router.ToProto()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	data, err := snap.MarshalVT()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	decoded := &RouteTableSnapshot{}
+	if err := decoded.UnmarshalVT(data); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !decoded.EqualVT(snap) || decoded.GetRoutes()[0].GetMetadata()["scopes"] != `["admin"]` {
+		t.Fatalf("snapshot did not round-trip: %v", decoded)
+	}
+
+	loaded := NewWithConfig(conf)
+	loaded.AddHandler("/old", fakeHandler("old"), WithName("old"))
+	if err := loaded.FromProto(decoded, registry.Lookup); err != nil {
+		t.Fatal(err.Error())
+	}
+	if loaded.Version() != router.Version() {
+		t.Errorf("expected version %d but got %d", router.Version(), loaded.Version())
+	}
+	if !reflect.DeepEqual(loaded.DeltaSince(0).Added[0].Metadata, map[string]any{"scopes": []any{"admin"}}) {
+		t.Errorf("metadata not loaded: %v", loaded.DeltaSince(0).Added[0])
+	}
+	ctx := context.Background()
+	fakeHandlerValue = ""
+	if found, _ := loaded.Serve(ctx, "/user/gopher", struct{}{}); !found || fakeHandlerValue != "user" {
+		t.Errorf("loaded route not served with precedence: %v %q", found, fakeHandlerValue)
+	}
+	if found, _ := loaded.Serve(ctx, "/old", struct{}{}); found {
+		t.Error("expected the route table to be replaced")
+	}
+
+	// the router is not changed if any route fails
+	decoded.Routes[1].Handler = "unknown"
+	version := loaded.Version()
+	if err := loaded.FromProto(decoded, registry.Lookup); err == nil {
+		t.Error("expected error for unknown handler")
+	}
+	if loaded.Version() != version {
+		t.Error("router changed by failed load")
+	}
+}
+
+func TestRouterProtoRouteKinds(t *testing.T) {
+	registry := NewHandlerRegistry[struct{}]()
+	registry.Register("list", fakeHandler("list"))
+
+	router := New[struct{}]()
+	router.AddHandler("/list/:page", registry.Lookup("list"), WithName("list"), WithTags("public"), WithDefault("page", "1"), WithTimeout(time.Second))
+	router.AddAlias("/l/:page", "/list/:page")
+	router.AddLocaleAlias("de", "/liste/:page", "/list/:page")
+	router.AddRewrite("/all/:page", "/list/:page")
+
+	snap, err := router.ToProto()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	data, err := snap.MarshalVT()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	decoded := &RouteTableSnapshot{}
+	if err := decoded.UnmarshalVT(data); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	loaded := New[struct{}]()
+	if err := loaded.FromProto(decoded, registry.Lookup); err != nil {
+		t.Fatal(err.Error())
+	}
+	if loaded.Hash() != router.Hash() {
+		t.Error("expected the loaded routes to have the same hash")
+	}
+	if res := loaded.Lookup("/l/2"); res.Pattern != "/list/:page" {
+		t.Errorf("expected the alias to be loaded, got %+v", res)
+	}
+	if defs := loaded.Routes(); defs[0].Timeout != time.Second || defs[0].Defaults["page"] != "1" {
+		t.Errorf("expected the timeout and defaults to be loaded, got %+v", defs[0])
+	}
+
+	// the snapshot must advance the version
+	if err := loaded.FromProto(decoded, registry.Lookup); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected version mismatch, got %v", err)
+	}
+	older := decoded.CloneVT()
+	older.Version, older.Routes = 1, older.Routes[:1]
+	older.Routes[0].Version = 1
+	if err := loaded.FromProto(older, registry.Lookup); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected version mismatch for an older snapshot, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package pathrouter
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRedirectLoop is returned when a path is redirected internally more than
+// the maximum number of corrections, see AddRedirect.
+var ErrRedirectLoop = errors.New("too many internal redirects")
+
+// redirectDepthCtxKey is the context key for the number of internal redirects.
+type redirectDepthCtxKey struct{}
+
+// AddRedirect registers a route redirecting the paths matched by the pattern to
+// the target template, for example /old/:id to /new/:id.
+//
+// The params of the pattern are substituted into the template, see BuildPath.
+// The RedirectHandler is called with the target path, if set. Otherwise the
+// target path is served directly: if a path is redirected more than
+// MaxCorrections times ErrRedirectLoop is returned. Lookup returns the target
+// path in the RedirectPath of the result.
+//
+// Panics if the pattern or template is invalid, the template refers to params
+// not in the pattern or the pattern conflicts with a registered pattern.
+func (r *Router[W]) AddRedirect(pattern, toTemplate string, opts ...RouteOption) {
+	r.addRedirect(pattern, toTemplate, false, opts)
+}
+
+// AddRewrite registers a route rewriting the paths matched by the pattern to
+// the target template, for example /old/:id to /new/:id, and serving the
+// target path instead.
+//
+// The params of the pattern are substituted into the template, see BuildPath.
+// Unlike AddRedirect the RedirectHandler is never called: the handle of the
+// target path is called with the target path. If a path is rewritten more than
+// MaxCorrections times ErrRedirectLoop is returned. Lookup returns the result
+// of looking up the target path.
+//
+// Panics if the pattern or template is invalid, the template refers to params
+// not in the pattern or the pattern conflicts with a registered pattern.
+func (r *Router[W]) AddRewrite(pattern, toTemplate string, opts ...RouteOption) {
+	r.addRedirect(pattern, toTemplate, true, opts)
+}
+
+// redirectHandle returns the handle of a redirect or rewrite route to the
+// target template in the form used by the trees, see AddRedirect.
+func (r *Router[W]) redirectHandle(target string, rewrite bool) Handle[W] {
+	return func(ctx context.Context, reqPath string, p Params, rw W) (bool, error) {
+		toPath, err := r.redirectTreePath(target, p)
+		if err != nil {
+			return false, err
+		}
+		if r.conf.RedirectHandler != nil && !rewrite {
+			return r.conf.RedirectHandler(ctx, reqPath, fromTree(toPath, r.conf.Separator), rw)
+		}
+		return r.serveInternal(ctx, toPath, rw)
+	}
+}
+
+// addRedirect registers a redirect or rewrite route, see AddRedirect.
+func (r *Router[W]) addRedirect(pattern, toTemplate string, rewrite bool, opts []RouteOption) {
+	rt, err := r.redirectRoute(pattern, toTemplate, rewrite)
+	if err != nil {
+		panic(err.Error())
+	}
+	if err := applyRouteOptions(rt, opts); err != nil {
+		panic(err.Error())
+	}
+
+	err = r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		t.addRoute(rt, r.conf.Fallthrough)
+		return t, nil
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// redirectRoute constructs a redirect or rewrite route to the target template,
+// see AddRedirect.
+func (r *Router[W]) redirectRoute(pattern, toTemplate string, rewrite bool) (*route[W], error) {
+	rt, err := newRoute[W](r.conf.UnicodeForm.Normalize(pattern), nil, r.conf.Separator)
+	if err != nil {
+		return nil, err
+	}
+	target, err := newRoute[W](r.conf.UnicodeForm.Normalize(toTemplate), nil, r.conf.Separator)
+	if err != nil {
+		return nil, err
+	}
+	for _, part := range target.parts {
+		if part.kind != static && !rt.hasWildcard(part.value) {
+			return nil, errors.Errorf("unknown param '%s' in redirect template '%s'", part.value, toTemplate)
+		}
+	}
+
+	rt.redirect, rt.rewrite = target.path, rewrite
+	rt.handle = r.redirectHandle(target.path, rewrite)
+	return rt, nil
+}
+
+// hasWildcard checks if the route has a named or catch-all param with the name.
+func (rt *route[W]) hasWildcard(name string) bool {
+	for _, part := range rt.parts {
+		if part.kind != static && part.value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// redirectPath substitutes the params into the template in the form used by
+// the trees and returns the path with the separator.
+func (r *Router[W]) redirectPath(tmpl string, ps Params) (string, error) {
+	toPath, err := r.redirectTreePath(tmpl, ps)
+	if err != nil {
+		return "", err
+	}
+	return fromTree(toPath, r.conf.Separator), nil
+}
+
+// redirectTreePath substitutes the params into the template in the form used
+// by the trees and returns the path in the form used by the trees.
+//
+// The params are already unescaped and normalized: the path must be served
+// without normalizing it again, see serveInternal.
+func (r *Router[W]) redirectTreePath(tmpl string, ps Params) (string, error) {
+	if customSeparator(r.conf.Separator) {
+		ps = ps.Clone()
+		// swapping the separator converts the values back to the tree form
+		paramsFromTree(ps, r.conf.Separator)
+	}
+	return BuildPath(tmpl, ps)
+}
+
+// maxRedirects returns the maximum number of internal redirects of a path.
+func (r *Router[W]) maxRedirects() int {
+	if r.conf.MaxCorrections == 0 {
+		return DefaultMaxCorrections
+	}
+	return r.conf.MaxCorrections
+}
+
+// serveInternal serves a path in the form used by the trees the request was
+// redirected to internally. The path is not normalized again: escapes in the
+// params are not decoded twice.
+// Returns ErrRedirectLoop if the maximum number of redirects is exceeded.
+func (r *Router[W]) serveInternal(ctx context.Context, treePath string, rw W) (bool, error) {
+	depth, _ := ctx.Value(redirectDepthCtxKey{}).(int)
+	if depth >= r.maxRedirects() {
+		return false, errors.Wrapf(ErrRedirectLoop, "path %s", fromTree(treePath, r.conf.Separator))
+	}
+	ctx = context.WithValue(ctx, redirectDepthCtxKey{}, depth+1)
+	if err := r.ctxErr(ctx); err != nil {
+		return r.canceled(ctx, fromTree(treePath, r.conf.Separator), rw, err)
+	}
+	return r.serveNormalized(ctx, treePath, rw, nil)
+}
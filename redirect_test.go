@@ -0,0 +1,164 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRouterAddRedirect(t *testing.T) {
+	var served []string
+	handle := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		served = append(served, reqPath)
+		return true, nil
+	}
+
+	router := New[struct{}]()
+	router.AddHandler("/new/:id", handle)
+	router.AddHandler("/files/*path", handle)
+	router.AddRedirect("/old/:id", "/new/:id")
+	router.AddRedirect("/docs/:section/*path", "/files/{section}/{path...}")
+	router.AddRedirect("/a", "/b")
+	router.AddRedirect("/b", "/a")
+
+	ctx := context.Background()
+	tests := []struct {
+		path   string
+		target string
+	}{
+		{"/old/42", "/new/42"},
+		{"/docs/api/v1/index", "/files/api/v1/index"},
+	}
+	for _, test := range tests {
+		served = nil
+		if found, err := router.Serve(ctx, test.path, struct{}{}); !found || err != nil {
+			t.Fatalf("path %s: expected found, got found=%v err=%v", test.path, found, err)
+		}
+		if len(served) != 1 || served[0] != test.target {
+			t.Errorf("path %s: expected target %s to be served, got %v", test.path, test.target, served)
+		}
+		if res := router.Lookup(test.path); !res.Found() || res.RedirectPath != test.target {
+			t.Errorf("path %s: unexpected lookup result %+v", test.path, res)
+		}
+	}
+
+	if _, err := router.Serve(ctx, "/a", struct{}{}); !errors.Is(err, ErrRedirectLoop) {
+		t.Errorf("expected redirect loop error, got %v", err)
+	}
+
+	var redirects []string
+	conf := DefaultConfig[struct{}]()
+	conf.RedirectHandler = func(ctx context.Context, fromPath, toPath string, rw struct{}) (bool, error) {
+		redirects = append(redirects, fromPath+" "+toPath)
+		return true, nil
+	}
+	withHandler := NewWithConfig(conf)
+	withHandler.AddRedirect("/old/:id", "/new/:id")
+	if found, err := withHandler.Serve(ctx, "/old/7", struct{}{}); !found || err != nil || len(redirects) != 1 || redirects[0] != "/old/7 /new/7" {
+		t.Errorf("unexpected redirect result found=%v err=%v redirects=%v", found, err, redirects)
+	}
+
+	for _, test := range []struct {
+		pattern, target string
+	}{
+		{"/old/:id", "/new/:name"},
+		{"/old/:", "/new"},
+		{"/old/:id", "/new/:"},
+		{"/old/:id", "/other/:id"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("redirect %s to %s: expected panic", test.pattern, test.target)
+				}
+			}()
+			router.AddRedirect(test.pattern, test.target)
+		}()
+	}
+}
+
+func TestRouterAddRedirectSeparator(t *testing.T) {
+	router := NewWithConfig(RouterConfig[struct{}]{Separator: '.'})
+	var served string
+	router.AddHandler("metrics.:host.*rest", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		served = reqPath + " " + p.ByName("host") + " " + p.ByName("rest")
+		return true, nil
+	})
+	router.AddRedirect("stats.*rest", "metrics.legacy.*rest")
+
+	if found, err := router.Serve(context.Background(), "stats.cpu.a/b", struct{}{}); !found || err != nil {
+		t.Fatalf("expected found, got found=%v err=%v", found, err)
+	}
+	if want := "metrics.legacy.cpu.a/b legacy .cpu.a/b"; served != want {
+		t.Errorf("expected %q but got %q", want, served)
+	}
+}
+
+func TestRouterAddRewrite(t *testing.T) {
+	var served []string
+	conf := DefaultConfig[struct{}]()
+	conf.RedirectHandler = func(ctx context.Context, fromPath, toPath string, rw struct{}) (bool, error) {
+		t.Errorf("unexpected redirect from %s to %s", fromPath, toPath)
+		return false, nil
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/v2/users/:id", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		served = append(served, reqPath+" "+p.ByName("id"))
+		return true, nil
+	})
+	router.AddRewrite("/v1/users/:id", "/v2/users/:id")
+	router.AddRewrite("/users/:id", "/v1/users/:id")
+	router.AddRewrite("/loop/:id", "/loop/:id")
+
+	ctx := context.Background()
+	for _, path := range []string{"/v1/users/1", "/users/2"} {
+		if found, err := router.Serve(ctx, path, struct{}{}); !found || err != nil {
+			t.Fatalf("path %s: expected found, got found=%v err=%v", path, found, err)
+		}
+	}
+	if want := []string{"/v2/users/1 1", "/v2/users/2 2"}; len(served) != 2 || served[0] != want[0] || served[1] != want[1] {
+		t.Errorf("expected %v but got %v", want, served)
+	}
+
+	res := router.Lookup("/users/3")
+	if !res.Found() || res.Pattern != "/v2/users/:id" || res.Params.ByName("id") != "3" || res.RedirectPath != "" {
+		t.Errorf("unexpected lookup result %+v", res)
+	}
+
+	if _, err := router.Serve(ctx, "/loop/1", struct{}{}); !errors.Is(err, ErrRedirectLoop) {
+		t.Errorf("expected redirect loop error, got %v", err)
+	}
+	if res := router.Lookup("/loop/1"); res.Found() {
+		t.Errorf("expected rewrite loop not to be found, got %+v", res)
+	}
+}
+
+func TestRouterRedirectUnescapeOnce(t *testing.T) {
+	var got []string
+	handle := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		got = append(got, p.ByName("x"))
+		return true, nil
+	}
+
+	conf := DefaultConfig[struct{}]()
+	conf.UnescapePath = true
+	// EncodedSlashKeep keeps %25 encoded
+	conf.EncodedSlash = EncodedSlashDecode
+	router := NewWithConfig(conf)
+	router.AddHandler("/new/:x", handle)
+	router.AddRewrite("/old/:x", "/new/:x")
+	router.AddRedirect("/moved/:x", "/new/:x")
+
+	for _, path := range []string{"/new/a%2525b", "/old/a%2525b", "/moved/a%2525b"} {
+		got = nil
+		if found, err := router.Serve(context.Background(), path, struct{}{}); !found || err != nil {
+			t.Fatalf("path %s: expected found, got found=%v err=%v", path, found, err)
+		}
+		if len(got) != 1 || got[0] != "a%25b" {
+			t.Errorf("path %s: expected param a%%25b decoded once, got %v", path, got)
+		}
+	}
+	if res := router.Lookup("/old/a%2525b"); res.Params.ByName("x") != "a%25b" {
+		t.Errorf("expected the rewritten lookup to decode the param once, got %v", res.Params)
+	}
+}
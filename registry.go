@@ -0,0 +1,222 @@
+package pathrouter
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HandlerRegistry maps handler names to handles.
+//
+// Used to build routers from declarative route entries, for example loaded
+// from a config file, see NewWithEntries. Safe to use concurrently.
+type HandlerRegistry[W any] struct {
+	mtx     sync.RWMutex
+	handles map[string]Handle[W]
+}
+
+// NewHandlerRegistry constructs a new empty HandlerRegistry.
+func NewHandlerRegistry[W any]() *HandlerRegistry[W] {
+	return &HandlerRegistry[W]{handles: make(map[string]Handle[W])}
+}
+
+// Register registers a handle with a name.
+// Panics if the name is empty, the handle is nil or the name is taken.
+func (r *HandlerRegistry[W]) Register(name string, handle Handle[W]) {
+	if name == "" {
+		panic("handler name must not be empty")
+	}
+	if handle == nil {
+		panic("nil handle for handler '" + name + "'")
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, exists := r.handles[name]; exists {
+		panic("a handle is already registered for handler '" + name + "'")
+	}
+	r.handles[name] = handle
+}
+
+// Lookup returns the handle registered with the name or nil if not found.
+//
+// Can be passed as the resolve function to LoadRoutes.
+func (r *HandlerRegistry[W]) Lookup(name string) Handle[W] {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.handles[name]
+}
+
+// Names returns the registered handler names in sorted order.
+func (r *HandlerRegistry[W]) Names() []string {
+	r.mtx.RLock()
+	names := make([]string, 0, len(r.handles))
+	for name := range r.handles {
+		names = append(names, name)
+	}
+	r.mtx.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// RouteEntry is a declarative route definition referring to a handler by name.
+//
+// An entry with Alias set registers an alias of the route with the canonical
+// pattern, see AddAlias, and must only have the pattern and the locale set. An
+// entry with Redirect set registers a redirect or rewrite route, see
+// AddRedirect, and needs no handler.
+type RouteEntry struct {
+	// Pattern is the route pattern.
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// Handler is the handler name.
+	Handler string `json:"handler,omitempty" yaml:"handler,omitempty"`
+	// Metadata contains the route metadata, if any.
+	Metadata map[string]any `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// Tags contains the route tags, if any, see WithTags.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Defaults contains the default param values by name, if any, see
+	// WithDefault.
+	Defaults map[string]string `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	// Timeout is the handle timeout in the format of time.ParseDuration, if
+	// any, see WithTimeout.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Alias is the canonical pattern if the route is an alias, see AddAlias.
+	Alias string `json:"alias,omitempty" yaml:"alias,omitempty"`
+	// Locale is the locale of a localized alias, if any, see AddLocaleAlias.
+	Locale string `json:"locale,omitempty" yaml:"locale,omitempty"`
+	// Redirect is the target template if the route is a redirect, see
+	// AddRedirect.
+	Redirect string `json:"redirect,omitempty" yaml:"redirect,omitempty"`
+	// Rewrite indicates the redirect is a rewrite, see AddRewrite.
+	Rewrite bool `json:"rewrite,omitempty" yaml:"rewrite,omitempty"`
+}
+
+// NewWithEntries constructs a new Router with the given config and the routes
+// from the entries, looking up the handles in the registry.
+func NewWithEntries[W any](conf RouterConfig[W], registry *HandlerRegistry[W], entries []RouteEntry) (*Router[W], error) {
+	router := NewWithConfig(conf)
+	if err := router.AddEntries(entries, registry.Lookup); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+// AddEntries registers the routes from the entries.
+//
+// The handles are looked up by handler name with resolve, which returns nil if
+// the name is unknown. If any of the routes are invalid, conflict or cannot be
+// resolved an error is returned and none of the routes are registered.
+func (r *Router[W]) AddEntries(entries []RouteEntry, resolve func(name string) Handle[W]) error {
+	rts := make([]*route[W], 0, len(entries))
+	for _, entry := range entries {
+		rt, err := r.entryRoute(entry, resolve)
+		if err != nil {
+			return err
+		}
+		rts = append(rts, rt)
+	}
+
+	return r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		for _, rt := range rts {
+			if err := r.tryAddEntryRoute(t, rt); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	})
+}
+
+// entryRoute constructs the route for the entry, see AddEntries.
+// Aliases are resolved when inserted, see tryAddEntryRoute.
+func (r *Router[W]) entryRoute(entry RouteEntry, resolve func(name string) Handle[W]) (*route[W], error) {
+	if entry.Alias != "" {
+		if entry.Handler != "" || len(entry.Metadata) != 0 || len(entry.Tags) != 0 || len(entry.Defaults) != 0 ||
+			entry.Timeout != "" || entry.Redirect != "" || entry.Rewrite {
+			return nil, errors.Errorf("route '%s': alias entry must only have a pattern and a locale", entry.Pattern)
+		}
+		alias, err := newRoute[W](r.conf.UnicodeForm.Normalize(entry.Pattern), nil, r.conf.Separator)
+		if err != nil {
+			return nil, err
+		}
+		alias.canonical, alias.locale = entry.Alias, entry.Locale
+		return alias, nil
+	}
+	if entry.Locale != "" {
+		return nil, errors.Errorf("route '%s': locale without alias", entry.Pattern)
+	}
+	if entry.Rewrite && entry.Redirect == "" {
+		return nil, errors.Errorf("route '%s': rewrite without redirect", entry.Pattern)
+	}
+
+	def := RouteDef[W]{
+		Pattern:  entry.Pattern,
+		Name:     entry.Handler,
+		Metadata: entry.Metadata,
+		Tags:     entry.Tags,
+		Defaults: entry.Defaults,
+	}
+	if entry.Timeout != "" {
+		timeout, err := time.ParseDuration(entry.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "route '%s': timeout", entry.Pattern)
+		}
+		def.Timeout = timeout
+	}
+	if entry.Redirect == "" {
+		def.Handle = resolve(entry.Handler)
+		if def.Handle == nil {
+			return nil, errors.Errorf("route '%s': unknown handler '%s'", entry.Pattern, entry.Handler)
+		}
+		return newRouteFromDef(def, r.conf.UnicodeForm, r.conf.Separator)
+	}
+
+	redirect, err := r.redirectRoute(entry.Pattern, entry.Redirect, entry.Rewrite)
+	if err != nil {
+		return nil, err
+	}
+	def.Handle = redirect.handle
+	rt, err := newRouteFromDef(def, r.conf.UnicodeForm, r.conf.Separator)
+	if err != nil {
+		return nil, err
+	}
+	rt.redirect, rt.rewrite = redirect.redirect, redirect.rewrite
+	return rt, nil
+}
+
+// tryAddEntryRoute inserts the route of an entry into the table, resolving
+// aliases with the canonical routes in the table, see entryRoute.
+func (r *Router[W]) tryAddEntryRoute(t *table[W], rt *route[W]) error {
+	if rt.canonical != "" {
+		return r.tryAddAlias(t, rt, rt.canonical)
+	}
+	return t.tryAddRoute(rt, r.conf.Fallthrough)
+}
+
+// routeEntry returns the entry of the route, see MarshalRoutes.
+// Returns an error if the route has no handler name and is not an alias or a
+// redirect.
+func (r *Router[W]) routeEntry(rt *route[W]) (RouteEntry, error) {
+	if rt.canonical != "" {
+		return RouteEntry{Pattern: rt.pattern, Alias: rt.canonical, Locale: rt.locale}, nil
+	}
+	if rt.name == "" && rt.redirect == "" {
+		return RouteEntry{}, errors.Errorf("route '%s' has no handler name", rt.pattern)
+	}
+	entry := RouteEntry{
+		Pattern:  rt.pattern,
+		Handler:  rt.name,
+		Metadata: rt.metadata,
+		Tags:     rt.tags,
+		Defaults: rt.defaults,
+	}
+	if rt.timeout > 0 {
+		entry.Timeout = rt.timeout.String()
+	}
+	if rt.redirect != "" {
+		entry.Redirect, entry.Rewrite = fromTree(rt.redirect, r.conf.Separator), rt.rewrite
+	}
+	return entry, nil
+}
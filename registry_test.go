@@ -0,0 +1,54 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestHandlerRegistry(t *testing.T) {
+	registry := NewHandlerRegistry[struct{}]()
+	registry.Register("user", fakeHandler("user"))
+	registry.Register("index", fakeHandler("index"))
+
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{"index", "user"}) {
+		t.Errorf("unexpected names: %v", names)
+	}
+	if registry.Lookup("unknown") != nil {
+		t.Error("expected nil handle for unknown name")
+	}
+	for _, fn := range []func(){
+		func() { registry.Register("user", fakeHandler("user")) },
+		func() { registry.Register("", fakeHandler("")) },
+		func() { registry.Register("nil", nil) },
+	} {
+		if recv := catchPanic(fn); recv == nil {
+			t.Error("expected panic")
+		}
+	}
+}
+
+func TestNewWithEntries(t *testing.T) {
+	registry := NewHandlerRegistry[struct{}]()
+	registry.Register("user", fakeHandler("user"))
+	registry.Register("index", fakeHandler("index"))
+
+	router, err := NewWithEntries(DefaultConfig[struct{}](), registry, []RouteEntry{
+		{Pattern: "/", Handler: "index"},
+		{Pattern: "/user/:name", Handler: "user", Metadata: map[string]any{"scope": "admin"}},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	fakeHandlerValue = ""
+	if found, _ := router.Serve(context.Background(), "/user/gopher", struct{}{}); !found || fakeHandlerValue != "user" {
+		t.Errorf("entry route not served: %v %q", found, fakeHandlerValue)
+	}
+
+	if _, err := NewWithEntries(DefaultConfig[struct{}](), registry, []RouteEntry{
+		{Pattern: "/", Handler: "index"},
+		{Pattern: "/missing", Handler: "missing"},
+	}); err == nil {
+		t.Error("expected error for unknown handler")
+	}
+}
@@ -0,0 +1,150 @@
+// Package reloadroute reloads the routes of a router when a route list file
+// changes.
+//
+// Watcher polls the file and on each change loads the route list, resolving
+// the handles in a registry, see pathrouter.Router.ReplaceFrom. If the list is
+// valid the route table of the router is swapped atomically with the loaded
+// routes, otherwise the router keeps serving the previous routes:
+//
+//	w := reloadroute.NewWatcher(router, reloadroute.Config[W]{
+//		Path:     "routes.txt",
+//		Registry: registry,
+//		OnError:  func(err error) { log.Printf("reload routes: %v", err) },
+//	})
+//	go w.Run(ctx)
+package reloadroute
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aperturerobotics/pathrouter"
+	"github.com/pkg/errors"
+)
+
+// DefaultInterval is the default interval between checks of the file.
+const DefaultInterval = time.Second
+
+// Config is the configuration for a Watcher.
+type Config[W any] struct {
+	// Path is the path to the route list file.
+	Path string
+	// Format is the format of the route list, see pathrouter.LoadFrom.
+	Format pathrouter.Format
+	// Registry contains the handles referred to by the route list.
+	Registry *pathrouter.HandlerRegistry[W]
+	// Interval is the interval between checks of the file.
+	// Defaults to DefaultInterval if zero.
+	Interval time.Duration
+
+	// OnReload is called with the route table version after the routes were
+	// reloaded, if set.
+	OnReload func(version uint64)
+	// OnError is called by Run with the error if the routes could not be
+	// reloaded, if set. The router keeps the previous routes.
+	OnError func(err error)
+}
+
+// Watcher reloads the routes of a router when the route list file changes.
+//
+// The file is checked by modification time and size. The routes of the router
+// are replaced by the routes in the file on each reload: the router should
+// not contain routes registered by other means.
+type Watcher[W any] struct {
+	conf   Config[W]
+	router *pathrouter.Router[W]
+
+	// mtx serializes the reloads
+	mtx sync.Mutex
+	// modTime and size identify the last loaded version of the file
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher constructs a new Watcher reloading the routes of the router.
+func NewWatcher[W any](router *pathrouter.Router[W], conf Config[W]) *Watcher[W] {
+	if conf.Interval <= 0 {
+		conf.Interval = DefaultInterval
+	}
+	return &Watcher[W]{conf: conf, router: router}
+}
+
+// Run loads the routes and reloads them when the file changes until the
+// context is canceled.
+//
+// Returns the error if the initial load fails. Later failures are passed to
+// OnError and the file is checked again after the interval.
+func (w *Watcher[W]) Run(ctx context.Context) error {
+	if err := w.Reload(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.conf.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-ticker.C:
+		}
+		if _, err := w.Check(); err != nil && w.conf.OnError != nil {
+			w.conf.OnError(err)
+		}
+	}
+}
+
+// Check reloads the routes if the file changed since the last reload.
+// Returns if the routes were reloaded.
+func (w *Watcher[W]) Check() (bool, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	info, err := os.Stat(w.conf.Path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false, nil
+	}
+	if err := w.reload(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Reload loads the routes from the file and swaps them into the router.
+//
+// OnReload is called on success. The router is not changed if an error is
+// returned.
+func (w *Watcher[W]) Reload() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.reload()
+}
+
+// reload loads the routes from the file, see Reload.
+// Expects the mutex to be locked.
+func (w *Watcher[W]) reload() error {
+	f, err := os.Open(w.conf.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	// an invalid version of the file is not loaded again by Check
+	w.modTime, w.size = info.ModTime(), info.Size()
+
+	version, err := w.router.ReplaceFrom(f, w.conf.Format, w.conf.Registry)
+	if err != nil {
+		return errors.Wrap(err, w.conf.Path)
+	}
+	if w.conf.OnReload != nil {
+		w.conf.OnReload(version)
+	}
+	return nil
+}
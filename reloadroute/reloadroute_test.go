@@ -0,0 +1,146 @@
+package reloadroute
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+func testHandle(ctx context.Context, reqPath string, p pathrouter.Params, rw struct{}) (bool, error) {
+	return true, nil
+}
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.txt")
+	write := func(list string, mod time.Time) {
+		if err := os.WriteFile(path, []byte(list), 0o644); err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	registry := pathrouter.NewHandlerRegistry[struct{}]()
+	registry.Register("user", testHandle)
+	registry.Register("files", testHandle)
+
+	var reloads []uint64
+	router := pathrouter.New[struct{}]()
+	w := NewWatcher(router, Config[struct{}]{
+		Path:     path,
+		Registry: registry,
+		OnReload: func(version uint64) { reloads = append(reloads, version) },
+	})
+
+	if err := w.Reload(); err == nil {
+		t.Fatal("expected error loading a missing file")
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write("/user/:name user\n", start)
+	if err := w.Reload(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !router.HasRoute("/user/:name") || len(reloads) != 1 || reloads[0] != router.Version() {
+		t.Fatalf("expected routes to be loaded, got reloads %v", reloads)
+	}
+	if changed, err := w.Check(); changed || err != nil {
+		t.Fatalf("expected no reload of an unchanged file, got %v %v", changed, err)
+	}
+
+	write("/files/*filepath files\n/about user\n", start.Add(time.Minute))
+	if changed, err := w.Check(); !changed || err != nil {
+		t.Fatalf("expected reload of a changed file, got %v %v", changed, err)
+	}
+	if router.HasRoute("/user/:name") || !router.HasRoute("/files/*filepath") {
+		t.Fatalf("expected routes to be replaced, got %v", router.RoutesWithPrefix("/"))
+	}
+	if routes := router.Routes(); len(routes) != 2 || routes[0].Pattern != "/files/*filepath" || routes[1].Pattern != "/about" {
+		t.Fatalf("expected the routes in file order, got %v", routes)
+	}
+
+	// an invalid list keeps the previous routes and is reported once
+	write("/user/:name unknown\n", start.Add(2*time.Minute))
+	if changed, err := w.Check(); changed || err == nil {
+		t.Fatalf("expected error reloading an invalid file, got %v %v", changed, err)
+	}
+	if changed, err := w.Check(); changed || err != nil {
+		t.Fatalf("expected the invalid file not to be loaded again, got %v %v", changed, err)
+	}
+	if !router.HasRoute("/files/*filepath") || len(reloads) != 2 {
+		t.Fatalf("expected the previous routes to be kept, got %v", router.RoutesWithPrefix("/"))
+	}
+
+	// Run reloads the file on change
+	ctx, cancel := context.WithCancel(context.Background())
+	reloaded := make(chan uint64, 1)
+	w = NewWatcher(router, Config[struct{}]{
+		Path:     path,
+		Registry: registry,
+		Interval: time.Millisecond,
+		OnReload: func(version uint64) {
+			select {
+			case reloaded <- version:
+			default:
+			}
+		},
+	})
+	if err := w.Run(ctx); err == nil {
+		t.Fatal("expected Run to return the initial load error")
+	}
+	write("/user/:name user\n", start.Add(3*time.Minute))
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	<-reloaded
+
+	write("/files/*filepath files\n", start.Add(4*time.Minute))
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to reload the changed file")
+	}
+	if !router.HasRoute("/files/*filepath") {
+		t.Errorf("expected routes to be reloaded, got %v", router.RoutesWithPrefix("/"))
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected Run to return the context error, got %v", err)
+	}
+}
+
+func TestWatcherAliases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	list := `{"routes": [
+		{"pattern": "/user/:name", "handler": "user"},
+		{"pattern": "/u/:name", "alias": "/user/:name"},
+		{"pattern": "/people/:name", "redirect": "/user/:name"}
+	]}`
+	if err := os.WriteFile(path, []byte(list), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	registry := pathrouter.NewHandlerRegistry[struct{}]()
+	registry.Register("user", testHandle)
+
+	router := pathrouter.New[struct{}]()
+	router.AddHandler("/old", testHandle)
+	w := NewWatcher(router, Config[struct{}]{Path: path, Format: pathrouter.FormatJSON, Registry: registry})
+	if err := w.Reload(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if router.HasRoute("/old") {
+		t.Error("expected the previous routes to be replaced")
+	}
+	if res := router.Lookup("/u/gopher"); res.Pattern != "/user/:name" {
+		t.Errorf("expected the alias to be kept, got %+v", res)
+	}
+	if res := router.Lookup("/people/gopher"); res.RedirectPath != "/user/gopher" {
+		t.Errorf("expected the redirect to be kept, got %+v", res)
+	}
+	if delta := router.DeltaSince(1); len(delta.Removed) != 1 || delta.Removed[0] != "/old" {
+		t.Errorf("expected the previous route to be reported as removed, got %+v", delta)
+	}
+}
@@ -0,0 +1,63 @@
+package pathrouter
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// ReorderFromStats orders the children of the tree nodes by the traffic of
+// the routes below them so the hottest branches are checked first, for
+// example with the RouteStats of a router serving production traffic.
+//
+// The weight of a route is the sum of its Matches and Lookups. The priority of
+// each node is set to the weight of the routes below it plus the number of
+// routes below it, so routes without traffic keep their relative order. Routes
+// missing from the stats have no traffic. Aliases use the stats of the
+// canonical route.
+//
+// The order is kept by Compile and Optimize: reorder the router before
+// compiling it. Routes added afterwards are inserted by route count. Does
+// nothing if the router is compiled.
+func (r *Router[W]) ReorderFromStats(stats []RouteStat) {
+	weights := make(map[string]uint64, len(stats))
+	for _, stat := range stats {
+		weights[stat.Pattern] += stat.Matches + stat.Lookups
+	}
+	weight := func(rt *route[W]) uint64 {
+		return weights[rt.matchedPattern()]
+	}
+
+	err := r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		for _, tree := range t.trees {
+			tree.reorder(weight)
+		}
+		return t, nil
+	})
+	if err != nil && !errors.Is(err, ErrCompiled) {
+		panic(err.Error())
+	}
+}
+
+// reorder sets the priority of the node and its children to the weight of
+// the routes below them plus the number of routes and orders the children by
+// priority. Returns the priority before saturating it.
+func (n *node[W]) reorder(weight func(rt *route[W]) uint64) uint64 {
+	var total uint64
+	if n.handle != nil {
+		total++
+		if n.route != nil {
+			total += weight(n.route)
+		}
+	}
+	for _, child := range n.children {
+		total += child.reorder(weight)
+	}
+	n.priority = math.MaxUint32
+	if total < math.MaxUint32 {
+		n.priority = uint32(total)
+	}
+	n.sortChildren()
+	return total
+}
@@ -0,0 +1,52 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRouterReorderFromStats(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.RouteStats = true
+	router := NewWithConfig(conf)
+	routes := []string{"/a/1", "/a/2", "/a/3", "/b/:id", "/c/*rest"}
+	for _, route := range routes {
+		router.AddHandler(route, fakeHandler(route))
+	}
+	indices := func(r *Router[struct{}]) string {
+		return r.load().trees[0].indices
+	}
+	if got := indices(router); got != "abc" {
+		t.Fatalf("expected children ordered by route count, got %q", got)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		_, _ = router.Serve(ctx, "/c/x", struct{}{})
+	}
+	for i := 0; i < 3; i++ {
+		_ = router.Lookup("/b/1")
+	}
+	router.ReorderFromStats(router.RouteStats())
+	if got := indices(router); got != "cba" {
+		t.Errorf("expected children ordered by traffic, got %q", got)
+	}
+	if got := indices(router.Compile()); got != "cba" {
+		t.Errorf("expected Compile to keep the order, got %q", got)
+	}
+
+	// the routes are unchanged and routes can still be added
+	router.AddHandler("/d", fakeHandler("/d"))
+	for _, path := range []string{"/a/1", "/a/3", "/b/x", "/c/y/z", "/d"} {
+		fakeHandlerValue = ""
+		if found, _ := router.Serve(ctx, path, struct{}{}); !found || fakeHandlerValue == "" {
+			t.Errorf("%s: expected found", path)
+		}
+	}
+
+	// routes without traffic keep their order
+	router.ReorderFromStats(nil)
+	if got := indices(router); got[0] != 'a' {
+		t.Errorf("expected the branch with the most routes first, got %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package pathrouter
+
+import "context"
+
+// Exchange contains the request and the response writer of a request served by
+// a RequestRouter.
+type Exchange[R, W any] struct {
+	// Request is the request.
+	Request R
+	// Writer is the response writer.
+	Writer W
+}
+
+// RequestHandle is a handle registered with a RequestRouter.
+// Called with the request path, the typed request and the params.
+type RequestHandle[R, W any] func(ctx context.Context, reqPath string, req R, p Params, rw W) (bool, error)
+
+// RequestRouter dispatches typed requests with a path to the handles registered
+// with matching patterns, keeping the request separate from the response
+// writer.
+//
+// The underlying router serves an Exchange containing both: use it to set the
+// NotFound and ErrorHandler handles of the config and for lookups.
+//
+// Safe to use concurrently.
+type RequestRouter[R, W any] struct {
+	router *Router[Exchange[R, W]]
+}
+
+// NewRequestRouter constructs a new RequestRouter with the given config.
+func NewRequestRouter[R, W any](conf RouterConfig[Exchange[R, W]]) *RequestRouter[R, W] {
+	return &RequestRouter[R, W]{router: NewWithConfig(conf)}
+}
+
+// Router returns the underlying router.
+func (r *RequestRouter[R, W]) Router() *Router[Exchange[R, W]] {
+	return r.router
+}
+
+// AddHandler registers a new request handle with the given pattern.
+//
+// Panics if the pattern is invalid or conflicts with a registered pattern.
+func (r *RequestRouter[R, W]) AddHandler(pattern string, handle RequestHandle[R, W], opts ...RouteOption) {
+	if handle == nil {
+		return
+	}
+	r.router.AddHandler(pattern, func(ctx context.Context, reqPath string, p Params, ex Exchange[R, W]) (bool, error) {
+		return handle(ctx, reqPath, ex.Request, p, ex.Writer)
+	}, opts...)
+}
+
+// Serve serves the request with the path with the router.
+// Returns if the request was handled and any error.
+func (r *RequestRouter[R, W]) Serve(ctx context.Context, reqPath string, req R, rw W) (bool, error) {
+	return r.router.Serve(ctx, reqPath, Exchange[R, W]{Request: req, Writer: rw})
+}
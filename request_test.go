@@ -0,0 +1,42 @@
+package pathrouter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type testMessage struct {
+	Subject string
+	Body    string
+}
+
+func TestRequestRouter(t *testing.T) {
+	var notFound []string
+	router := NewRequestRouter(RouterConfig[Exchange[*testMessage, *strings.Builder]]{
+		NotFound: func(ctx context.Context, reqPath string, p Params, ex Exchange[*testMessage, *strings.Builder]) (bool, error) {
+			notFound = append(notFound, ex.Request.Body)
+			return false, nil
+		},
+	})
+	router.AddHandler("/greet/:name", func(ctx context.Context, reqPath string, msg *testMessage, p Params, out *strings.Builder) (bool, error) {
+		out.WriteString(msg.Body + " " + p.ByName("name"))
+		return true, nil
+	})
+
+	var out strings.Builder
+	msg := &testMessage{Subject: "/greet/gopher", Body: "hello"}
+	if found, err := router.Serve(context.Background(), msg.Subject, msg, &out); !found || err != nil {
+		t.Fatalf("expected found, got found=%v err=%v", found, err)
+	}
+	if out.String() != "hello gopher" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	if found, _ := router.Serve(context.Background(), "/missing", &testMessage{Body: "lost"}, &out); found || len(notFound) != 1 || notFound[0] != "lost" {
+		t.Errorf("expected not found handler with the request, got %v", notFound)
+	}
+	if res := router.Router().Lookup("/greet/x"); !res.Found() {
+		t.Error("expected lookup in the underlying router to find the route")
+	}
+}
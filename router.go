@@ -39,6 +39,26 @@
 //	 /files/templates/article.html       match: filepath="/templates/article.html"
 //	 /files                              no match, but the router would redirect
 //
+// A path ending with a slash can be anchored with the {$} end marker. Anchored
+// paths only match the exact path and are never the target of a trailing
+// slash or case-insensitive correction:
+//
+//	Path: /dir/{$}
+//
+//	Requests:
+//	 /dir/                               match
+//	 /dir                                no match
+//	 /DIR/                               no match
+//
+// The wildcards can also be written in the net/http ServeMux syntax, {name} for
+// named parameters and {name...} for catch-all parameters, which are converted
+// to :name and *name. As with ServeMux the wildcards must be full path
+// segments. Unlike ServeMux, the catch-all value includes the leading slash and
+// a path with a trailing slash does not match the paths below it.
+//
+// The parameter names of a path must be unique: a path such as /a/:id/b/:id
+// is rejected.
+//
 // The value of parameters is saved as a slice of the Param struct, consisting
 // each of a key and a value. The slice is passed to the Handle func as a third
 // parameter.
@@ -54,12 +74,27 @@ package pathrouter
 
 import (
 	"context"
+	"log/slog"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// anchorSuffix is the end-of-path marker for anchored paths.
+const anchorSuffix = "{$}"
+
+// DefaultMaxCorrections is the default value for RouterConfig.MaxCorrections.
+const DefaultMaxCorrections = 4
+
 // Handle is a function that can be registered to a route to handle requests.
 // Returns if the request was handled and any error.
 // If false, nil is returned, assumes the function has returned "not found."
+//
+// The Params are only valid until the handle returns: the backing slice is
+// pooled and reused for other requests, unless RouterConfig.ParamsAllocator is
+// set to UnpooledParams. Use Params.Clone or Params.Map to retain the values
+// after the handle returns.
 type Handle[W any] func(ctx context.Context, reqPath string, p Params, rw W) (bool, error)
 
 // Param is a single URL parameter, consisting of a key and a value.
@@ -90,8 +125,14 @@ type RouterConfig[W any] struct {
 	// handler for the path with (without) the trailing slash exists.
 	// For example if /foo/ is requested but a route only exists for /foo, the
 	// client is redirected to /foo
+	// Ignored if TrailingSlash is set.
 	RedirectTrailingSlash bool
 
+	// TrailingSlash is the trailing slash policy. If TrailingSlashDefault, the
+	// policy is TrailingSlashRedirect if RedirectTrailingSlash is set or
+	// TrailingSlashStrict otherwise.
+	TrailingSlash TrailingSlashPolicy
+
 	// RedirectFixedPath configures the router to fix the current request path, if no
 	// handle is registered for it.
 	// First superfluous path elements like ../ or // are removed.
@@ -102,13 +143,198 @@ type RouterConfig[W any] struct {
 	// RedirectTrailingSlash is independent of this option.
 	RedirectFixedPath bool
 
+	// UnescapePath configures the router to decode %XX escapes in the request
+	// path before lookup, so params contain the decoded values.
+	// Encoded slashes are handled according to EncodedSlash.
+	// An invalid escape is passed to the ErrorHandler, if set, or returned.
+	UnescapePath bool
+
+	// EncodedSlash is the policy for encoded slashes (%2F) if UnescapePath is
+	// set. Defaults to EncodedSlashKeep.
+	EncodedSlash EncodedSlashPolicy
+
+	// UnicodeForm is the Unicode normalization form applied to the patterns
+	// when registered and to the request paths before lookup, after
+	// UnescapePath, so visually identical paths match the same route.
+	// Defaults to UnicodeNone.
+	UnicodeForm UnicodeForm
+
+	// BeforeLookup is applied to the request path by Serve, ServeAll, Lookup
+	// and LookupAll before any other normalization, for example to strip a
+	// tenant prefix from the raw path. The limits apply to the path before
+	// BeforeLookup, see MaxPathLength.
+	BeforeLookup func(reqPath string) string
+
+	// AfterMatch is applied to the params of each route matched by Serve,
+	// ServeAll, Lookup and LookupAll before they are used, for example to
+	// lowercase slugs. The returned params are passed to the Limiter and the
+	// handle. The params can be modified in place and are only valid until the
+	// handle returns, see Params.Clone. The pattern is the matched pattern.
+	AfterMatch func(pattern string, ps Params) Params
+
+	// ParamsAllocator allocates and releases the params passed to the
+	// handles. If nil, the params are pooled with a sync.Pool and only valid
+	// until the handle returns. Set UnpooledParams to allocate new params for
+	// each request which can be retained, for example if pooling is
+	// undesirable on the platform.
+	ParamsAllocator ParamsAllocator
+
+	// PathNormalizer is applied to the request path before lookup by Serve,
+	// ServeAll and Lookup, after UnescapePath and UnicodeForm. The handler is called with the
+	// normalized path.
+	//
+	// For example set CleanPath to always serve the cleaned path instead of
+	// redirecting, or use a func to strip a prefix or decode the path.
+	// If nil the path is looked up as-is.
+	PathNormalizer func(reqPath string) string
+
+	// Separator is the path segment separator, for example '.' to route dot
+	// separated subjects like orders.created.:id. Patterns and paths with a
+	// separator other than '/' do not start with the separator and '/' is an
+	// ordinary character. Params, patterns and paths passed to the handlers
+	// use the separator. Defaults to '/' if zero.
+	//
+	// Use the {name} and {name...} wildcard syntax if the separator is ':' or
+	// '*'. DumpTree and ExportDOT use '/'.
+	Separator byte
+
+	// StaticFastPath configures the router to look up the request path in a
+	// map of the routes without params before walking the tree.
+	// Speeds up lookups of static routes at the cost of a map lookup for paths
+	// matching routes with params. Not used if Fallthrough is set and routes
+	// overlap.
+	StaticFastPath bool
+
+	// LookupCacheSize is the maximum number of request paths cached by Serve
+	// with the matched route and params, evicting the least recently used. Hot
+	// paths matching routes with params are served without walking the tree.
+	// The cache is cleared when the routes change. Not used if Fallthrough is
+	// set and routes overlap. If zero, disabled.
+	LookupCacheSize int
+
+	// NotFoundCacheSize is the maximum number of request paths not matching
+	// any route cached by Serve, evicting the least recently used. Cached
+	// paths skip the lookup and the path corrections and go straight to the
+	// NotFound handler, for example for floods of the same nonexistent paths.
+	// Paths only matching disabled routes and paths whose handles declined are
+	// not cached. The cache is cleared when the routes change. Not used if
+	// Fallthrough is set and routes overlap. If zero, disabled.
+	NotFoundCacheSize int
+
+	// ParamsInContext configures Serve to attach the Params to the context
+	// passed to the handler, see ParamsFromContext.
+	ParamsInContext bool
+
+	// PatternInContext configures Serve to attach the pattern of the matched
+	// route to the context passed to the handler, see PatternFromContext.
+	// Useful to aggregate metrics and logs by route instead of by path.
+	PatternInContext bool
+
+	// Observer is notified of the served requests and handler errors, for
+	// example to record metrics or traces by route pattern.
+	Observer Observer
+
+	// Logger logs each request at the debug level if set: the request path,
+	// the normalized path, the path corrections and the matched route with
+	// the params. Useful to diagnose why a path is not found.
+	Logger *slog.Logger
+
+	// Selector selects the handle for requests to routes registered with
+	// AddWeighted. Defaults to RandomSelector if nil.
+	Selector Selector
+
+	// RouteStats enables counting the matches, declined matches, handler
+	// errors and lookups of each route, see Router.RouteStats.
+	RouteStats bool
+
+	// Fallthrough allows registering overlapping routes, for example
+	// /user/new and /user/:name, or /files/*filepath and /files/index.html.
+	// If a handle returns false, nil the next matching route is tried.
+	//
+	// Matching routes are tried in precedence order: longer static prefixes
+	// first, then named params, then catch-all params. Routes with the same
+	// precedence are tried in the order they were registered.
+	Fallthrough bool
+
+	// RedirectHandler is called with the corrected path instead of serving
+	// the corrected path when a trailing slash or fixed path redirect is found.
+	// This allows the caller to issue a redirect, for example a HTTP 301.
+	// If nil, the corrected path is served directly.
+	RedirectHandler func(ctx context.Context, fromPath, toPath string, rw W) (bool, error)
+
+	// MaxCorrections is the maximum number of consecutive trailing slash and
+	// fixed path corrections applied to a path by Serve before giving up.
+	// If zero, DefaultMaxCorrections is used. If negative, corrected paths are
+	// not served, but are still passed to the RedirectHandler.
+	MaxCorrections int
+
 	// NotFound is called when no matching route is found.
 	NotFound Handle[W]
 
+	// ErrorHandler is called when a handle or the NotFound handler returns an
+	// error, or when the request path cannot be unescaped. The Params are the
+	// params of the matched route, if any. The result of the ErrorHandler is
+	// used as the result of the handle.
+	ErrorHandler func(ctx context.Context, reqPath string, p Params, rw W, err error) (bool, error)
+
+	// TimeoutHandler is called when a handle of a route with a timeout returns
+	// an error after the timeout expired, see
+	// WithTimeout. The Params are the params of the route. The result of the
+	// TimeoutHandler is used as the result of the handle.
+	TimeoutHandler Handle[W]
+
+	// Limiter is consulted after a route is matched and before calling the
+	// handle, for example to rate limit by pattern and tenant param.
+	// Rejected requests are not handled: the LimitedHandler is called, if set,
+	// or false and ErrLimited are returned.
+	Limiter Limiter
+
+	// LimitedHandler is called for requests rejected by the Limiter with the
+	// params of the matched route. The result of the LimitedHandler is used as
+	// the result of the handle.
+	LimitedHandler Handle[W]
+
+	// CheckCanceled configures Serve and ServeAll to check if the context is
+	// done before looking up the path, before serving a corrected path and
+	// before calling each handle. Canceled requests are not looked up or
+	// handled: the CanceledHandler is called, if set, or false and the
+	// context error is returned.
+	CheckCanceled bool
+
+	// CanceledHandler is called with the context error for canceled requests
+	// if CheckCanceled is set. The result of the CanceledHandler is used as the
+	// result of the request.
+	CanceledHandler func(ctx context.Context, reqPath string, rw W, err error) (bool, error)
+
+	// MaxPathLength is the maximum length in bytes of a request path before
+	// normalization. Longer paths are rejected before any lookup: the
+	// RejectedHandler is called, if set, or false and ErrPathRejected are
+	// returned. Lookups of longer paths find no route. If zero, not limited.
+	MaxPathLength int
+
+	// MaxSegments is the maximum number of segments of a request path before
+	// normalization, for example 2 for /a/b. Paths with more segments are
+	// rejected as with MaxPathLength. If zero, not limited.
+	MaxSegments int
+
+	// RejectedHandler is called for requests rejected by MaxPathLength or
+	// MaxSegments with an error wrapping ErrPathRejected. The result of the
+	// RejectedHandler is used as the result of the request.
+	RejectedHandler func(ctx context.Context, reqPath string, rw W, err error) (bool, error)
+
 	// Function to handle panics recovered from handlers.
 	// If nil, no recover() will be called (panics will throw).
 	// The fourth parameter is the error from recover().
 	PanicHandler func(ctx context.Context, reqPath string, rw W, panicErr interface{})
+
+	// PanicErrorHandler is called instead of the PanicHandler with the
+	// recovered panic, the params of the matched route and the stack trace.
+	// The result of the PanicErrorHandler is used as the result of Serve.
+	PanicErrorHandler func(ctx context.Context, rw W, err *PanicError) (bool, error)
+
+	// PanicErrors configures Serve to recover panics and return them as a
+	// *PanicError if neither the PanicHandler nor the PanicErrorHandler is set.
+	PanicErrors bool
 }
 
 // Router can be used to dispatch URL requests to different handler functions
@@ -116,11 +342,105 @@ type RouterConfig[W any] struct {
 //
 // R is the request type.
 // W is the response writer type.
+//
+// The Router is safe to use concurrently: routes can be added while other
+// goroutines are serving requests. Each request is served with a consistent
+// snapshot of the route table.
 type Router[W any] struct {
 	conf       RouterConfig[W]
-	tree       *node[W]
 	paramsPool sync.Pool
-	maxParams  uint16
+	maxParams  atomic.Uint32
+
+	// mtx serializes changes to the route table.
+	mtx sync.Mutex
+	// tbl is the current route table.
+	tbl atomic.Pointer[table[W]]
+	// fallback is the router to try before calling NotFound, if any.
+	fallback atomic.Pointer[Router[W]]
+	// compiled indicates the router is read-only, see Compile.
+	compiled atomic.Bool
+	// anyDisabled indicates a route was disabled, see SetRouteEnabled.
+	// Shared with the clones which share the routes, see Clone.
+	anyDisabled *atomic.Bool
+	// hooks contains the route change hooks, if any, see OnRouteAdded.
+	hooks atomic.Pointer[routeHooks[W]]
+	// fallthroughMisses counts the requests not found after the matched
+	// routes declined, see FallthroughMisses.
+	fallthroughMisses atomic.Uint64
+}
+
+// route is a route registered with the router.
+type route[W any] struct {
+	// pattern is the pattern with a leading slash and the end marker.
+	pattern string
+	// path is the path inserted into the tree.
+	path string
+	// anchored indicates the pattern had the {$} end marker.
+	anchored bool
+	// parts contains the parsed pattern.
+	parts []patternPart
+	// handle is the registered handle.
+	handle Handle[W]
+	// handles contains the chain of handles if registered with AppendHandler.
+	handles []Handle[W]
+	// name is the handler name, see WithName.
+	name string
+	// metadata contains the route metadata, see WithMetadata.
+	metadata map[string]any
+	// version is the router version the route was registered at.
+	version uint64
+	// counters contains the route statistics, see RouterConfig.RouteStats.
+	// Shared with copies of the route. Nil unless RouteStats is set, see
+	// allocCounters.
+	counters *routeCounters
+	// disabled indicates the route is disabled, see SetRouteEnabled.
+	// Shared with copies of the route.
+	disabled *atomic.Bool
+	// canonical is the pattern of the route an alias resolves to, see AddAlias.
+	canonical string
+	// canonicalRoute is the route an alias was resolved with, see syncAliases.
+	canonicalRoute *route[W]
+	// locale is the locale of a localized alias, see AddLocaleAlias.
+	locale string
+	// redirect is the target template of a redirect route in the form used by
+	// the trees, see AddRedirect.
+	redirect string
+	// rewrite indicates the redirect is a rewrite, see AddRewrite.
+	rewrite bool
+	// timeout is the timeout of the handle, see WithTimeout.
+	timeout time.Duration
+	// tags contains the route tags, see WithTags.
+	tags []string
+	// defaults contains the default param values by name, see WithDefault.
+	defaults map[string]string
+}
+
+// matchedPattern returns the pattern reported for paths matched by the route:
+// the canonical pattern for aliases.
+func (rt *route[W]) matchedPattern() string {
+	if rt.canonical != "" {
+		return rt.canonical
+	}
+	return rt.pattern
+}
+
+// newRoute parses the pattern and constructs a new route.
+func newRoute[W any](pattern string, handle Handle[W], sep byte) (*route[W], error) {
+	path, anchored, err := cleanPattern(toTree(pattern, sep))
+	if err != nil {
+		return nil, err
+	}
+	parts, _, err := parsePattern(path)
+	if err != nil {
+		return nil, err
+	}
+	rt := &route[W]{path: path, anchored: anchored, parts: parts, handle: handle, disabled: new(atomic.Bool)}
+	rt.pattern = path
+	if anchored {
+		rt.pattern += anchorSuffix
+	}
+	rt.pattern = fromTree(rt.pattern, sep)
+	return rt, nil
 }
 
 // DefaultConfig returns the default configuration if none is specified.
@@ -142,67 +462,86 @@ func New[W any]() *Router[W] {
 //
 // All configuration values are defaulted to false.
 func NewWithConfig[W any](conf RouterConfig[W]) *Router[W] {
-	return &Router[W]{
-		conf: conf,
+	r := &Router[W]{conf: conf, anyDisabled: new(atomic.Bool)}
+	r.paramsPool.New = func() interface{} {
+		ps := make(Params, 0, r.maxParams.Load())
+		return &ps
 	}
+	return r
 }
 
-func (r *Router[W]) getParams() *Params {
-	ps, _ := r.paramsPool.Get().(*Params)
-	*ps = (*ps)[0:0] // reset slice
-	return ps
-}
-
-func (r *Router[W]) putParams(ps *Params) {
-	if ps != nil {
-		r.paramsPool.Put(ps)
-	}
+// Clone returns a copy of the router with the same config and routes.
+//
+// The handles are shared. Routes added to either router afterwards do not
+// affect the other router. The enabled state of the routes registered before
+// the copy is shared: disabling one of them disables it in both routers, see
+// SetRouteEnabled. The fallback router, if any, is shared. The copy of a
+// compiled router is compiled, see Compile.
+func (r *Router[W]) Clone() *Router[W] {
+	out := NewWithConfig(r.conf)
+	r.mtx.Lock()
+	// tables are immutable once published and can be shared
+	out.tbl.Store(r.tbl.Load())
+	out.maxParams.Store(r.maxParams.Load())
+	r.mtx.Unlock()
+	out.anyDisabled = r.anyDisabled
+	out.fallback.Store(r.fallback.Load())
+	out.compiled.Store(r.compiled.Load())
+	return out
 }
 
 // AddHandler registers a new request handle with the given path.
-func (r *Router[W]) AddHandler(path string, handle Handle[W]) {
+//
+// If the path ends with the {$} marker the route only matches the exact path.
+//
+// Safe to call while serving requests. The route table is copied on each call:
+// use ApplyDelta to add many routes at once.
+func (r *Router[W]) AddHandler(path string, handle Handle[W], opts ...RouteOption) {
 	if handle == nil {
 		return
 	}
 
-	if len(path) == 0 {
-		path = "/"
-	} else if path[0] != '/' {
-		path = "/" + path
+	rt, err := newRoute(r.conf.UnicodeForm.Normalize(path), handle, r.conf.Separator)
+	if err != nil {
+		panic(err.Error())
 	}
-
-	var varsCount uint16
-	root := r.tree
-	if root == nil {
-		root = new(node[W])
-		r.tree = root
+	if err := applyRouteOptions(rt, opts); err != nil {
+		panic(err.Error())
 	}
 
-	root.addRoute(path, handle)
-
-	// Update maxParams
-	if paramsCount := countParams(path); paramsCount+varsCount > r.maxParams {
-		r.maxParams = paramsCount + varsCount
+	err = r.update(func(old *table[W]) (*table[W], error) {
+		t := old.clone()
+		t.addRoute(rt, r.conf.Fallthrough)
+		return t, nil
+	})
+	if err != nil {
+		panic(err.Error())
 	}
+}
 
-	// Lazy-init paramsPool alloc func
-	if r.paramsPool.New == nil && r.maxParams > 0 {
-		r.paramsPool.New = func() interface{} {
-			ps := make(Params, 0, r.maxParams)
-			return &ps
-		}
+// Routes returns the definitions of the registered routes in registration order.
+func (r *Router[W]) Routes() []RouteDef[W] {
+	t := r.load()
+	defs := make([]RouteDef[W], 0, len(t.routes))
+	for _, rt := range sortRoutes(t.routes) {
+		defs = append(defs, rt.routeDef())
 	}
+	return defs
 }
 
-// recoverPanic recovers from a panic while processing a path.
-func (r *Router[W]) recoverPanic(ctx context.Context, reqPath string, wr W) {
-	defer func() {
-		// if the panic handler panics, just give up :)
-		_ = recover()
-	}()
-	if rcv := recover(); rcv != nil && r.conf.PanicHandler != nil {
-		r.conf.PanicHandler(ctx, reqPath, wr, rcv)
+// Walk calls fn with the definition of each registered route in registration
+// order. Stops and returns the error if fn returns an error.
+//
+// The routes are walked in the snapshot of the route table at the time of the
+// call: fn may register and remove routes. The metadata maps are shared with
+// the routes and must not be modified.
+func (r *Router[W]) Walk(fn func(def RouteDef[W]) error) error {
+	for _, rt := range sortRoutes(r.load().routes) {
+		if err := fn(rt.routeDef()); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // LookupPath allows the manual lookup of a handler with a path.
@@ -210,72 +549,489 @@ func (r *Router[W]) recoverPanic(ctx context.Context, reqPath string, wr W) {
 // If the path was found, it returns the handle function and the path parameter
 // values. Otherwise the third return value indicates whether a redirection to
 // the same path with an extra / without the trailing slash should be performed.
+//
+// If overlapping routes are registered returns the route with precedence.
 func (r *Router[W]) LookupPath(path string) (Handle[W], Params, bool) {
-	if root := r.tree; root != nil {
-		handle, ps, tsr := root.getValue(path, r.getParams)
-		if handle == nil {
+	if r.checkLimits(path) != nil {
+		return nil, nil, false
+	}
+	t := r.load()
+	path = toTree(path, r.conf.Separator)
+	switch len(t.trees) {
+	case 0:
+		return nil, nil, false
+	case 1:
+		if r.conf.StaticFastPath {
+			if rt := t.static[path]; rt != nil && rt.enabled() {
+				r.countLookup(rt)
+				return rt.handle, nil, false
+			}
+		}
+		leaf, ps, tsr := t.trees[0].getValue(path, r.getParams, nil)
+		if leaf != nil && !leaf.route.enabled() {
+			leaf, tsr = nil, false
+		}
+		if leaf == nil {
 			r.putParams(ps)
 			return nil, nil, tsr
 		}
+		r.countLookup(leaf.route)
 		if ps == nil {
-			return handle, nil, tsr
+			return leaf.handle, nil, tsr
 		}
-		return handle, *ps, tsr
+		paramsFromTree(*ps, r.conf.Separator)
+		return leaf.handle, *ps, tsr
 	}
-	return nil, nil, false
+
+	matches, tsr := r.matchAll(t, path)
+	if len(matches) == 0 {
+		return nil, nil, tsr
+	}
+	r.putMatches(matches[1:])
+	r.countLookup(matches[0].route)
+	if matches[0].ps == nil {
+		return matches[0].handle, nil, false
+	}
+	paramsFromTree(*matches[0].ps, r.conf.Separator)
+	return matches[0].handle, *matches[0].ps, false
 }
 
 // Serve serves a request with the router.
 // Returns if the request was handled and any error.
 // Note: if the error handler is set, may return true even if not found.
+//
+// Serve does not allocate with the default config if the routes do not overlap,
+// for static, param and catch-all routes, paths not found and trailing slash
+// corrections, as long as the handles do not. LookupPath does not allocate for
+// routes without params and paths not found. The guarantee is checked by the
+// tests. Options attaching values to the context, Fallthrough, a custom
+// Separator, UnescapePath, the hooks and the Observer can allocate.
 func (r *Router[W]) Serve(ctx context.Context, reqPath string, wr W) (bool, error) {
-	if r.conf.PanicHandler != nil {
-		defer r.recoverPanic(ctx, reqPath, wr)
+	if r.conf.Observer != nil {
+		return r.observe(reqPath, func(matched *string) (bool, error) {
+			return r.serve(ctx, reqPath, wr, matched)
+		})
 	}
+	return r.serve(ctx, reqPath, wr, nil)
+}
 
-	if reqPath == "" {
-		reqPath = "/"
+// serve serves a request with the router, see Serve.
+// Sets matched to the pattern of the route handling the request, if not nil.
+func (r *Router[W]) serve(ctx context.Context, reqPath string, wr W, matched *string) (found bool, err error) {
+	if r.recoverPanics() {
+		defer r.recoverPanic(ctx, reqPath, wr, &found, &err)
 	}
 
-	if root := r.tree; root != nil {
-		if handle, ps, tsr := root.getValue(reqPath, r.getParams); handle != nil {
-			var params Params
-			if ps != nil {
-				params = *ps
-				defer r.putParams(ps)
+	if err := r.ctxErr(ctx); err != nil {
+		return r.canceled(ctx, reqPath, wr, err)
+	}
+	if err := r.checkLimits(reqPath); err != nil {
+		return r.rejected(ctx, reqPath, wr, err)
+	}
+
+	inPath := reqPath
+	reqPath, err = r.normalizePath(reqPath)
+	if err != nil {
+		return r.pathError(ctx, reqPath, wr, err)
+	}
+	if reqPath != inPath && r.debugEnabled(ctx) {
+		r.logDebug(ctx, "normalized path", slog.String("path", inPath), slog.String("normalized", fromTree(reqPath, r.conf.Separator)))
+	}
+	return r.serveNormalized(ctx, reqPath, wr, matched)
+}
+
+// serveNormalized serves a request with a path in the form used by the trees,
+// see serve.
+func (r *Router[W]) serveNormalized(ctx context.Context, reqPath string, wr W, matched *string) (found bool, err error) {
+	maxCorrections := r.conf.MaxCorrections
+	if maxCorrections == 0 {
+		maxCorrections = DefaultMaxCorrections
+	}
+
+	t := r.load()
+	// declined indicates the matched routes declined the request
+	var toggled, disabledMatch, declined bool
+	var misses *lruCache[string, struct{}]
+	if len(t.trees) == 1 {
+		misses = r.missCache(t)
+	}
+	for corrections := 0; len(t.trees) != 0; corrections++ {
+		var tsr bool
+		if misses != nil && corrections == 0 {
+			if _, ok := misses.get(reqPath); ok {
+				break
 			}
-			found, handlerErr := handle(ctx, reqPath, params, wr)
-			if found || handlerErr != nil {
-				return found, handlerErr
+		}
+		if len(t.trees) == 1 {
+			if r.conf.StaticFastPath {
+				if rt := t.static[reqPath]; rt != nil && rt.enabled() {
+					found, handlerErr := r.callHandle(ctx, reqPath, match[W]{route: rt, handle: rt.handle}, wr, matched)
+					if found || handlerErr != nil {
+						return found, handlerErr
+					}
+					declined = true
+					break
+				}
 			}
-		} else if reqPath != "/" && reqPath != "" {
-			if tsr && r.conf.RedirectTrailingSlash {
-				if len(reqPath) > 1 && reqPath[len(reqPath)-1] == '/' {
-					reqPath = reqPath[:len(reqPath)-1]
-				} else {
-					reqPath = reqPath + "/"
+
+			cache := r.lookupCache(t)
+			if cache != nil {
+				if m, ok := r.cachedLookup(cache, reqPath); ok {
+					found, handlerErr := r.serveMatch(ctx, reqPath, m, wr, matched)
+					if found || handlerErr != nil {
+						return found, handlerErr
+					}
+					declined = true
+					break
 				}
-				return r.Serve(ctx, reqPath, wr)
 			}
 
-			// Try to fix the request path
-			if r.conf.RedirectFixedPath {
-				fixedPath, fixedFound := root.findCaseInsensitivePath(
-					CleanPath(reqPath),
-					r.conf.RedirectTrailingSlash,
-				)
-				if fixedFound {
-					reqPath = fixedPath
-					return r.Serve(ctx, reqPath, wr)
+			var leaf *node[W]
+			var ps *Params
+			leaf, ps, tsr = t.trees[0].getValue(reqPath, r.getParams, nil)
+			if leaf != nil && leaf.route.emptyTrailingDefault(hasTrailingSlash(reqPath)) {
+				leaf, tsr = nil, t.trailingSlashMatch(reqPath)
+			}
+			if leaf != nil && !leaf.route.enabled() {
+				leaf, disabledMatch = nil, true
+			}
+			if leaf != nil {
+				if cache != nil {
+					cacheMatch(cache, reqPath, leaf, ps)
 				}
+				found, handlerErr := r.serveMatch(ctx, reqPath, match[W]{route: leaf.route, handle: leaf.handle, ps: ps}, wr, matched)
+				if found || handlerErr != nil {
+					return found, handlerErr
+				}
+				declined = true
+				break
 			}
+			r.putParams(ps)
+		} else {
+			var matches []match[W]
+			matches, tsr = r.matchAll(t, reqPath)
+			if len(matches) != 0 {
+				found, handlerErr := r.serveMatches(ctx, reqPath, matches, wr, matched)
+				if found || handlerErr != nil {
+					return found, handlerErr
+				}
+				declined = true
+				break
+			}
+		}
+
+		if toPath, ok := r.matchBothPath(reqPath, tsr); ok && !toggled {
+			toggled, reqPath = true, toPath
+			continue
+		}
+		if err := r.ctxErr(ctx); err != nil {
+			return r.canceled(ctx, fromTree(reqPath, r.conf.Separator), wr, err)
+		}
+		toPath, corrected := r.correctPath(t, reqPath, tsr)
+		if !corrected {
+			// defaults only apply if the path cannot be corrected: /list/books/
+			// is redirected to /list/books rather than matching /list/:page
+			if _, m, ok := r.matchTrailingDefault(t, reqPath, r.getParams); ok {
+				found, handlerErr := r.serveMatch(ctx, reqPath, m, wr, matched)
+				if found || handlerErr != nil {
+					return found, handlerErr
+				}
+				declined = true
+				break
+			}
+			if misses != nil && corrections == 0 && !disabledMatch && r.pureMiss(t, reqPath, tsr) {
+				misses.add(strings.Clone(reqPath), struct{}{})
+			}
+			break
+		}
+		if r.debugEnabled(ctx) {
+			reason := "fixed path"
+			if tsr && r.trailingSlash() == TrailingSlashRedirect {
+				reason = "trailing slash"
+			}
+			r.logDebug(ctx, "corrected path",
+				slog.String("path", fromTree(reqPath, r.conf.Separator)),
+				slog.String("corrected", fromTree(toPath, r.conf.Separator)),
+				slog.String("reason", reason),
+				slog.Bool("redirect", r.conf.RedirectHandler != nil),
+			)
+		}
+		if r.conf.RedirectHandler != nil {
+			return r.conf.RedirectHandler(ctx, fromTree(reqPath, r.conf.Separator), fromTree(toPath, r.conf.Separator), wr)
 		}
+		if corrections >= maxCorrections {
+			break
+		}
+		reqPath = toPath
+	}
+
+	if declined && r.conf.RouteStats {
+		r.fallthroughMisses.Add(1)
 	}
+	return r.notFound(ctx, reqPath, wr)
+}
 
-	// not found
-	if r.conf.NotFound == nil {
-		return false, nil
+// normalizePath applies BeforeLookup, if set, unescapes the request path, if
+// enabled, applies the Unicode normalization form and the PathNormalizer, if
+// set, and converts the path to the form used by the trees.
+func (r *Router[W]) normalizePath(reqPath string) (string, error) {
+	if r.conf.BeforeLookup != nil {
+		reqPath = r.conf.BeforeLookup(reqPath)
+	}
+	if r.conf.UnescapePath {
+		unescaped, err := UnescapePath(reqPath, r.conf.EncodedSlash)
+		if err != nil {
+			return reqPath, err
+		}
+		reqPath = unescaped
 	}
+	reqPath = r.conf.UnicodeForm.Normalize(reqPath)
+	if r.conf.PathNormalizer != nil {
+		reqPath = r.conf.PathNormalizer(reqPath)
+	}
+	reqPath = toTree(reqPath, r.conf.Separator)
+	if reqPath == "" {
+		reqPath = "/"
+	}
+	return reqPath, nil
+}
 
-	return r.conf.NotFound(ctx, reqPath, nil, wr)
+// afterMatch applies AfterMatch, if set, to the params of the matched route.
+func (r *Router[W]) afterMatch(rt *route[W], ps Params) Params {
+	if r.conf.AfterMatch == nil {
+		return ps
+	}
+	var pattern string
+	if rt != nil {
+		pattern = rt.matchedPattern()
+	}
+	return r.conf.AfterMatch(pattern, ps)
+}
+
+// pathError handles a request path which could not be normalized.
+func (r *Router[W]) pathError(ctx context.Context, reqPath string, wr W, err error) (bool, error) {
+	if r.debugEnabled(ctx) {
+		r.logDebug(ctx, "invalid path", slog.String("path", reqPath), slog.Any("error", err))
+	}
+	if r.conf.ErrorHandler != nil {
+		return r.conf.ErrorHandler(ctx, reqPath, nil, wr, err)
+	}
+	return false, err
+}
+
+// correctPath returns the corrected path for a path which was not found with
+// the trailing slash and fixed path redirects, if enabled.
+// The tsr flag is the trailing slash recommendation from the lookup.
+func (r *Router[W]) correctPath(t *table[W], reqPath string, tsr bool) (string, bool) {
+	toPath, corrected := r.findCorrectedPath(t, reqPath, tsr)
+	if corrected && r.anyDisabled.Load() {
+		// the corrected path may only match disabled routes
+		matches, _ := r.matchAll(t, toPath)
+		r.putMatches(matches)
+		if len(matches) == 0 {
+			return "", false
+		}
+	}
+	return toPath, corrected
+}
+
+// findCorrectedPath returns the corrected path including the disabled routes,
+// see correctPath.
+func (r *Router[W]) findCorrectedPath(t *table[W], reqPath string, tsr bool) (string, bool) {
+	if reqPath == "/" {
+		return "", false
+	}
+
+	policy := r.trailingSlash()
+	if tsr && policy == TrailingSlashRedirect {
+		return toggleTrailingSlash(reqPath), true
+	}
+
+	// Try to fix the request path
+	if r.conf.RedirectFixedPath {
+		return t.findCaseInsensitivePath(
+			CleanPath(reqPath),
+			policy != TrailingSlashStrict,
+		)
+	}
+
+	return "", false
+}
+
+// serveMatch calls the handle for a matched route and releases the params.
+func (r *Router[W]) serveMatch(ctx context.Context, reqPath string, m match[W], wr W, matched *string) (bool, error) {
+	defer r.putParams(m.ps)
+	return r.callHandle(ctx, reqPath, m, wr, matched)
+}
+
+// serveMatches calls the handles for the matched routes in order.
+// If Fallthrough is not set, only the first match is used.
+func (r *Router[W]) serveMatches(ctx context.Context, reqPath string, matches []match[W], wr W, matched *string) (bool, error) {
+	defer r.putMatches(matches)
+	for _, m := range matches {
+		found, handlerErr := r.callHandle(ctx, reqPath, m, wr, matched)
+		if found || handlerErr != nil || !r.conf.Fallthrough {
+			return found, handlerErr
+		}
+	}
+	return false, nil
+}
+
+// callHandle calls the handle of the match with the matched params.
+// Sets matched to the pattern of the route if the handle returns true or an
+// error and matched is not nil.
+func (r *Router[W]) callHandle(ctx context.Context, reqPath string, m match[W], wr W, matched *string) (bool, error) {
+	if err := r.ctxErr(ctx); err != nil {
+		return r.canceled(ctx, fromTree(reqPath, r.conf.Separator), wr, err)
+	}
+	var params Params
+	if m.ps != nil {
+		params = *m.ps
+	}
+	if customSeparator(r.conf.Separator) {
+		reqPath = fromTree(reqPath, r.conf.Separator)
+		paramsFromTree(params, r.conf.Separator)
+	}
+	params = r.afterMatch(m.route, m.route.applyDefaults(params))
+	if r.recoverPanics() {
+		defer r.wrapPanic(reqPath, m, params)
+	}
+	if r.conf.ParamsInContext {
+		ctx = ContextWithParams(ctx, params)
+	}
+	if r.conf.PatternInContext && m.route != nil {
+		ctx = ContextWithPattern(ctx, m.route.matchedPattern())
+	}
+	if m.route != nil && m.route.locale != "" {
+		ctx = ContextWithLocale(ctx, m.route.locale)
+	}
+	var found bool
+	var err error
+	if r.conf.Limiter != nil && !r.allow(ctx, reqPath, m, params) {
+		found, err = r.limited(ctx, reqPath, params, wr)
+	} else {
+		found, err = r.handleWithTimeout(ctx, reqPath, m, params, wr)
+	}
+	if m.route != nil && r.debugEnabled(ctx) {
+		attrs := []slog.Attr{slog.String("path", reqPath), slog.String("pattern", m.route.matchedPattern()), paramsAttr(params), slog.Bool("handled", found)}
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+		}
+		r.logDebug(ctx, "matched route", attrs...)
+	}
+	if m.route != nil {
+		if r.conf.RouteStats && m.route.counters != nil {
+			m.route.counters.count(found, err)
+		}
+		if matched != nil && (found || err != nil) {
+			*matched = m.route.matchedPattern()
+		}
+		if err != nil && r.conf.Observer != nil {
+			r.conf.Observer.OnHandlerError(m.route.matchedPattern(), err)
+		}
+	}
+	if err != nil && r.conf.ErrorHandler != nil {
+		return r.conf.ErrorHandler(ctx, reqPath, params, wr, err)
+	}
+	return found, err
+}
+
+// SetFallback sets the router to serve requests not handled by this router.
+//
+// The fallback router is tried after the trailing slash and fixed path
+// redirects and before the NotFound handler. If neither router handles the
+// request a single NotFound handler is called: the one of this router, if
+// set, otherwise the one of the fallback router.
+// Set nil to remove the fallback router.
+func (r *Router[W]) SetFallback(next *Router[W]) {
+	for fb := next; fb != nil; fb = fb.fallback.Load() {
+		if fb == r {
+			panic("fallback router chain contains a cycle")
+		}
+	}
+	r.fallback.Store(next)
+}
+
+// notFoundCtxKey is the context key for the NotFound handler deferred by a
+// fallback router to the router which called it.
+type notFoundCtxKey struct{}
+
+// notFound tries the fallback router then calls the NotFound handler of the
+// group containing the path or of the router, if set.
+//
+// When serving as a fallback the NotFound handler is not called but deferred
+// to the calling router, which calls it only if it has none of its own.
+func (r *Router[W]) notFound(ctx context.Context, reqPath string, wr W) (bool, error) {
+	treePath := reqPath
+	reqPath = fromTree(reqPath, r.conf.Separator)
+	if r.debugEnabled(ctx) {
+		r.logDebug(ctx, "no route handled path", slog.String("path", reqPath))
+	}
+	deferred, isFallback := ctx.Value(notFoundCtxKey{}).(*func() (bool, error))
+	if fallback := r.fallback.Load(); fallback != nil {
+		fallbackCtx := ctx
+		if !isFallback {
+			deferred = new(func() (bool, error))
+			fallbackCtx = context.WithValue(ctx, notFoundCtxKey{}, deferred)
+		}
+		found, err := fallback.Serve(fallbackCtx, reqPath, wr)
+		if found || err != nil {
+			return found, err
+		}
+	}
+	notFound := r.conf.NotFound
+	if handle := r.load().groupNotFound(treePath); handle != nil {
+		notFound = handle
+	}
+	if notFound != nil {
+		call := func() (bool, error) {
+			found, err := notFound(ctx, reqPath, nil, wr)
+			if err != nil && r.conf.ErrorHandler != nil {
+				return r.conf.ErrorHandler(ctx, reqPath, nil, wr, err)
+			}
+			return found, err
+		}
+		if isFallback {
+			*deferred = call
+			return false, nil
+		}
+		return call()
+	}
+	if !isFallback && deferred != nil && *deferred != nil {
+		return (*deferred)()
+	}
+	return false, nil
+}
+
+// FindCaseInsensitivePath makes a case-insensitive lookup of the path and tries
+// to find a handler. It can optionally also fix trailing slashes.
+// It returns the case-corrected path and a bool indicating whether the lookup
+// was successful.
+//
+// This is the lookup used by Serve for RedirectFixedPath and is useful for
+// frameworks built on LookupPath implementing their own redirect policy.
+// The path is not cleaned, see CleanPath.
+func (r *Router[W]) FindCaseInsensitivePath(path string, fixTrailingSlash bool) (string, bool) {
+	return r.load().findCaseInsensitivePath(path, fixTrailingSlash)
+}
+
+// findCaseInsensitivePath makes a case-insensitive lookup of the path in each
+// of the trees, see FindCaseInsensitivePath.
+func (t *table[W]) findCaseInsensitivePath(path string, fixTrailingSlash bool) (string, bool) {
+	if fixTrailingSlash && len(t.trees) > 1 && path != "/" {
+		// fixing only the trailing slash takes precedence over fixing the case
+		// in another tree, the trees apply the precedence to their routes
+		toPath := toggleTrailingSlash(path)
+		for _, tree := range t.trees {
+			if leaf, _, _ := tree.getValue(toPath, nil, nil); leaf != nil && !leaf.route.anchored {
+				return toPath, true
+			}
+		}
+	}
+	for _, tree := range t.trees {
+		if fixedPath, found := tree.findCaseInsensitivePath(path, fixTrailingSlash); found {
+			return fixedPath, true
+		}
+	}
+	return "", false
 }
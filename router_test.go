@@ -8,6 +8,8 @@ package pathrouter
 import (
 	"context"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync/atomic"
 	"testing"
 
@@ -238,3 +240,607 @@ func TestRouterLookup(t *testing.T) {
 		t.Error("Got wrong TSR recommendation!")
 	}
 }
+
+func TestRouterAnchoredPath(t *testing.T) {
+	var lastPath string
+	handlerFunc := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		lastPath = reqPath
+		return true, nil
+	}
+
+	router := New[struct{}]()
+	router.AddHandler("/dir/{$}", handlerFunc)
+	router.AddHandler("/path/", handlerFunc)
+
+	testRoutes := []struct {
+		route    string
+		found    bool
+		location string
+	}{
+		{"/dir/", true, "/dir/"},    // Exact match
+		{"/dir", false, ""},         // No TSR +/
+		{"/DIR/", false, ""},        // No fixed case
+		{"/dir//", false, ""},       // No CleanPath
+		{"/path", true, "/path/"},   // TSR +/ (not anchored)
+		{"/dir/x", false, ""},       // NotFound
+		{"/dir/{$}", false, ""},     // Marker is not part of the path
+		{"/PATH/", true, "/path/"},  // Fixed case (not anchored)
+		{"/../dir/", false, ""},     // No CleanPath
+		{"/dir/../dir/", false, ""}, // No CleanPath
+	}
+	ctx := context.Background()
+	for _, tr := range testRoutes {
+		lastPath = ""
+		found, err := router.Serve(ctx, tr.route, struct{}{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if found != tr.found {
+			t.Errorf("anchored route %s failed: expected found=%v but got found=%v", tr.route, tr.found, found)
+		}
+		if lastPath != tr.location {
+			t.Errorf("anchored route %s failed: expected path=%v but got path=%v", tr.route, tr.location, lastPath)
+		}
+	}
+
+	recv := catchPanic(func() {
+		router.AddHandler("/nope{$}", handlerFunc)
+	})
+	if recv == nil {
+		t.Error("no panic for {$} without a trailing slash")
+	}
+}
+
+func TestRouterFallthrough(t *testing.T) {
+	var calls []string
+	handler := func(route string, found bool) Handle[struct{}] {
+		return func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			calls = append(calls, route+" "+p.ByName("name")+p.ByName("rest"))
+			return found, nil
+		}
+	}
+
+	conf := DefaultConfig[struct{}]()
+	conf.Fallthrough = true
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/*rest", handler("/user/*rest", true))
+	router.AddHandler("/user/:name", handler("/user/:name", false))
+	router.AddHandler("/user/new", handler("/user/new", false))
+	router.AddHandler("/user/:name/profile", handler("/user/:name/profile", true))
+	router.AddHandler("/static", handler("/static", false))
+
+	if recv := catchPanic(func() {
+		router.AddHandler("/user/new", handler("/user/new", true))
+	}); recv == nil {
+		t.Error("no panic for duplicate path")
+	}
+
+	tests := []struct {
+		path  string
+		found bool
+		calls []string
+	}{
+		{"/user/new", true, []string{"/user/new ", "/user/:name new", "/user/*rest /new"}},
+		{"/user/gopher", true, []string{"/user/:name gopher", "/user/*rest /gopher"}},
+		{"/user/gopher/profile", true, []string{"/user/:name/profile gopher"}},
+		{"/user/gopher/other", true, []string{"/user/*rest /gopher/other"}},
+		{"/static", false, []string{"/static "}},
+		{"/USER/NEW", true, []string{"/user/:name NEW", "/user/*rest /NEW"}},
+		{"/STATIC", false, []string{"/static "}},
+	}
+	ctx := context.Background()
+	for _, test := range tests {
+		calls = nil
+		found, err := router.Serve(ctx, test.path, struct{}{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if found != test.found {
+			t.Errorf("path %s: expected found=%v but got found=%v", test.path, test.found, found)
+		}
+		if !reflect.DeepEqual(calls, test.calls) {
+			t.Errorf("path %s: expected calls %q but got %q", test.path, test.calls, calls)
+		}
+	}
+
+	// LookupPath returns the route with precedence.
+	calls = nil
+	handle, ps, _ := router.LookupPath("/user/new")
+	if handle == nil {
+		t.Fatal("expected handle for /user/new")
+	}
+	_, _ = handle(ctx, "/user/new", ps, struct{}{})
+	if !reflect.DeepEqual(calls, []string{"/user/new "}) {
+		t.Errorf("LookupPath returned the wrong route: %q", calls)
+	}
+}
+
+func TestRouterFallthroughDelta(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.Fallthrough = true
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+
+	err := router.ApplyDelta(RouteDelta[struct{}]{
+		FromVersion: router.Version(),
+		ToVersion:   router.Version() + 1,
+		Added:       []RouteDef[struct{}]{{Pattern: "/user/new", Handle: fakeHandler("/user/new")}},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for path, route := range map[string]string{"/user/new": "/user/new", "/user/gopher": "/user/:name"} {
+		fakeHandlerValue = ""
+		if _, err := router.Serve(context.Background(), path, struct{}{}); err != nil {
+			t.Fatal(err.Error())
+		}
+		if fakeHandlerValue != route {
+			t.Errorf("path %s: expected route %s but got %s", path, route, fakeHandlerValue)
+		}
+	}
+}
+
+func TestRouterFallback(t *testing.T) {
+	var notFound []string
+	notFoundHandler := func(name string) Handle[struct{}] {
+		return func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			notFound = append(notFound, name)
+			return false, nil
+		}
+	}
+
+	primaryConf := DefaultConfig[struct{}]()
+	primaryConf.NotFound = notFoundHandler("primary")
+	primary := NewWithConfig(primaryConf)
+	primary.AddHandler("/user/:name", fakeHandler("primary /user/:name"))
+	primary.AddHandler("/skip", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, nil
+	})
+
+	secondaryConf := DefaultConfig[struct{}]()
+	secondaryConf.NotFound = notFoundHandler("secondary")
+	secondary := NewWithConfig(secondaryConf)
+	secondary.AddHandler("/user/:name", fakeHandler("secondary /user/:name"))
+	secondary.AddHandler("/order/:id", fakeHandler("secondary /order/:id"))
+	secondary.AddHandler("/skip", fakeHandler("secondary /skip"))
+	primary.SetFallback(secondary)
+
+	tests := []struct {
+		path  string
+		found bool
+		route string
+	}{
+		{"/user/gopher", true, "primary /user/:name"},
+		{"/order/1", true, "secondary /order/:id"},
+		{"/ORDER/1", true, "secondary /order/:id"},
+		{"/skip", true, "secondary /skip"},
+		{"/nope", false, ""},
+	}
+	ctx := context.Background()
+	for _, test := range tests {
+		fakeHandlerValue, notFound = "", nil
+		found, err := primary.Serve(ctx, test.path, struct{}{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if found != test.found || fakeHandlerValue != test.route {
+			t.Errorf("path %s: expected found=%v route=%q but got found=%v route=%q", test.path, test.found, test.route, found, fakeHandlerValue)
+		}
+		if !test.found && !reflect.DeepEqual(notFound, []string{"primary"}) {
+			t.Errorf("path %s: expected only primary NotFound but got %v", test.path, notFound)
+		}
+	}
+
+	// the fallback NotFound handler is used if the router has none
+	primary.conf.NotFound = nil
+	notFound = nil
+	primary.Serve(ctx, "/nope", struct{}{})
+	if !reflect.DeepEqual(notFound, []string{"secondary"}) {
+		t.Errorf("expected only secondary NotFound but got %v", notFound)
+	}
+	primary.conf.NotFound = notFoundHandler("primary")
+
+	if recv := catchPanic(func() { secondary.SetFallback(primary) }); recv == nil {
+		t.Error("no panic for fallback cycle")
+	}
+	primary.SetFallback(nil)
+	if found, _ := primary.Serve(ctx, "/order/1", struct{}{}); found {
+		t.Error("fallback was not removed")
+	}
+}
+
+func TestRouterErrorHandler(t *testing.T) {
+	errHandler := errors.New("handler error")
+	errNotFound := errors.New("not found error")
+
+	conf := DefaultConfig[struct{}]()
+	conf.NotFound = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, errNotFound
+	}
+	var handledErr error
+	var handledParams Params
+	conf.ErrorHandler = func(ctx context.Context, reqPath string, p Params, rw struct{}, err error) (bool, error) {
+		handledErr, handledParams = err, p.Clone()
+		return true, nil
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, errHandler
+	})
+	router.AddHandler("/ok", fakeHandler("/ok"))
+
+	ctx := context.Background()
+	found, err := router.Serve(ctx, "/user/gopher", struct{}{})
+	if !found || err != nil {
+		t.Errorf("expected error handler result, got found=%v err=%v", found, err)
+	}
+	if handledErr != errHandler || handledParams.ByName("name") != "gopher" {
+		t.Errorf("error handler called with err=%v params=%v", handledErr, handledParams)
+	}
+
+	handledErr = nil
+	if found, err := router.Serve(ctx, "/nope", struct{}{}); !found || err != nil || handledErr != errNotFound {
+		t.Errorf("expected error handler for NotFound, got found=%v err=%v handled=%v", found, err, handledErr)
+	}
+
+	handledErr = nil
+	if found, err := router.Serve(ctx, "/ok", struct{}{}); !found || err != nil || handledErr != nil {
+		t.Errorf("unexpected result found=%v err=%v handled=%v", found, err, handledErr)
+	}
+}
+
+func TestRouterRedirectHandler(t *testing.T) {
+	var from, to string
+	conf := DefaultConfig[struct{}]()
+	conf.RedirectHandler = func(ctx context.Context, fromPath, toPath string, rw struct{}) (bool, error) {
+		from, to = fromPath, toPath
+		return true, nil
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/path", fakeHandler("/path"))
+	router.AddHandler("/dir/", fakeHandler("/dir/"))
+
+	tests := []struct {
+		path     string
+		location string
+	}{
+		{"/path", ""},
+		{"/path/", "/path"},
+		{"/dir", "/dir/"},
+		{"/PATH", "/path"},
+		{"/../dir", "/dir/"},
+	}
+	ctx := context.Background()
+	for _, test := range tests {
+		from, to, fakeHandlerValue = "", "", ""
+		found, err := router.Serve(ctx, test.path, struct{}{})
+		if err != nil || !found {
+			t.Fatalf("path %s: unexpected result found=%v err=%v", test.path, found, err)
+		}
+		if to != test.location {
+			t.Errorf("path %s: expected redirect to %q but got %q", test.path, test.location, to)
+		}
+		if test.location != "" && (from != test.path || fakeHandlerValue != "") {
+			t.Errorf("path %s: redirect from %q served %q", test.path, from, fakeHandlerValue)
+		}
+	}
+}
+
+func TestRouterMaxCorrections(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.MaxCorrections = -1
+	var notFoundPath string
+	conf.NotFound = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		notFoundPath = reqPath
+		return false, nil
+	}
+	router := NewWithConfig(conf)
+	router.AddHandler("/path", fakeHandler("/path"))
+
+	ctx := context.Background()
+	for _, path := range []string{"/path/", "/PATH"} {
+		fakeHandlerValue, notFoundPath = "", ""
+		if found, err := router.Serve(ctx, path, struct{}{}); found || err != nil {
+			t.Errorf("path %s: expected not found, got found=%v err=%v", path, found, err)
+		}
+		if notFoundPath != path || fakeHandlerValue != "" {
+			t.Errorf("path %s: correction was served: %q %q", path, notFoundPath, fakeHandlerValue)
+		}
+	}
+
+	if found, _ := router.Serve(ctx, "/path", struct{}{}); !found {
+		t.Error("exact path not found")
+	}
+}
+
+func TestRouterFindCaseInsensitivePath(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/path", fakeHandler("/path"))
+	router.AddHandler("/dir/", fakeHandler("/dir/"))
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	router.AddHandler("/anchored/{$}", fakeHandler("/anchored/{$}"))
+
+	tests := []struct {
+		path     string
+		fixTSR   bool
+		found    bool
+		location string
+	}{
+		{"/PATH", false, true, "/path"},
+		{"/PATH/", false, false, ""},
+		{"/PATH/", true, true, "/path"},
+		{"/Dir", true, true, "/dir/"},
+		{"/USER/Gopher", false, true, "/user/Gopher"},
+		{"/ANCHORED/", true, false, ""},
+		{"/nope", true, false, ""},
+	}
+	for _, test := range tests {
+		location, found := router.FindCaseInsensitivePath(test.path, test.fixTSR)
+		if found != test.found || location != test.location {
+			t.Errorf("path %s: expected %v %q but got %v %q", test.path, test.found, test.location, found, location)
+		}
+	}
+
+	// a trailing slash fix in a later tree takes precedence over a case fix
+	tbl := &table[struct{}]{}
+	for _, pattern := range []string{"/FOO", "/foo/"} {
+		rt, err := newRoute(pattern, fakeHandler(pattern), 0)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		tree := new(node[struct{}])
+		tree.insertRoute(rt)
+		tbl.trees = append(tbl.trees, tree)
+	}
+	if location, found := tbl.findCaseInsensitivePath("/foo", true); !found || location != "/foo/" {
+		t.Errorf("expected the trailing slash fix /foo/ but got %v %q", found, location)
+	}
+}
+
+func TestRouterPathNormalizer(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.RedirectFixedPath = false
+	conf.PathNormalizer = func(reqPath string) string {
+		return CleanPath(strings.TrimPrefix(reqPath, "/tenant"))
+	}
+	router := NewWithConfig(conf)
+
+	var gotPath string
+	router.AddHandler("/user/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		gotPath = reqPath
+		return true, nil
+	})
+
+	ctx := context.Background()
+	for _, path := range []string{"/user/gopher", "/tenant/user/gopher", "/tenant//user/./gopher"} {
+		gotPath = ""
+		if found, err := router.Serve(ctx, path, struct{}{}); !found || err != nil {
+			t.Errorf("path %s: expected found, got found=%v err=%v", path, found, err)
+		}
+		if gotPath != "/user/gopher" {
+			t.Errorf("path %s: expected normalized path but got %q", path, gotPath)
+		}
+		if res := router.Lookup(path); res.Pattern != "/user/:name" {
+			t.Errorf("path %s: lookup expected pattern but got %q", path, res.Pattern)
+		}
+	}
+}
+
+func TestRouterLookupHooks(t *testing.T) {
+	var order []string
+	conf := DefaultConfig[struct{}]()
+	conf.UnescapePath = true
+	conf.BeforeLookup = func(reqPath string) string {
+		order = append(order, "before "+reqPath)
+		return strings.TrimPrefix(reqPath, "/acme")
+	}
+	conf.AfterMatch = func(pattern string, ps Params) Params {
+		order = append(order, "after "+pattern)
+		for i := range ps {
+			ps[i].Value = strings.ToLower(ps[i].Value)
+		}
+		return ps
+	}
+	router := NewWithConfig(conf)
+
+	var gotPath, gotSlug string
+	router.AddHandler("/posts/:slug", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		gotPath, gotSlug = reqPath, p.ByName("slug")
+		return true, nil
+	})
+
+	ctx := context.Background()
+	if found, err := router.Serve(ctx, "/acme/posts/Hello%20World", struct{}{}); !found || err != nil {
+		t.Fatalf("expected found, got found=%v err=%v", found, err)
+	}
+	if gotPath != "/posts/Hello World" || gotSlug != "hello world" {
+		t.Errorf("unexpected path %q slug %q", gotPath, gotSlug)
+	}
+	if want := []string{"before /acme/posts/Hello%20World", "after /posts/:slug"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("expected hooks %v, got %v", want, order)
+	}
+
+	if res := router.Lookup("/acme/posts/Go"); res.Params.ByName("slug") != "go" {
+		t.Errorf("expected lookup params to be rewritten, got %v", res.Params)
+	}
+	if matches := router.LookupAll("/acme/posts/Go"); len(matches) != 1 || matches[0].Params.ByName("slug") != "go" {
+		t.Errorf("expected LookupAll params to be rewritten, got %+v", matches)
+	}
+	gotSlug = ""
+	if found, _ := router.ServeAll(ctx, "/acme/posts/Go", struct{}{}); !found || gotSlug != "go" {
+		t.Errorf("expected ServeAll params to be rewritten, got %q", gotSlug)
+	}
+}
+
+func TestRouterConcurrentAddHandler(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/static", fakeHandler("/static"))
+
+	const n = 100
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < n; i++ {
+			router.AddHandler("/user"+strconv.Itoa(i)+"/:name/:id", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+				return true, nil
+			})
+		}
+	}()
+
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		if found, err := router.Serve(ctx, "/static", struct{}{}); !found || err != nil {
+			t.Fatalf("expected static route found, got found=%v err=%v", found, err)
+		}
+		_, _ = router.Serve(ctx, "/user"+strconv.Itoa(i)+"/gopher/1", struct{}{})
+	}
+	<-done
+
+	if found, _ := router.Serve(ctx, "/user99/gopher/1", struct{}{}); !found {
+		t.Error("expected route added concurrently to be found")
+	}
+	if router.Version() != n+1 {
+		t.Errorf("expected version %d but got %d", n+1, router.Version())
+	}
+}
+
+func TestRouterAddHandlerConflictUnchanged(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	before := router.Version()
+
+	if recv := catchPanic(func() {
+		router.AddHandler("/user/:id/x", fakeHandler("conflict"))
+	}); recv == nil {
+		t.Fatal("expected conflict panic")
+	}
+	if router.Version() != before {
+		t.Errorf("version changed after failed registration")
+	}
+	if _, ps, _ := router.LookupPath("/user/gopher"); ps.ByName("name") != "gopher" {
+		t.Errorf("route table changed after failed registration: %v", ps)
+	}
+}
+
+func TestRouterClone(t *testing.T) {
+	base := New[struct{}]()
+	base.AddHandler("/user/:name", fakeHandler("/user/:name"))
+
+	clone := base.Clone()
+	clone.AddHandler("/user/:name/posts", fakeHandler("/user/:name/posts"))
+	base.AddHandler("/about", fakeHandler("/about"))
+
+	ctx := context.Background()
+	tests := []struct {
+		router *Router[struct{}]
+		path   string
+		found  bool
+	}{
+		{base, "/user/gopher", true},
+		{clone, "/user/gopher", true},
+		{base, "/user/gopher/posts", false},
+		{clone, "/user/gopher/posts", true},
+		{base, "/about", true},
+		{clone, "/about", false},
+	}
+	for _, test := range tests {
+		if found, _ := test.router.Serve(ctx, test.path, struct{}{}); found != test.found {
+			t.Errorf("path %s: expected found=%v but got %v", test.path, test.found, found)
+		}
+	}
+}
+
+func TestRouterStaticFastPath(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.StaticFastPath = true
+	router := NewWithConfig(conf)
+	router.AddHandler("/about", fakeHandler("/about"))
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	router.AddHandler("/dir/{$}", fakeHandler("/dir/{$}"))
+
+	ctx := context.Background()
+	tests := []struct {
+		path  string
+		found bool
+		value string
+	}{
+		{"/about", true, "/about"},
+		{"/user/gopher", true, "/user/:name"},
+		{"/dir/", true, "/dir/{$}"},
+		{"/dir", false, ""},
+		{"/ABOUT", true, "/about"},
+	}
+	for _, test := range tests {
+		fakeHandlerValue = ""
+		if found, _ := router.Serve(ctx, test.path, struct{}{}); found != test.found || fakeHandlerValue != test.value {
+			t.Errorf("path %s: expected %v %q but got %v %q", test.path, test.found, test.value, found, fakeHandlerValue)
+		}
+	}
+
+	// the map must be updated with the chain
+	var appended bool
+	router.AddHandler("/next", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, nil
+	})
+	router.AppendHandler("/next", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		appended = true
+		return true, nil
+	})
+	if found, _ := router.Serve(ctx, "/next", struct{}{}); !found || !appended {
+		t.Errorf("static lookup did not use the appended handle: %v %v", found, appended)
+	}
+
+	// and rebuilt when a delta is applied
+	if err := router.ApplyDelta(RouteDelta[struct{}]{
+		FromVersion: router.Version(),
+		ToVersion:   router.Version() + 1,
+		Removed:     []string{"/about"},
+	}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if found, _ := router.Serve(ctx, "/about", struct{}{}); found {
+		t.Error("removed static route was served")
+	}
+}
+
+func BenchmarkRouterStaticRoutes(b *testing.B) {
+	const routeCount = 3000
+	paths := make([]string, routeCount)
+	for i := range paths {
+		paths[i] = "/api/v1/resource" + strconv.Itoa(i%100) + "/action" + strconv.Itoa(i)
+	}
+	handle := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return true, nil
+	}
+
+	for _, fastPath := range []bool{false, true} {
+		name := "tree"
+		if fastPath {
+			name = "map"
+		}
+		b.Run(name, func(b *testing.B) {
+			conf := DefaultConfig[struct{}]()
+			conf.StaticFastPath = fastPath
+			router := NewWithConfig(conf)
+			if err := router.ApplyDelta(routeDeltaForPaths(paths, handle)); err != nil {
+				b.Fatal(err.Error())
+			}
+
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = router.Serve(ctx, paths[i%len(paths)], struct{}{})
+			}
+		})
+	}
+}
+
+// routeDeltaForPaths builds a full snapshot delta registering handle for paths.
+func routeDeltaForPaths[W any](paths []string, handle Handle[W]) RouteDelta[W] {
+	delta := RouteDelta[W]{ToVersion: uint64(len(paths))}
+	for _, path := range paths {
+		delta.Added = append(delta.Added, RouteDef[W]{Pattern: path, Handle: handle})
+	}
+	return delta
+}
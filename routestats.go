@@ -0,0 +1,109 @@
+package pathrouter
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RouteStat contains the statistics of a route, see RouterConfig.RouteStats.
+type RouteStat struct {
+	// Pattern is the route pattern.
+	Pattern string
+	// Name is the handler name, if any, see WithName.
+	Name string
+	// Matches is the number of requests matching the route.
+	Matches uint64
+	// Declined is the number of matches where the handle returned false
+	// without an error, for example to fall through to the next route.
+	Declined uint64
+	// Errors is the number of matches where the handle returned an error.
+	Errors uint64
+	// Lookups is the number of paths matching the route looked up with Lookup
+	// and LookupPath.
+	Lookups uint64
+	// LastHit is the time of the last match or zero if none.
+	LastHit time.Time
+}
+
+// routeCounters contains the counters of a route.
+type routeCounters struct {
+	matches  atomic.Uint64
+	declined atomic.Uint64
+	errors   atomic.Uint64
+	lookups  atomic.Uint64
+	// lastHit is the time of the last match in unix nanoseconds.
+	lastHit atomic.Int64
+}
+
+// count counts a match with the result of the handle.
+func (c *routeCounters) count(found bool, err error) {
+	c.matches.Add(1)
+	c.lastHit.Store(time.Now().UnixNano())
+	switch {
+	case err != nil:
+		c.errors.Add(1)
+	case !found:
+		c.declined.Add(1)
+	}
+}
+
+// allocCounters allocates the counters of the routes added to the table. The
+// aliases share the counters of the canonical routes. The table must not be
+// published yet.
+func allocCounters[W any](t *table[W]) {
+	for _, rt := range t.routes {
+		if rt.counters == nil && rt.canonical == "" {
+			rt.counters = new(routeCounters)
+		}
+	}
+	for _, rt := range t.routes {
+		if rt.counters == nil {
+			if canonical := t.routes[rt.canonical]; canonical != nil {
+				rt.counters = canonical.counters
+			}
+		}
+	}
+}
+
+// countLookup counts a lookup matching the route, if enabled.
+func (r *Router[W]) countLookup(rt *route[W]) {
+	if r.conf.RouteStats && rt != nil && rt.counters != nil {
+		rt.counters.lookups.Add(1)
+	}
+}
+
+// RouteStats returns the statistics of the registered routes in registration
+// order. The counters are zero unless RouterConfig.RouteStats is set.
+//
+// The counters are kept when handles are appended to a route and reset when a
+// route is replaced, for example with ApplyDelta. Aliases are counted with the
+// canonical route and not listed, see AddAlias.
+func (r *Router[W]) RouteStats() []RouteStat {
+	routes := sortRoutes(r.load().routes)
+	stats := make([]RouteStat, 0, len(routes))
+	for _, rt := range routes {
+		if rt.canonical != "" {
+			continue
+		}
+		stat := RouteStat{Pattern: rt.pattern, Name: rt.name}
+		if c := rt.counters; c != nil {
+			stat.Matches = c.matches.Load()
+			stat.Declined = c.declined.Load()
+			stat.Errors = c.errors.Load()
+			stat.Lookups = c.lookups.Load()
+			if lastHit := c.lastHit.Load(); lastHit != 0 {
+				stat.LastHit = time.Unix(0, lastHit)
+			}
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// FallthroughMisses returns the number of requests not found after every
+// matched route declined, for example overlapping routes falling through to
+// the NotFound handler, see RouterConfig.Fallthrough. Zero unless
+// RouterConfig.RouteStats is set.
+func (r *Router[W]) FallthroughMisses() uint64 {
+	return r.fallthroughMisses.Load()
+}
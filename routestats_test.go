@@ -0,0 +1,92 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRouterRouteStats(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.RouteStats = true
+	conf.Fallthrough = true
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/new", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return false, nil
+	}, WithName("new"))
+	router.AddHandler("/user/:name", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		switch p.ByName("name") {
+		case "error":
+			return false, errors.New("handler error")
+		case "decline":
+			return false, nil
+		}
+		return true, nil
+	})
+	router.AddHandler("/dead", fakeHandler("dead"))
+
+	start := time.Now()
+	ctx := context.Background()
+	for _, path := range []string{"/user/new", "/user/gopher", "/user/error", "/user/decline", "/missing"} {
+		_, _ = router.Serve(ctx, path, struct{}{})
+	}
+
+	if misses := router.FallthroughMisses(); misses != 1 {
+		t.Errorf("expected one request not found after falling through, got %d", misses)
+	}
+
+	stats := router.RouteStats()
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 route stats but got %d", len(stats))
+	}
+	tests := []struct {
+		pattern  string
+		name     string
+		matches  uint64
+		declined uint64
+		errors   uint64
+	}{
+		{"/user/new", "new", 1, 1, 0},
+		{"/user/:name", "", 4, 1, 1},
+		{"/dead", "", 0, 0, 0},
+	}
+	for i, test := range tests {
+		st := stats[i]
+		if st.Pattern != test.pattern || st.Name != test.name || st.Matches != test.matches || st.Declined != test.declined || st.Errors != test.errors {
+			t.Errorf("route %d: expected %+v but got %+v", i, test, st)
+		}
+		if hit := test.matches != 0; hit != !st.LastHit.IsZero() || hit && st.LastHit.Before(start) {
+			t.Errorf("route %s: unexpected last hit %v", st.Pattern, st.LastHit)
+		}
+	}
+}
+
+func TestRouterRouteStatsDisabled(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/", fakeHandler("/"))
+	_, _ = router.Serve(context.Background(), "/", struct{}{})
+	if stats := router.RouteStats(); len(stats) != 1 || stats[0].Matches != 0 {
+		t.Errorf("expected no counts, got %+v", stats)
+	}
+	if rt := router.load().routes["/"]; rt.counters != nil {
+		t.Error("expected no counters to be allocated")
+	}
+}
+
+func TestRouterRouteStatsLookups(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.RouteStats = true
+	router := NewWithConfig(conf)
+	router.AddHandler("/user/:name", fakeHandler("user"))
+	router.AddHandler("/about", fakeHandler("about"))
+
+	_ = router.Lookup("/user/gopher")
+	_, _, _ = router.LookupPath("/user/gopher")
+	_ = router.Lookup("/missing")
+
+	stats := router.RouteStats()
+	if stats[0].Lookups != 2 || stats[0].Matches != 0 || stats[1].Lookups != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,135 @@
+// Package rpcroute routes RPC method paths like /package.Service/Method.
+//
+// The method paths of gRPC, drpc and starpc calls consist of the fully
+// qualified service ID and the method ID. Router dispatches calls by method path
+// with the stream of the call as the pathrouter response writer type, which is
+// useful for proxies and interceptors:
+//
+//	router := rpcroute.NewRouter[srpc.Stream]()
+//	router.HandleService("echo.Echoer", func(ctx context.Context, service, method string, strm srpc.Stream) (bool, error) {
+//		return echoer.InvokeMethod(service, method, strm)
+//	})
+//	router.AddHandler("/:service/Health", healthCheck)
+//	// router implements the srpc.Invoker interface
+//	srpc.NewServer(router)
+package rpcroute
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aperturerobotics/pathrouter"
+	"github.com/pkg/errors"
+)
+
+// Param names of the service and method IDs in the patterns registered by
+// HandleService.
+const (
+	ServiceParam = "service"
+	MethodParam  = "method"
+)
+
+// MethodPath returns the method path for the service and method IDs.
+func MethodPath(serviceID, methodID string) string {
+	return "/" + serviceID + "/" + methodID
+}
+
+// SplitMethodPath splits a method path into the service and method IDs.
+// The leading slash is optional.
+func SplitMethodPath(path string) (serviceID, methodID string, err error) {
+	path = strings.TrimPrefix(path, "/")
+	serviceID, methodID, ok := strings.Cut(path, "/")
+	if !ok || serviceID == "" || methodID == "" || strings.Contains(methodID, "/") {
+		return "", "", errors.Errorf("invalid method path '%s'", path)
+	}
+	return serviceID, methodID, nil
+}
+
+// InvokeFunc handles a call with the service and method IDs.
+type InvokeFunc[S any] func(ctx context.Context, serviceID, methodID string, strm S) (bool, error)
+
+// Invoker invokes methods by service and method ID.
+//
+// Matches the srpc.Invoker interface of starpc with S set to srpc.Stream.
+type Invoker[S any] interface {
+	// InvokeMethod invokes the method matching the service and method IDs.
+	// Returns false, nil if the method was not found.
+	InvokeMethod(serviceID, methodID string, strm S) (bool, error)
+}
+
+// Router dispatches RPC calls by method path.
+//
+// Safe to use concurrently.
+type Router[S any] struct {
+	router *pathrouter.Router[S]
+}
+
+// NewRouter constructs a new Router.
+//
+// Method paths are matched exactly: the trailing slash and fixed path
+// redirects are disabled. Overlapping patterns are allowed: a handle for a
+// single method takes precedence over a handle for the service and a handle
+// returning false, nil falls through to the next matching handle.
+func NewRouter[S any]() *Router[S] {
+	return NewRouterWithConfig(pathrouter.RouterConfig[S]{Fallthrough: true})
+}
+
+// NewRouterWithConfig constructs a new Router with the given config.
+func NewRouterWithConfig[S any](conf pathrouter.RouterConfig[S]) *Router[S] {
+	return &Router[S]{router: pathrouter.NewWithConfig(conf)}
+}
+
+// Router returns the underlying path router.
+func (r *Router[S]) Router() *pathrouter.Router[S] {
+	return r.router
+}
+
+// AddHandler registers a handle with a method path pattern, for example
+// /:service/:method, /echo.Echoer/:method or /files.Files/*rest.
+func (r *Router[S]) AddHandler(pattern string, handle pathrouter.Handle[S], opts ...pathrouter.RouteOption) {
+	r.router.AddHandler(pattern, handle, opts...)
+}
+
+// HandleMethod registers a handle for a single method of a service.
+func (r *Router[S]) HandleMethod(serviceID, methodID string, handle InvokeFunc[S], opts ...pathrouter.RouteOption) {
+	r.router.AddHandler(MethodPath(serviceID, methodID), func(ctx context.Context, reqPath string, p pathrouter.Params, strm S) (bool, error) {
+		return handle(ctx, serviceID, methodID, strm)
+	}, opts...)
+}
+
+// HandleService registers a handle for all methods of a service.
+func (r *Router[S]) HandleService(serviceID string, handle InvokeFunc[S], opts ...pathrouter.RouteOption) {
+	r.router.AddHandler(MethodPath(serviceID, ":"+MethodParam), func(ctx context.Context, reqPath string, p pathrouter.Params, strm S) (bool, error) {
+		return handle(ctx, serviceID, p.ByName(MethodParam), strm)
+	}, opts...)
+}
+
+// HandleInvoker registers an Invoker for all methods of a service.
+func (r *Router[S]) HandleInvoker(serviceID string, inv Invoker[S], opts ...pathrouter.RouteOption) {
+	r.HandleService(serviceID, func(ctx context.Context, serviceID, methodID string, strm S) (bool, error) {
+		return inv.InvokeMethod(serviceID, methodID, strm)
+	}, opts...)
+}
+
+// Invoke dispatches a call by method path.
+// Returns false, nil if no handle matched.
+func (r *Router[S]) Invoke(ctx context.Context, methodPath string, strm S) (bool, error) {
+	if !strings.HasPrefix(methodPath, "/") {
+		methodPath = "/" + methodPath
+	}
+	return r.router.Serve(ctx, methodPath, strm)
+}
+
+// InvokeMethod dispatches a call by service and method IDs, implementing
+// Invoker. The context is the context of the stream if it has a Context method,
+// as the starpc and drpc streams do.
+func (r *Router[S]) InvokeMethod(serviceID, methodID string, strm S) (bool, error) {
+	ctx := context.Background()
+	if cs, ok := any(strm).(interface{ Context() context.Context }); ok {
+		ctx = cs.Context()
+	}
+	return r.Invoke(ctx, MethodPath(serviceID, methodID), strm)
+}
+
+// _ is a type assertion
+var _ Invoker[any] = ((*Router[any])(nil))
@@ -0,0 +1,100 @@
+package rpcroute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/pathrouter"
+)
+
+// testStream is a stream with a context.
+type testStream struct {
+	ctx   context.Context
+	calls []string
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+// testInvoker records the invoked methods.
+type testInvoker struct{}
+
+func (testInvoker) InvokeMethod(serviceID, methodID string, strm *testStream) (bool, error) {
+	if methodID == "Missing" {
+		return false, nil
+	}
+	strm.calls = append(strm.calls, "invoker "+serviceID+"/"+methodID)
+	return true, nil
+}
+
+func TestSplitMethodPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		service string
+		method  string
+	}{
+		{"/echo.Echoer/Echo", "echo.Echoer", "Echo"},
+		{"pkg.v1.Service/Method", "pkg.v1.Service", "Method"},
+		{"/echo.Echoer", "", ""},
+		{"/echo.Echoer/", "", ""},
+		{"/a/b/c", "", ""},
+	}
+	for _, test := range tests {
+		service, method, err := SplitMethodPath(test.path)
+		if (err != nil) != (test.service == "") || service != test.service || method != test.method {
+			t.Errorf("path %s: unexpected result %q %q %v", test.path, service, method, err)
+		}
+		if err == nil && MethodPath(service, method) != "/"+service+"/"+method {
+			t.Errorf("path %s: unexpected method path", test.path)
+		}
+	}
+}
+
+func TestRouter(t *testing.T) {
+	type ctxKey struct{}
+	record := func(name string) InvokeFunc[*testStream] {
+		return func(ctx context.Context, serviceID, methodID string, strm *testStream) (bool, error) {
+			strm.calls = append(strm.calls, name+" "+serviceID+"/"+methodID+" "+ctx.Value(ctxKey{}).(string))
+			return true, nil
+		}
+	}
+
+	router := NewRouter[*testStream]()
+	router.HandleService("echo.Echoer", record("service"))
+	router.HandleMethod("echo.Echoer", "Health", record("method"))
+	router.HandleInvoker("files.Files", testInvoker{})
+	router.AddHandler("/:service/Ping", func(ctx context.Context, reqPath string, p pathrouter.Params, strm *testStream) (bool, error) {
+		strm.calls = append(strm.calls, "ping "+p.ByName("service"))
+		return true, nil
+	})
+
+	tests := []struct {
+		service string
+		method  string
+		call    string
+	}{
+		{"echo.Echoer", "Echo", "service echo.Echoer/Echo ctx"},
+		{"echo.Echoer", "Health", "method echo.Echoer/Health ctx"},
+		{"files.Files", "Read", "invoker files.Files/Read"},
+		{"other.Service", "Ping", "ping other.Service"},
+		{"files.Files", "Missing", ""},
+		{"other.Service", "Pong", ""},
+	}
+	for _, test := range tests {
+		strm := &testStream{ctx: context.WithValue(context.Background(), ctxKey{}, "ctx")}
+		found, err := router.InvokeMethod(test.service, test.method, strm)
+		if err != nil || found != (test.call != "") {
+			t.Errorf("%s/%s: unexpected result found=%v err=%v", test.service, test.method, found, err)
+			continue
+		}
+		if found && (len(strm.calls) != 1 || strm.calls[0] != test.call) {
+			t.Errorf("%s/%s: expected call %q but got %q", test.service, test.method, test.call, strm.calls)
+		}
+	}
+
+	strm := &testStream{ctx: context.WithValue(context.Background(), ctxKey{}, "invoke")}
+	if found, err := router.Invoke(strm.ctx, "echo.Echoer/Echo", strm); !found || err != nil || strm.calls[0] != "service echo.Echoer/Echo invoke" {
+		t.Errorf("unexpected result found=%v err=%v calls=%q", found, err, strm.calls)
+	}
+}
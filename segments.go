@@ -0,0 +1,306 @@
+package pathrouter
+
+import (
+	"strings"
+)
+
+// segmentSeparator returns the separator of the segments.
+func (r *Router[W]) segmentSeparator() byte {
+	if customSeparator(r.conf.Separator) {
+		return r.conf.Separator
+	}
+	return '/'
+}
+
+// segmentsPath joins the segments with the separator to a path.
+// Returns false if a segment contains the separator.
+func (r *Router[W]) segmentsPath(segments []string) (string, bool) {
+	sep := r.segmentSeparator()
+	n := len(segments)
+	for _, seg := range segments {
+		if strings.IndexByte(seg, sep) >= 0 {
+			return "", false
+		}
+		n += len(seg)
+	}
+
+	var sb strings.Builder
+	sb.Grow(n)
+	for i, seg := range segments {
+		// paths with a custom separator do not start with the separator
+		if i != 0 || sep == '/' {
+			sb.WriteByte(sep)
+		}
+		sb.WriteString(seg)
+	}
+	if sb.Len() == 0 && sep == '/' {
+		return "/", true
+	}
+	return sb.String(), true
+}
+
+// AddHandlerSegments registers a new handle with the pattern given as the
+// segments, for example "users", ":id" and "*rest".
+//
+// Panics if a segment contains the separator or if the pattern is invalid or
+// conflicts with a registered pattern.
+func (r *Router[W]) AddHandlerSegments(segments []string, handle Handle[W], opts ...RouteOption) {
+	pattern, ok := r.segmentsPath(segments)
+	if !ok {
+		panic("separator in pattern segments " + strings.Join(segments, ", "))
+	}
+	r.AddHandler(pattern, handle, opts...)
+}
+
+// LookupSegments looks up the path given as the segments, for example the
+// tokens of a message subject, see Lookup.
+//
+// The trees are walked segment by segment without joining the segments to a
+// path, unless no route matches: the path is then joined to find the redirect
+// Lookup would return. The segments are matched literally: they are not
+// unescaped or normalized, see RouterConfig.UnescapePath. Returns an empty
+// result if a segment contains the separator.
+func (r *Router[W]) LookupSegments(segments []string) LookupResult[W] {
+	var res LookupResult[W]
+	sep := r.segmentSeparator()
+	length := len(segments)
+	for _, seg := range segments {
+		if strings.IndexByte(seg, sep) >= 0 {
+			return res
+		}
+		length += len(seg)
+	}
+	t := r.load()
+	if len(t.trees) == 0 || r.checkSegmentLimits(segments, length) != nil {
+		return res
+	}
+
+	if matches := r.matchSegments(t, segments); len(matches) != 0 {
+		r.putMatches(matches[1:])
+		// the path is only used by lazy params
+		return r.lookupMatch(t, matches[0], "", 0, lookupOptions{})
+	}
+	path, _ := r.segmentsPath(segments)
+	return r.lookupNormalized(t, toTree(path, r.conf.Separator), 0, lookupOptions{})
+}
+
+// checkSegmentLimits checks the segments against the limits as checkLimits
+// checks the joined path. The length is the number of bytes of the segments
+// plus one separator per segment.
+func (r *Router[W]) checkSegmentLimits(segments []string, length int) error {
+	if customSeparator(r.conf.Separator) {
+		// paths with a custom separator do not start with the separator
+		length--
+	}
+	if len(segments) == 0 {
+		// the empty path is "/" with the default separator
+		return r.checkPathLimits(length+1, 1)
+	}
+	return r.checkPathLimits(length, len(segments))
+}
+
+// matchSegments looks up the segments in all trees skipping the disabled
+// routes, see matchRoutesParams.
+func (r *Router[W]) matchSegments(t *table[W], segments []string) []match[W] {
+	var matches []match[W]
+	trailingSlash := len(segments) > 1 && segments[len(segments)-1] == ""
+	for _, tree := range t.trees {
+		path := newSegmentPath(segments, r.segmentSeparator())
+		leaf, ps := tree.getValueSegments(&path, r.getParams)
+		if leaf == nil || !leaf.route.enabled() || leaf.route.emptyTrailingDefault(trailingSlash) {
+			r.putParams(ps)
+			continue
+		}
+		matches = append(matches, match[W]{route: leaf.route, handle: leaf.handle, ps: ps})
+	}
+	sortMatches(matches)
+	return matches
+}
+
+// segmentPath is a cursor over a path given as segments in the form used by
+// the trees: "/" followed by the segments joined with "/" and '/' in the
+// segments swapped with the separator, see toTree.
+type segmentPath struct {
+	segments []string
+	sep      byte
+	// seg is the index of the current segment.
+	seg int
+	// off is the offset in the current segment, where 0 is the '/' before it.
+	off int
+	// n is the remaining length of the path.
+	n int
+}
+
+// rootSegments are the segments of the empty path "/".
+var rootSegments = []string{""}
+
+// newSegmentPath builds a cursor at the start of the segments.
+func newSegmentPath(segments []string, sep byte) segmentPath {
+	if len(segments) == 0 {
+		segments = rootSegments
+	}
+	p := segmentPath{segments: segments, sep: sep}
+	for _, seg := range segments {
+		p.n += len(seg) + 1
+	}
+	return p
+}
+
+// byteAt returns the byte at the cursor. Must not be called at the end.
+func (p *segmentPath) byteAt() byte {
+	if p.off == 0 {
+		return '/'
+	}
+	c := p.segments[p.seg][p.off-1]
+	if c == '/' {
+		return p.sep
+	}
+	return c
+}
+
+// advance moves the cursor by n bytes.
+func (p *segmentPath) advance(n int) {
+	p.n -= n
+	for n != 0 {
+		rem := len(p.segments[p.seg]) + 1 - p.off
+		if n < rem {
+			p.off += n
+			return
+		}
+		n -= rem
+		p.seg++
+		p.off = 0
+	}
+}
+
+// hasPrefix checks if the remaining path starts with prefix.
+func (p *segmentPath) hasPrefix(prefix string) bool {
+	if p.n < len(prefix) {
+		return false
+	}
+	c := *p
+	for i := 0; i < len(prefix); i++ {
+		if c.byteAt() != prefix[i] {
+			return false
+		}
+		c.advance(1)
+	}
+	return true
+}
+
+// equals checks if the remaining path is s.
+func (p *segmentPath) equals(s string) bool {
+	return p.n == len(s) && p.hasPrefix(s)
+}
+
+// segmentEnd returns the length of the rest of the current segment.
+func (p *segmentPath) segmentEnd() int {
+	if p.off == 0 {
+		return 0
+	}
+	return len(p.segments[p.seg]) + 1 - p.off
+}
+
+// value returns the next n bytes of the current segment, see segmentEnd.
+func (p *segmentPath) value(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return swapSeparator(p.segments[p.seg][p.off-1:p.off-1+n], p.sep)
+}
+
+// String joins the remaining path.
+func (p *segmentPath) String() string {
+	var sb strings.Builder
+	sb.Grow(p.n)
+	for i := p.seg; i < len(p.segments); i++ {
+		seg := p.segments[i]
+		if i != p.seg || p.off == 0 {
+			sb.WriteByte('/')
+		} else {
+			seg = seg[p.off-1:]
+		}
+		sb.WriteString(swapSeparator(seg, p.sep))
+	}
+	return sb.String()
+}
+
+// getValueSegments returns the node holding the handle for the path given as
+// segments, see getValue. No trailing slash redirect is recommended.
+func (n *node[W]) getValueSegments(path *segmentPath, params func() *Params) (leaf *node[W], ps *Params) {
+walk: // Outer loop for walking the tree
+	for {
+		prefix := n.path
+		if path.n > len(prefix) {
+			if !path.hasPrefix(prefix) {
+				return
+			}
+			path.advance(len(prefix))
+
+			if !n.wildChild {
+				idxc := path.byteAt()
+				for i, c := range []byte(n.indices) {
+					if c == idxc {
+						n = n.children[i]
+						continue walk
+					}
+				}
+				return
+			}
+
+			n = n.children[0]
+			switch n.nType {
+			case param:
+				end := path.segmentEnd()
+				if params != nil {
+					if ps == nil {
+						ps = params()
+					}
+					i := len(*ps)
+					*ps = (*ps)[:i+1]
+					(*ps)[i] = Param{
+						Key:   n.path[1:],
+						Value: path.value(end),
+					}
+				}
+
+				if end < path.n {
+					if len(n.children) > 0 {
+						path.advance(end)
+						n = n.children[0]
+						continue walk
+					}
+					return
+				}
+				if n.handle != nil {
+					leaf = n
+				}
+				return
+
+			case catchAll:
+				if params != nil {
+					if ps == nil {
+						ps = params()
+					}
+					i := len(*ps)
+					*ps = (*ps)[:i+1]
+					(*ps)[i] = Param{
+						Key:   n.path[2:],
+						Value: path.String(),
+					}
+				}
+				if n.handle != nil {
+					leaf = n
+				}
+				return
+
+			default:
+				panic("invalid node type")
+			}
+		}
+		if path.equals(prefix) && n.handle != nil {
+			leaf = n
+		}
+		return
+	}
+}
@@ -0,0 +1,123 @@
+package pathrouter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRouterSegments(t *testing.T) {
+	type result struct {
+		pattern string
+		params  Params
+	}
+	tests := []struct {
+		segments []string
+		slash    result
+		dot      result
+	}{{
+		segments: []string{"orders", "42", "items"},
+		slash:    result{"/orders/:id/*rest", Params{{"id", "42"}, {"rest", "/items"}}},
+		dot:      result{"orders.:id.*rest", Params{{"id", "42"}, {"rest", ".items"}}},
+	}, {
+		segments: []string{"orders", "4/2", "items"},
+		dot:      result{"orders.:id.*rest", Params{{"id", "4/2"}, {"rest", ".items"}}},
+	}, {
+		segments: []string{"orders", "4.2", "items"},
+		slash:    result{"/orders/:id/*rest", Params{{"id", "4.2"}, {"rest", "/items"}}},
+	}, {
+		segments: nil,
+		slash:    result{"/", nil},
+		dot:      result{"", nil},
+	}, {
+		segments: []string{"missing"},
+	}}
+
+	for _, sep := range []byte{'/', '.'} {
+		router := NewWithConfig(RouterConfig[struct{}]{Separator: sep})
+		router.AddHandlerSegments([]string{"orders", ":id", "*rest"}, fakeHandler("orders"))
+		router.AddHandlerSegments(nil, fakeHandler("root"))
+
+		for _, test := range tests {
+			want := test.slash
+			if sep == '.' {
+				want = test.dot
+			}
+			res := router.LookupSegments(test.segments)
+			if res.Pattern != want.pattern || !reflect.DeepEqual(res.Params, want.params) {
+				t.Errorf("sep %q segments %v: expected %q %v but got %q %v", sep, test.segments, want.pattern, want.params, res.Pattern, res.Params)
+			}
+		}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("sep %q: expected panic for separator in segment", sep)
+				}
+			}()
+			router.AddHandlerSegments([]string{"a" + string(sep) + "b"}, fakeHandler(""))
+		}()
+	}
+}
+
+func TestRouterLookupSegmentsLiteral(t *testing.T) {
+	router := NewWithConfig(RouterConfig[struct{}]{UnescapePath: true})
+	router.AddHandler("/files/:name", fakeHandler("file"))
+
+	res := router.LookupSegments([]string{"files", "a%20b"})
+	if res.Pattern != "/files/:name" || res.Params.ByName("name") != "a%20b" {
+		t.Errorf("expected the segment to be matched literally, got %q %v", res.Pattern, res.Params)
+	}
+	if res := router.Lookup("/files/a%20b"); res.Params.ByName("name") != "a b" {
+		t.Errorf("expected Lookup to unescape the path, got %v", res.Params)
+	}
+}
+
+func TestRouterLookupSegmentsLookup(t *testing.T) {
+	patterns := []string{
+		"/",
+		"/users",
+		"/users/:id",
+		"/users/:id/posts/:post",
+		"/files/*path",
+		"/static/path/",
+		"/prefix_:name",
+	}
+	paths := [][]string{
+		nil,
+		{""},
+		{"users"},
+		{"users", ""},
+		{"users", "42"},
+		{"users", "42", ""},
+		{"users", "42", "posts", "7"},
+		{"users", "42", "posts"},
+		{"files"},
+		{"files", ""},
+		{"files", "a", "b.txt"},
+		{"static", "path"},
+		{"static", "path", ""},
+		{"prefix_gopher"},
+		{"prefix_"},
+		{"us"},
+		{"missing", "path"},
+	}
+	for _, sep := range []byte{'/', '.'} {
+		router := NewWithConfig(RouterConfig[struct{}]{Separator: sep, RedirectTrailingSlash: true})
+		for _, pattern := range patterns {
+			if sep != '/' {
+				pattern = strings.ReplaceAll(strings.TrimPrefix(pattern, "/"), "/", string(sep))
+			}
+			router.AddHandler(pattern, fakeHandler(pattern))
+		}
+		for _, segments := range paths {
+			path, _ := router.segmentsPath(segments)
+			want := router.Lookup(path)
+			got := router.LookupSegments(segments)
+			if got.Pattern != want.Pattern || !reflect.DeepEqual(got.Params, want.Params) ||
+				got.TSR != want.TSR || got.RedirectPath != want.RedirectPath {
+				t.Errorf("sep %q segments %q: expected %+v but got %+v", sep, segments, want, got)
+			}
+		}
+	}
+}
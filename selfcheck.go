@@ -0,0 +1,134 @@
+package pathrouter
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Problem is a problem with a registered route found by SelfCheck.
+type Problem struct {
+	// Pattern is the pattern of the route.
+	Pattern string
+	// Path is the witness path used to check the route, if any.
+	Path string
+	// Message describes the problem.
+	Message string
+}
+
+// String formats the problem as a string.
+func (p Problem) String() string {
+	if p.Path == "" {
+		return p.Pattern + ": " + p.Message
+	}
+	return p.Pattern + ": " + p.Message + " (path " + p.Path + ")"
+}
+
+// witnessParams returns the param values used to build a witness path.
+func witnessParams(parts []patternPart) Params {
+	var ps Params
+	for _, part := range parts {
+		switch part.kind {
+		case param:
+			ps = append(ps, Param{Key: part.value, Value: "~" + part.value})
+		case catchAll:
+			ps = append(ps, Param{Key: part.value, Value: "/~" + part.value})
+		}
+	}
+	return ps
+}
+
+// SelfCheck exercises every registered route with a synthesized witness path
+// and returns any problems found. Intended as a sanity check at startup.
+//
+// For each route a witness path is built from the pattern with BuildPath and
+// looked up. Problems are reported if the witness path is changed by
+// CleanPath, if the route is not reachable by the witness path or only
+// reachable with a trailing slash or fixed path correction, or if the params
+// do not round-trip through BuildPath.
+func (r *Router[W]) SelfCheck() []Problem {
+	var problems []Problem
+	t := r.load()
+	for _, rt := range sortRoutes(t.routes) {
+		problems = append(problems, r.checkRoute(t, rt)...)
+	}
+	return problems
+}
+
+// checkRoute checks a single route, see SelfCheck.
+func (r *Router[W]) checkRoute(t *table[W], rt *route[W]) []Problem {
+	var problems []Problem
+	report := func(path, msg string) {
+		problems = append(problems, Problem{Pattern: rt.pattern, Path: fromTree(path, r.conf.Separator), Message: msg})
+	}
+
+	want := witnessParams(rt.parts)
+	path, err := BuildPath(rt.path, want)
+	if err != nil {
+		report("", "cannot build witness path: "+err.Error())
+		return problems
+	}
+	if cleaned := CleanPath(path); cleaned != path {
+		report(path, "path is changed to "+cleaned+" by CleanPath")
+	}
+
+	// disabled routes are checked as if enabled
+	matches, tsr := r.matchRoutes(t, path, true)
+	defer r.putMatches(matches)
+
+	var matched *match[W]
+	for i := range matches {
+		if matches[i].route == rt {
+			matched = &matches[i]
+			break
+		}
+	}
+	if matched == nil {
+		switch {
+		case len(matches) != 0:
+			report(path, "unreachable: path matches "+matches[0].route.pattern)
+		case tsr && r.trailingSlash() != TrailingSlashStrict:
+			report(path, "only reachable with a trailing slash redirect")
+		default:
+			if _, found := t.findCaseInsensitivePath(CleanPath(path), r.trailingSlash() != TrailingSlashStrict); found && r.conf.RedirectFixedPath {
+				report(path, "only reachable with a fixed path redirect")
+			} else {
+				report(path, "unreachable")
+			}
+		}
+		return problems
+	}
+	if matched != &matches[0] && !r.conf.Fallthrough {
+		report(path, "shadowed by "+matches[0].route.pattern)
+	}
+
+	var got Params
+	if matched.ps != nil {
+		got = *matched.ps
+	}
+	if len(got) != 0 || len(want) != 0 {
+		if !reflect.DeepEqual(got, want) {
+			report(path, "params mismatch: expected "+formatParams(want)+" but got "+formatParams(got))
+			return problems
+		}
+	}
+	if rebuilt, err := BuildPath(rt.path, got); err != nil {
+		report(path, "params do not round-trip: "+err.Error())
+	} else if rebuilt != path {
+		report(path, "params do not round-trip: built "+rebuilt)
+	}
+	return problems
+}
+
+// formatParams formats params as key=value pairs.
+func formatParams(ps Params) string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i, p := range ps {
+		if i != 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(p.Key + "=" + p.Value)
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
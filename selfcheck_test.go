@@ -0,0 +1,35 @@
+package pathrouter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRouterSelfCheck(t *testing.T) {
+	router := New[struct{}]()
+	for _, route := range []string{
+		"/",
+		"/user/:name",
+		"/user/:name/posts/:post",
+		"/files/*filepath",
+		"/dir/{$}",
+		"/src:version/main.go",
+	} {
+		router.AddHandler(route, fakeHandler(route))
+	}
+	if problems := router.SelfCheck(); len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+
+	router.AddHandler("/a//b", fakeHandler("/a//b"))
+	router.AddHandler("/x/./y", fakeHandler("/x/./y"))
+	problems := router.SelfCheck()
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems but got: %v", problems)
+	}
+	for i, pattern := range []string{"/a//b", "/x/./y"} {
+		if problems[i].Pattern != pattern || !strings.Contains(problems[i].Message, "CleanPath") {
+			t.Errorf("unexpected problem: %v", problems[i])
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package pathrouter
+
+import "strings"
+
+// Separators
+//
+// The trees always use '/' to separate path segments. If a different separator
+// is configured with RouterConfig.Separator, the separator and '/' are swapped
+// in the patterns and paths before they are inserted into and looked up in the
+// trees, and swapped back in the patterns, paths and params passed to the
+// caller. Patterns and paths with a custom separator do not start with the
+// separator: the leading '/' of the trees is added and removed when swapping.
+
+// customSeparator checks if sep is a separator other than the default '/'.
+func customSeparator(sep byte) bool {
+	return sep != 0 && sep != '/'
+}
+
+// swapSeparator swaps the separator with '/' in s.
+func swapSeparator(s string, sep byte) string {
+	if sep == '/' || strings.IndexByte(s, '/') < 0 && strings.IndexByte(s, sep) < 0 {
+		return s
+	}
+	b := []byte(s)
+	for i, c := range b {
+		switch c {
+		case sep:
+			b[i] = '/'
+		case '/':
+			b[i] = sep
+		}
+	}
+	return string(b)
+}
+
+// toTree converts a pattern or path with the separator to the form used by the
+// trees.
+func toTree(s string, sep byte) string {
+	if !customSeparator(sep) {
+		return s
+	}
+	return "/" + swapSeparator(s, sep)
+}
+
+// fromTree converts a pattern or path in the form used by the trees to the
+// separator.
+func fromTree(s string, sep byte) string {
+	if !customSeparator(sep) {
+		return s
+	}
+	return swapSeparator(strings.TrimPrefix(s, "/"), sep)
+}
+
+// paramsFromTree converts the param values to the separator in place.
+func paramsFromTree(ps Params, sep byte) {
+	if !customSeparator(sep) {
+		return
+	}
+	for i := range ps {
+		ps[i].Value = swapSeparator(ps[i].Value, sep)
+	}
+}
@@ -0,0 +1,79 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRouterSeparator(t *testing.T) {
+	var gotPath string
+	var gotParams Params
+	handler := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		gotPath, gotParams = reqPath, append(Params(nil), p...)
+		return true, nil
+	}
+
+	tests := []struct {
+		sep     byte
+		pattern string
+		path    string
+		params  Params
+	}{
+		{'.', "orders.created.:id", "orders.created.42", Params{{"id", "42"}}},
+		{'.', "files.*rest", "files.a/b.c", Params{{"rest", ".a/b.c"}}},
+		{'.', "status", "status", nil},
+		{':', "deploy:{env}:{args...}", "deploy:prod:a:b", Params{{"env", "prod"}, {"args", ":a:b"}}},
+		{'\\', `Users\:name\*file`, `Users\gopher\go\main.go`, Params{{"name", "gopher"}, {"file", `\go\main.go`}}},
+	}
+	for _, test := range tests {
+		conf := RouterConfig[struct{}]{Separator: test.sep}
+		router := NewWithConfig(conf)
+		router.AddHandler(test.pattern, handler)
+
+		gotPath, gotParams = "", nil
+		if found, err := router.Serve(context.Background(), test.path, struct{}{}); !found || err != nil {
+			t.Errorf("pattern %s: expected path %s to be found, got found=%v err=%v", test.pattern, test.path, found, err)
+			continue
+		}
+		if gotPath != test.path || !reflect.DeepEqual(gotParams, test.params) {
+			t.Errorf("pattern %s: expected %q %v but got %q %v", test.pattern, test.path, test.params, gotPath, gotParams)
+		}
+
+		res := router.Lookup(test.path)
+		if !res.Found() || res.Pattern != router.Routes()[0].Pattern || !reflect.DeepEqual(res.Params, test.params) {
+			t.Errorf("pattern %s: unexpected lookup result %+v", test.pattern, res)
+		}
+		if _, ps, _ := router.LookupPath(test.path); !reflect.DeepEqual(ps, test.params) {
+			t.Errorf("pattern %s: expected LookupPath params %v but got %v", test.pattern, test.params, ps)
+		}
+	}
+}
+
+func TestRouterSeparatorPatterns(t *testing.T) {
+	router := NewWithConfig(RouterConfig[struct{}]{Separator: '.', RedirectTrailingSlash: true})
+	router.AddHandler("orders.:id", fakeHandler("orders.:id"))
+	router.AddHandler("a/b.c", fakeHandler("a/b.c"))
+	router.AddHandler("dir.{$}", fakeHandler("dir.{$}"))
+
+	var patterns []string
+	for _, def := range router.Routes() {
+		patterns = append(patterns, def.Pattern)
+	}
+	if want := []string{"orders.:id", "a/b.c", "dir.{$}"}; !reflect.DeepEqual(patterns, want) {
+		t.Errorf("expected patterns %v but got %v", want, patterns)
+	}
+
+	if res := router.Lookup("a/b.c"); !res.Found() {
+		t.Error("expected a/b.c to be found")
+	}
+	if res := router.Lookup("a.b.c"); res.Found() {
+		t.Error("expected a.b.c not to be found")
+	}
+	if res := router.Lookup("orders.42."); res.Found() || res.RedirectPath != "orders.42" {
+		t.Errorf("expected redirect to orders.42, got %+v", res)
+	}
+	if problems := router.SelfCheck(); len(problems) != 0 {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+}
@@ -0,0 +1,158 @@
+package pathrouter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSourceInterval is the default interval between syncs with a
+// RouteSource which does not notify changes.
+const DefaultSourceInterval = 10 * time.Second
+
+// RouteSource provides the complete set of routes of a router, for example
+// backed by a database, etcd or a Kubernetes resource.
+//
+// Sources notifying changes implement WatchableRouteSource.
+type RouteSource[W any] interface {
+	// Snapshot returns the current routes.
+	Snapshot() ([]RouteDef[W], error)
+}
+
+// WatchableRouteSource is a RouteSource notifying changes to the routes.
+type WatchableRouteSource[W any] interface {
+	RouteSource[W]
+	// Changes returns a channel receiving a value when the routes change.
+	// Closing the channel stops the SourceController.
+	Changes() <-chan struct{}
+}
+
+// SourceControllerConfig is the configuration for a SourceController.
+type SourceControllerConfig struct {
+	// Interval is the interval between syncs.
+	//
+	// Defaults to DefaultSourceInterval if zero and the source does not
+	// implement WatchableRouteSource. If the source notifies changes the
+	// router is only synced periodically if set.
+	Interval time.Duration
+
+	// OnSync is called with the route table version after the router was
+	// synced, if set.
+	OnSync func(version uint64)
+	// OnError is called by Run with the error if the router could not be
+	// synced, if set. The router keeps the previous routes.
+	OnError func(err error)
+}
+
+// SourceController keeps the routes of a router in sync with a RouteSource.
+//
+// The routes of the router are replaced by the snapshot of the source on each
+// sync: the router should not contain routes registered by other means.
+type SourceController[W any] struct {
+	conf   SourceControllerConfig
+	router *Router[W]
+	source RouteSource[W]
+
+	// mtx serializes the syncs
+	mtx sync.Mutex
+}
+
+// NewSourceController constructs a new SourceController syncing the router
+// with the source.
+func NewSourceController[W any](router *Router[W], source RouteSource[W], conf SourceControllerConfig) *SourceController[W] {
+	if _, ok := source.(WatchableRouteSource[W]); !ok && conf.Interval <= 0 {
+		conf.Interval = DefaultSourceInterval
+	}
+	return &SourceController[W]{conf: conf, router: router, source: source}
+}
+
+// Sync replaces the routes of the router with the snapshot of the source.
+//
+// The snapshot is validated before changing the route table: if any of the
+// routes are invalid or conflict an error is returned and the router is not
+// changed. OnSync is called on success.
+func (c *SourceController[W]) Sync() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	defs, err := c.source.Snapshot()
+	if err != nil {
+		return errors.Wrap(err, "route source snapshot")
+	}
+	version, err := c.router.ReplaceAll(defs)
+	if err != nil {
+		return err
+	}
+	if c.conf.OnSync != nil {
+		c.conf.OnSync(version)
+	}
+	return nil
+}
+
+// Run syncs the router and syncs it again on each change notified by the
+// source and after each interval until the context is canceled.
+//
+// Returns the error if the initial sync fails. Later failures are passed to
+// OnError. Returns nil if the changes channel of the source is closed.
+func (c *SourceController[W]) Run(ctx context.Context) error {
+	if err := c.Sync(); err != nil {
+		return err
+	}
+
+	var changes <-chan struct{}
+	if ws, ok := c.source.(WatchableRouteSource[W]); ok {
+		changes = ws.Changes()
+	}
+	var tick <-chan time.Time
+	if c.conf.Interval > 0 {
+		ticker := time.NewTicker(c.conf.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case _, ok := <-changes:
+			if !ok {
+				return nil
+			}
+		case <-tick:
+		}
+		if err := c.Sync(); err != nil && c.conf.OnError != nil {
+			c.conf.OnError(err)
+		}
+	}
+}
+
+// ReplaceAll replaces all routes with the routes in the definitions,
+// registered in order: the routes keep the order of the definitions, see
+// Routes. Returns the new route table version.
+//
+// The definitions are validated before changing the route table: if any of the
+// routes are invalid or conflict an error is returned and the router is not
+// changed.
+func (r *Router[W]) ReplaceAll(defs []RouteDef[W]) (uint64, error) {
+	var version uint64
+	err := r.update(func(old *table[W]) (*table[W], error) {
+		version = old.version + 1
+		routes := make(map[string]*route[W], len(defs))
+		for i, def := range defs {
+			rt, err := newRouteFromDef(def, r.conf.UnicodeForm, r.conf.Separator)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := routes[rt.pattern]; exists {
+				return nil, errors.Errorf("duplicate route '%s'", rt.pattern)
+			}
+			rt.version = old.version + 1 + uint64(i)
+			routes[rt.pattern] = rt
+			version = rt.version
+		}
+		return r.replaceRoutes(old, routes, version)
+	})
+	return version, err
+}
@@ -0,0 +1,149 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// testRouteSource is a WatchableRouteSource returning the configured routes.
+type testRouteSource struct {
+	mtx     sync.Mutex
+	defs    []RouteDef[struct{}]
+	err     error
+	changes chan struct{}
+}
+
+func (s *testRouteSource) Snapshot() ([]RouteDef[struct{}], error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.defs, s.err
+}
+
+func (s *testRouteSource) Changes() <-chan struct{} {
+	return s.changes
+}
+
+func (s *testRouteSource) set(defs []RouteDef[struct{}], err error) {
+	s.mtx.Lock()
+	s.defs, s.err = defs, err
+	s.mtx.Unlock()
+}
+
+func TestSourceController(t *testing.T) {
+	source := &testRouteSource{changes: make(chan struct{})}
+	source.set([]RouteDef[struct{}]{
+		{Pattern: "/user/:name", Handle: fakeHandler("user"), Name: "user"},
+		{Pattern: "/files/*filepath", Handle: fakeHandler("files")},
+	}, nil)
+
+	router := New[struct{}]()
+	router.AddHandler("/old", fakeHandler("old"))
+	synced := make(chan uint64, 1)
+	errs := make(chan error, 1)
+	ctrl := NewSourceController[struct{}](router, source, SourceControllerConfig{
+		OnSync:  func(version uint64) { synced <- version },
+		OnError: func(err error) { errs <- err },
+	})
+	if ctrl.conf.Interval != 0 {
+		t.Errorf("expected no polling for a watchable source, got %v", ctrl.conf.Interval)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(ctx) }()
+	if version := <-synced; version != router.Version() {
+		t.Errorf("expected synced version %d, got %d", router.Version(), version)
+	}
+	if patterns := router.RoutesWithPrefix("/"); !reflect.DeepEqual(patterns, []string{"/user/:name", "/files/*filepath"}) {
+		t.Errorf("unexpected routes after sync: %v", patterns)
+	}
+
+	// invalid snapshots keep the previous routes
+	version := router.Version()
+	for _, err := range []error{
+		errors.New("unavailable"),
+		nil,
+	} {
+		source.set([]RouteDef[struct{}]{
+			{Pattern: "/a", Handle: fakeHandler("a")},
+			{Pattern: "/a", Handle: fakeHandler("a")},
+		}, err)
+		source.changes <- struct{}{}
+		select {
+		case <-errs:
+		case <-synced:
+			t.Fatal("expected sync error")
+		}
+		if router.Version() != version {
+			t.Fatal("expected the router not to change")
+		}
+	}
+
+	source.set([]RouteDef[struct{}]{{Pattern: "/b", Handle: fakeHandler("b")}}, nil)
+	source.changes <- struct{}{}
+	<-synced
+	if patterns := router.RoutesWithPrefix("/"); !reflect.DeepEqual(patterns, []string{"/b"}) {
+		t.Errorf("unexpected routes after change: %v", patterns)
+	}
+	if delta := router.DeltaSince(version); !reflect.DeepEqual(delta.Removed, []string{"/files/*filepath", "/user/:name"}) {
+		t.Errorf("unexpected removed routes in delta: %v", delta.Removed)
+	}
+
+	close(source.changes)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected nil error after the changes closed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to return after the changes closed")
+	}
+}
+
+func TestSourceControllerPoll(t *testing.T) {
+	source := &testRouteSource{}
+	source.set([]RouteDef[struct{}]{{Pattern: "/a", Handle: fakeHandler("a")}}, nil)
+	router := New[struct{}]()
+	synced := make(chan uint64, 1)
+	ctrl := NewSourceController[struct{}](router, struct{ RouteSource[struct{}] }{source}, SourceControllerConfig{
+		Interval: time.Millisecond,
+		OnSync: func(version uint64) {
+			select {
+			case synced <- version:
+			default:
+			}
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(ctx) }()
+	<-synced
+	source.set([]RouteDef[struct{}]{{Pattern: "/b", Handle: fakeHandler("b")}}, nil)
+	deadline := time.After(5 * time.Second)
+	for !router.HasRoute("/b") {
+		select {
+		case <-synced:
+		case <-deadline:
+			t.Fatal("expected the router to be synced periodically")
+		}
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected the context error, got %v", err)
+	}
+
+	source.set(nil, errors.New("unavailable"))
+	if err := NewSourceController[struct{}](router, source, SourceControllerConfig{}).Run(context.Background()); err == nil {
+		t.Error("expected the initial sync error")
+	}
+	if ctrl := NewSourceController[struct{}](router, struct{ RouteSource[struct{}] }{source}, SourceControllerConfig{}); ctrl.conf.Interval != DefaultSourceInterval {
+		t.Errorf("expected the default interval, got %v", ctrl.conf.Interval)
+	}
+}
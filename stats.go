@@ -0,0 +1,57 @@
+package pathrouter
+
+// Stats contains statistics about the route table.
+type Stats struct {
+	// Routes is the number of registered routes.
+	Routes int
+	// Trees is the number of trees, see RouterConfig.Fallthrough.
+	Trees int
+	// Nodes is the total number of nodes in the trees.
+	Nodes int
+	// StaticNodes is the number of static nodes, including the tree roots.
+	StaticNodes int
+	// ParamNodes is the number of named param nodes.
+	ParamNodes int
+	// CatchAllNodes is the number of catch-all nodes.
+	CatchAllNodes int
+	// MaxDepth is the maximum depth of a node, the roots have depth 1.
+	MaxDepth int
+	// PathBytes is the total length of the path and index strings of the nodes.
+	PathBytes int
+	// MaxParams is the maximum number of params of a route.
+	MaxParams int
+}
+
+// Stats returns statistics about the route table.
+func (r *Router[W]) Stats() Stats {
+	t := r.load()
+	stats := Stats{
+		Routes:    len(t.routes),
+		Trees:     len(t.trees),
+		MaxParams: int(t.maxParams),
+	}
+	for _, tree := range t.trees {
+		tree.addStats(&stats, 1)
+	}
+	return stats
+}
+
+// addStats adds the node and its children to the stats.
+func (n *node[W]) addStats(stats *Stats, depth int) {
+	stats.Nodes++
+	switch n.nType {
+	case param:
+		stats.ParamNodes++
+	case catchAll:
+		stats.CatchAllNodes++
+	default:
+		stats.StaticNodes++
+	}
+	if depth > stats.MaxDepth {
+		stats.MaxDepth = depth
+	}
+	stats.PathBytes += len(n.path) + len(n.indices)
+	for _, child := range n.children {
+		child.addStats(stats, depth+1)
+	}
+}
@@ -0,0 +1,37 @@
+package pathrouter
+
+import "testing"
+
+func TestRouterStats(t *testing.T) {
+	router := New[struct{}]()
+	if stats := router.Stats(); stats != (Stats{}) {
+		t.Fatalf("expected empty stats but got %+v", stats)
+	}
+
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	router.AddHandler("/users", fakeHandler("/users"))
+	router.AddHandler("/src/*filepath", fakeHandler("/src/*filepath"))
+
+	// /
+	// ├── user
+	// │   ├── /
+	// │   │   └── :name
+	// │   └── s
+	// └── src
+	//     └── (catch-all)
+	//         └── /*filepath
+	want := Stats{
+		Routes:        3,
+		Trees:         1,
+		Nodes:         8,
+		StaticNodes:   5,
+		ParamNodes:    1,
+		CatchAllNodes: 2,
+		MaxDepth:      4,
+		PathBytes:     len("/us") + len("user/s") + len("/") + len(":name") + len("s") + len("src/") + len("/*filepath"),
+		MaxParams:     1,
+	}
+	if stats := router.Stats(); stats != want {
+		t.Errorf("expected stats %+v but got %+v", want, stats)
+	}
+}
@@ -0,0 +1,244 @@
+package pathrouter
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrVersionMismatch is returned if a delta does not apply to the current version
+// or does not advance it.
+var ErrVersionMismatch = errors.New("route delta version mismatch")
+
+// RouteDef is a route definition.
+type RouteDef[W any] struct {
+	// Pattern is the route pattern.
+	Pattern string
+	// Handle is the handle for the pattern.
+	Handle Handle[W]
+	// Name is the handler name, if any, see WithName.
+	Name string
+	// Metadata contains the route metadata, if any, see WithMetadata.
+	Metadata map[string]any
+	// Tags contains the route tags, if any, see WithTags.
+	Tags []string
+	// Defaults contains the default param values by name, if any, see
+	// WithDefault.
+	Defaults map[string]string
+	// Timeout is the timeout of the handle, if any, see WithTimeout.
+	Timeout time.Duration
+}
+
+// routeDef returns the definition of the route.
+func (rt *route[W]) routeDef() RouteDef[W] {
+	return RouteDef[W]{Pattern: rt.pattern, Handle: rt.handle, Name: rt.name, Metadata: rt.metadata, Tags: rt.tags, Defaults: rt.defaults, Timeout: rt.timeout}
+}
+
+// newRouteFromDef parses the pattern of the definition and constructs a new route.
+func newRouteFromDef[W any](def RouteDef[W], form UnicodeForm, sep byte) (*route[W], error) {
+	if def.Handle == nil {
+		return nil, errors.Errorf("nil handle for pattern '%s'", def.Pattern)
+	}
+	rt, err := newRoute(form.Normalize(def.Pattern), def.Handle, sep)
+	if err != nil {
+		return nil, err
+	}
+	rt.name, rt.metadata, rt.tags = def.Name, def.Metadata, def.Tags
+	rt.timeout = def.Timeout
+	if err := rt.setDefaults(def.Defaults); err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+// RouteDelta contains the changes to a route table between two versions.
+//
+// A delta with FromVersion zero is a full snapshot of the route table.
+type RouteDelta[W any] struct {
+	// FromVersion is the version the delta applies to.
+	FromVersion uint64
+	// ToVersion is the version after applying the delta.
+	ToVersion uint64
+	// Added contains the routes added or replaced since FromVersion.
+	Added []RouteDef[W]
+	// Removed contains the patterns removed since FromVersion.
+	Removed []string
+}
+
+// IsEmpty checks if the delta contains no changes.
+func (d *RouteDelta[W]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// Version returns the version of the route table.
+//
+// The version is incremented each time a route is added and set to the
+// ToVersion of a delta when it is applied.
+func (r *Router[W]) Version() uint64 {
+	return r.load().version
+}
+
+// DeltaSince returns the changes to the route table since the given version.
+//
+// If version is zero or newer than the current version, returns a full
+// snapshot of the route table.
+func (r *Router[W]) DeltaSince(version uint64) RouteDelta[W] {
+	t := r.load()
+	if version > t.version {
+		version = 0
+	}
+	delta := RouteDelta[W]{FromVersion: version, ToVersion: t.version}
+	for _, rt := range sortRoutes(t.routes) {
+		if rt.version > version {
+			delta.Added = append(delta.Added, rt.routeDef())
+		}
+	}
+	if version != 0 {
+		for pattern, removedAt := range t.removed {
+			if removedAt > version {
+				delta.Removed = append(delta.Removed, pattern)
+			}
+		}
+		sort.Strings(delta.Removed)
+	}
+	return delta
+}
+
+// ApplyDelta applies the changes from a delta produced by DeltaSince.
+//
+// The delta must apply to the current version unless it is a full snapshot,
+// and ToVersion must be newer than the current version: otherwise
+// ErrVersionMismatch is returned.
+// The delta is validated before changing the route table: if any of the routes
+// conflict an error is returned and the router is not changed.
+func (r *Router[W]) ApplyDelta(delta RouteDelta[W]) error {
+	return r.update(func(old *table[W]) (*table[W], error) {
+		return r.applyDelta(old, delta)
+	})
+}
+
+// applyDelta returns a new table with the delta applied to the table.
+func (r *Router[W]) applyDelta(old *table[W], delta RouteDelta[W]) (*table[W], error) {
+	if delta.FromVersion != 0 && delta.FromVersion != old.version {
+		return nil, errors.Wrapf(
+			ErrVersionMismatch,
+			"delta from version %d cannot apply to version %d",
+			delta.FromVersion,
+			old.version,
+		)
+	}
+	// FromVersion is zero or the current version
+	if delta.ToVersion <= old.version {
+		return nil, errors.Wrapf(
+			ErrVersionMismatch,
+			"delta to version %d is not newer than version %d",
+			delta.ToVersion,
+			old.version,
+		)
+	}
+
+	routes := make(map[string]*route[W], len(old.routes)+len(delta.Added))
+	if delta.FromVersion != 0 {
+		for pattern, rt := range old.routes {
+			routes[pattern] = rt
+		}
+	}
+	for _, pattern := range delta.Removed {
+		rt, err := newRoute[W](r.conf.UnicodeForm.Normalize(pattern), nil, r.conf.Separator)
+		if err != nil {
+			return nil, err
+		}
+		delete(routes, rt.pattern)
+	}
+	for _, def := range delta.Added {
+		rt, err := newRouteFromDef(def, r.conf.UnicodeForm, r.conf.Separator)
+		if err != nil {
+			return nil, err
+		}
+		rt.version = delta.ToVersion
+		routes[rt.pattern] = rt
+	}
+
+	return r.replaceRoutes(old, routes, delta.ToVersion)
+}
+
+// replaceRoutes returns a new table with the routes replacing the routes of
+// the old table at the given version. Routes missing from routes are recorded
+// as removed at the version.
+func (r *Router[W]) replaceRoutes(old *table[W], routes map[string]*route[W], version uint64) (*table[W], error) {
+	trees, maxParams, err := buildTrees(routes, r.conf.Fallthrough)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	removed := make(map[string]uint64, len(old.removed))
+	for pattern, removedAt := range old.removed {
+		removed[pattern] = removedAt
+	}
+	for pattern := range old.routes {
+		if _, ok := routes[pattern]; !ok {
+			removed[pattern] = version
+			changed = append(changed, pattern)
+		}
+	}
+	for pattern, rt := range routes {
+		delete(removed, pattern)
+		if old.routes[pattern] != rt {
+			changed = append(changed, pattern)
+		}
+	}
+
+	return &table[W]{
+		trees:     trees,
+		maxParams: maxParams,
+		routes:    routes,
+		static:    staticRoutes(routes),
+		removed:   removed,
+		version:   version,
+		changed:   changed,
+		notFound:  old.notFound,
+	}, nil
+}
+
+// sortRoutes sorts the routes by version then by pattern.
+func sortRoutes[W any](routes map[string]*route[W]) []*route[W] {
+	out := make([]*route[W], 0, len(routes))
+	for _, rt := range routes {
+		out = append(out, rt)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].version != out[j].version {
+			return out[i].version < out[j].version
+		}
+		return out[i].pattern < out[j].pattern
+	})
+	return out
+}
+
+// buildTrees builds the trees for a set of routes.
+// If layered is set, overlapping routes are inserted into additional trees.
+// Returns the trees and the maximum number of params.
+func buildTrees[W any](routes map[string]*route[W], layered bool) (trees []*node[W], maxParams uint16, err error) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			trees, maxParams, err = nil, 0, errors.Errorf("%v", rerr)
+		}
+	}()
+
+	for _, rt := range sortRoutes(routes) {
+		if layered {
+			trees = insertRouteLayered(trees, rt)
+		} else {
+			if len(trees) == 0 {
+				trees = []*node[W]{new(node[W])}
+			}
+			trees[0].insertRoute(rt)
+		}
+		if paramsCount := countParams(rt.path); paramsCount > maxParams {
+			maxParams = paramsCount
+		}
+	}
+	return trees, maxParams, nil
+}
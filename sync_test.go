@@ -0,0 +1,158 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestRouterDeltaSync(t *testing.T) {
+	ctx := context.Background()
+	control := New[struct{}]()
+	edge := New[struct{}]()
+
+	syncEdge := func() {
+		t.Helper()
+		delta := control.DeltaSince(edge.Version())
+		if err := edge.ApplyDelta(delta); err != nil {
+			t.Fatal(err.Error())
+		}
+		if edge.Version() != control.Version() {
+			t.Fatalf("expected version %d but got %d", control.Version(), edge.Version())
+		}
+	}
+	expect := func(path, route string) {
+		t.Helper()
+		fakeHandlerValue = ""
+		found, err := edge.Serve(ctx, path, struct{}{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if found != (route != "") || fakeHandlerValue != route {
+			t.Errorf("path %s: expected route %q but got %q", path, route, fakeHandlerValue)
+		}
+	}
+
+	control.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	control.AddHandler("/dir/{$}", fakeHandler("/dir/{$}"))
+	syncEdge()
+	expect("/user/gopher", "/user/:name")
+	expect("/dir/", "/dir/{$}")
+	expect("/dir", "")
+
+	v := control.Version()
+	control.AddHandler("/files/*filepath", fakeHandler("/files/*filepath"))
+	delta := control.DeltaSince(v)
+	if delta.FromVersion != v || len(delta.Added) != 1 || delta.Added[0].Pattern != "/files/*filepath" {
+		t.Fatalf("unexpected delta: %+v", delta)
+	}
+	syncEdge()
+	expect("/files/a/b", "/files/*filepath")
+
+	// remove a route on the control plane
+	v = control.Version()
+	if err := control.ApplyDelta(RouteDelta[struct{}]{
+		FromVersion: v,
+		ToVersion:   v + 1,
+		Removed:     []string{"user/:name"},
+	}); err != nil {
+		t.Fatal(err.Error())
+	}
+	delta = control.DeltaSince(v)
+	if len(delta.Removed) != 1 || delta.Removed[0] != "/user/:name" || len(delta.Added) != 0 {
+		t.Fatalf("unexpected delta: %+v", delta)
+	}
+	syncEdge()
+	expect("/user/gopher", "")
+	expect("/files/a/b", "/files/*filepath")
+
+	// no changes
+	if delta := control.DeltaSince(control.Version()); !delta.IsEmpty() {
+		t.Fatalf("expected empty delta: %+v", delta)
+	}
+
+	// full snapshot
+	snapshot := control.DeltaSince(0)
+	if snapshot.FromVersion != 0 || len(snapshot.Added) != 2 {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+	fresh := New[struct{}]()
+	fresh.AddHandler("/stale", fakeHandler("/stale"))
+	if err := fresh.ApplyDelta(snapshot); err != nil {
+		t.Fatal(err.Error())
+	}
+	if h, _, _ := fresh.LookupPath("/stale"); h != nil {
+		t.Error("snapshot did not replace existing routes")
+	}
+	if h, _, _ := fresh.LookupPath("/dir/"); h == nil {
+		t.Error("snapshot did not add routes")
+	}
+}
+
+func TestRouterApplyDeltaInvalid(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/user/:name", fakeHandler("/user/:name"))
+	version := router.Version()
+
+	err := router.ApplyDelta(RouteDelta[struct{}]{FromVersion: version + 5, ToVersion: version + 6})
+	if !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected version mismatch, got %v", err)
+	}
+
+	// deltas must advance the version
+	for _, delta := range []RouteDelta[struct{}]{
+		{FromVersion: version, ToVersion: version},
+		{FromVersion: version, ToVersion: version - 1},
+		{FromVersion: 0, ToVersion: version},
+		{},
+	} {
+		if err := router.ApplyDelta(delta); !errors.Is(err, ErrVersionMismatch) {
+			t.Errorf("%d -> %d: expected version mismatch, got %v", delta.FromVersion, delta.ToVersion, err)
+		}
+	}
+
+	// conflicting wildcard: router must be unchanged
+	err = router.ApplyDelta(RouteDelta[struct{}]{
+		FromVersion: version,
+		ToVersion:   version + 1,
+		Added: []RouteDef[struct{}]{
+			{Pattern: "/user/:id/profile", Handle: fakeHandler("/user/:id/profile")},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected conflict error")
+	}
+	if router.Version() != version {
+		t.Errorf("version changed after failed delta: %d", router.Version())
+	}
+	if h, _, _ := router.LookupPath("/user/gopher"); h == nil {
+		t.Error("routes changed after failed delta")
+	}
+}
+
+func TestRouterDeltaTimeout(t *testing.T) {
+	control := New[struct{}]()
+	control.AddHandler("/slow", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return false, errors.New("expected deadline")
+		}
+		return true, nil
+	}, WithTimeout(time.Minute))
+
+	delta := control.DeltaSince(0)
+	if len(delta.Added) != 1 || delta.Added[0].Timeout != time.Minute {
+		t.Fatalf("expected the timeout in the delta, got %+v", delta.Added)
+	}
+	edge := New[struct{}]()
+	if err := edge.ApplyDelta(delta); err != nil {
+		t.Fatal(err.Error())
+	}
+	if found, err := edge.Serve(context.Background(), "/slow", struct{}{}); !found || err != nil {
+		t.Errorf("expected found, got found=%v err=%v", found, err)
+	}
+	if defs := edge.Routes(); len(defs) != 1 || defs[0].Timeout != time.Minute {
+		t.Errorf("expected the timeout in the routes, got %+v", defs)
+	}
+}
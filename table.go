@@ -0,0 +1,267 @@
+package pathrouter
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// table is a snapshot of the route table.
+//
+// A table is never modified after it is published with Router.update: changes
+// are made to a copy which then replaces the table. Lookups load the current
+// table and are not blocked by concurrent changes.
+type table[W any] struct {
+	// trees contains the route trees, see insertRouteLayered.
+	trees []*node[W]
+	// maxParams is the maximum number of params of the routes.
+	maxParams uint16
+	// routes contains the registered routes by pattern.
+	// The routes are shared between tables and must not be modified.
+	routes map[string]*route[W]
+	// static contains the routes without params by path.
+	// Used by the static fast path, see RouterConfig.StaticFastPath.
+	static map[string]*route[W]
+	// removed contains the version each removed pattern was removed at.
+	removed map[string]uint64
+	// version is incremented each time the routes change.
+	version uint64
+	// changed contains the patterns of the routes added, replaced or removed
+	// since the table was cloned, see routeHooks.notify. May contain
+	// duplicates.
+	changed []string
+	// notFound contains the NotFound handles of groups by prefix in the form
+	// used by the trees, see Group.NotFound.
+	notFound map[string]Handle[W]
+	// cache is the lookup cache of the table, see Router.lookupCache.
+	cache atomic.Pointer[lruCache[string, cachedMatch[W]]]
+	// misses is the not found cache of the table, see Router.missCache.
+	misses atomic.Pointer[lruCache[string, struct{}]]
+	// defaults contains the routes with a default for the param of the last
+	// segment, see trailingDefaults.
+	defaults atomic.Pointer[[]*route[W]]
+	// hash is the digest of the table or zero if not computed yet, see
+	// Router.Hash.
+	hash atomic.Uint64
+}
+
+// replaceRoute replaces the registered route with an updated copy with the same
+// pattern and bumps the version.
+func (t *table[W]) replaceRoute(existing, updated *route[W]) {
+	t.version++
+	updated.version = t.version
+	t.swapRoute(existing, updated)
+}
+
+// swapRoute replaces the registered route with an updated copy with the same
+// pattern without bumping the version, for changes not visible in the route
+// definition such as the handle.
+func (t *table[W]) swapRoute(existing, updated *route[W]) {
+	t.routes[existing.pattern] = updated
+	t.changed = append(t.changed, existing.pattern)
+	if t.static[existing.path] == existing {
+		t.static[existing.path] = updated
+	}
+	for _, tree := range t.trees {
+		if leaf := tree.findPath(existing.path); leaf != nil && leaf.route == existing {
+			leaf.handle = updated.handle
+			leaf.route = updated
+			break
+		}
+	}
+}
+
+// groupNotFound returns the NotFound handle of the group with the longest
+// prefix containing the path or nil if none.
+func (t *table[W]) groupNotFound(path string) Handle[W] {
+	var handle Handle[W]
+	longest := -1
+	for prefix, h := range t.notFound {
+		if len(prefix) > longest && (path == prefix || strings.HasPrefix(path, prefix+"/")) {
+			handle, longest = h, len(prefix)
+		}
+	}
+	return handle
+}
+
+// clone returns a copy of the table which can be modified.
+func (t *table[W]) clone() *table[W] {
+	out := &table[W]{
+		trees:     make([]*node[W], len(t.trees)),
+		maxParams: t.maxParams,
+		routes:    make(map[string]*route[W], len(t.routes)+1),
+		static:    make(map[string]*route[W], len(t.static)+1),
+		removed:   make(map[string]uint64, len(t.removed)),
+		version:   t.version,
+	}
+	if len(t.notFound) != 0 {
+		out.notFound = make(map[string]Handle[W], len(t.notFound))
+		for prefix, handle := range t.notFound {
+			out.notFound[prefix] = handle
+		}
+	}
+	for i, tree := range t.trees {
+		out.trees[i] = tree.clone()
+	}
+	for pattern, rt := range t.routes {
+		out.routes[pattern] = rt
+	}
+	for path, rt := range t.static {
+		out.static[path] = rt
+	}
+	for pattern, removedAt := range t.removed {
+		out.removed[pattern] = removedAt
+	}
+	return out
+}
+
+// empty returns a copy of the table without routes which can be modified,
+// keeping the version, the removed patterns and the group NotFound handles.
+// See removeMissing.
+func (t *table[W]) empty() *table[W] {
+	out := &table[W]{
+		routes:   make(map[string]*route[W]),
+		static:   make(map[string]*route[W]),
+		removed:  make(map[string]uint64, len(t.removed)+len(t.routes)),
+		version:  t.version,
+		notFound: t.notFound,
+	}
+	for pattern, removedAt := range t.removed {
+		out.removed[pattern] = removedAt
+	}
+	return out
+}
+
+// removeMissing records the routes of the old table missing from the table as
+// removed and bumps the version if any are missing.
+func (t *table[W]) removeMissing(old *table[W]) {
+	var removed bool
+	for pattern := range old.routes {
+		if _, ok := t.routes[pattern]; ok {
+			continue
+		}
+		if !removed {
+			t.version++
+			removed = true
+		}
+		t.removed[pattern] = t.version
+		t.changed = append(t.changed, pattern)
+	}
+}
+
+// addRoute inserts the route into the trees and bumps the version.
+// If layered is set, overlapping routes are inserted into additional trees.
+// Panics if the route conflicts with an existing route.
+func (t *table[W]) addRoute(rt *route[W], layered bool) {
+	if layered {
+		if _, exists := t.routes[rt.pattern]; exists {
+			panic("a handle is already registered for path '" + rt.pattern + "'")
+		}
+		t.trees = insertRouteLayered(t.trees, rt)
+	} else {
+		if len(t.trees) == 0 {
+			t.trees = []*node[W]{new(node[W])}
+		}
+		t.trees[0].insertRoute(rt)
+	}
+
+	t.version++
+	rt.version = t.version
+	t.routes[rt.pattern] = rt
+	t.changed = append(t.changed, rt.pattern)
+	delete(t.removed, rt.pattern)
+
+	paramsCount := countParams(rt.path)
+	if paramsCount > t.maxParams {
+		t.maxParams = paramsCount
+	}
+	if paramsCount == 0 {
+		t.static[rt.path] = rt
+	}
+}
+
+// tryAddRoute inserts the route as with addRoute returning any conflict as an
+// error. The table must be discarded if an error is returned.
+func (t *table[W]) tryAddRoute(rt *route[W], layered bool) (err error) {
+	defer recoverConflict(&err)
+	t.addRoute(rt, layered)
+	return nil
+}
+
+// recoverConflict sets err to the recovered panic of a conflicting insert.
+// Must be deferred.
+func recoverConflict(err *error) {
+	if rerr := recover(); rerr != nil {
+		*err = errors.Errorf("%v", rerr)
+	}
+}
+
+// staticRoutes returns the routes without params by path.
+func staticRoutes[W any](routes map[string]*route[W]) map[string]*route[W] {
+	static := make(map[string]*route[W])
+	for _, rt := range routes {
+		if countParams(rt.path) == 0 {
+			static[rt.path] = rt
+		}
+	}
+	return static
+}
+
+// load returns the current route table.
+func (r *Router[W]) load() *table[W] {
+	if t := r.tbl.Load(); t != nil {
+		return t
+	}
+	return &table[W]{}
+}
+
+// update replaces the route table with the table returned by fn.
+//
+// Changes are serialized: fn is called with the current table, which must not
+// be modified, see table.clone. If fn returns an error or panics the route
+// table is not changed. Returns ErrCompiled if the router is compiled.
+//
+// The route change hooks are called after the table is published, see
+// OnRouteAdded.
+func (r *Router[W]) update(fn func(old *table[W]) (*table[W], error)) error {
+	if r.compiled.Load() {
+		return ErrCompiled
+	}
+
+	old, t, err := r.swap(fn)
+	if err != nil {
+		return err
+	}
+	if hooks := r.hooks.Load(); hooks != nil {
+		hooks.notify(old, t)
+	}
+	return nil
+}
+
+// swap replaces the route table with the table returned by fn, see update.
+// Returns the old and the new table.
+func (r *Router[W]) swap(fn func(old *table[W]) (*table[W], error)) (old, t *table[W], err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	old = r.load()
+	t, err = fn(old)
+	if err == nil && t != old {
+		err = r.syncAliases(t)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.conf.RouteStats && t != old {
+		allocCounters(t)
+	}
+
+	// increase maxParams before publishing the table so that lookups in the
+	// table always get a params slice with sufficient capacity.
+	if uint32(t.maxParams) > r.maxParams.Load() {
+		r.maxParams.Store(uint32(t.maxParams))
+	}
+	r.tbl.Store(t)
+	return old, t, nil
+}
@@ -0,0 +1,67 @@
+package pathrouter
+
+import "slices"
+
+// taggedRoutes returns the routes of the table with the tag in registration
+// order.
+func taggedRoutes[W any](t *table[W], tag string) []*route[W] {
+	var out []*route[W]
+	for _, rt := range sortRoutes(t.routes) {
+		if slices.Contains(rt.tags, tag) {
+			out = append(out, rt)
+		}
+	}
+	return out
+}
+
+// RoutesByTag returns the patterns of the routes with the tag in registration
+// order, see WithTags.
+func (r *Router[W]) RoutesByTag(tag string) []string {
+	var patterns []string
+	for _, rt := range taggedRoutes(r.load(), tag) {
+		patterns = append(patterns, rt.pattern)
+	}
+	return patterns
+}
+
+// SetTagEnabled enables or disables the routes with the tag, see
+// SetRouteEnabled. Returns the number of routes with the tag.
+func (r *Router[W]) SetTagEnabled(tag string, enabled bool) int {
+	rts := taggedRoutes(r.load(), tag)
+	if !enabled && len(rts) != 0 {
+		r.anyDisabled.Store(true)
+	}
+	for _, rt := range rts {
+		rt.disabled.Store(!enabled)
+	}
+	return len(rts)
+}
+
+// WrapTag wraps the handles of the routes with the tag with the middleware.
+// The first middleware is the outermost. Routes registered later are not
+// wrapped. Returns the number of routes with the tag.
+//
+// Panics if the router is compiled.
+func (r *Router[W]) WrapTag(tag string, mws ...Middleware[W]) int {
+	var n int
+	err := r.update(func(old *table[W]) (*table[W], error) {
+		rts := taggedRoutes(old, tag)
+		n = len(rts)
+		if n == 0 {
+			return old, nil
+		}
+		t := old.clone()
+		for _, existing := range rts {
+			// routes are shared with the previous table: replace instead of modify
+			updated := *existing
+			updated.handle = applyMiddleware(existing.handle, mws)
+			updated.handles = nil
+			t.replaceRoute(existing, &updated)
+		}
+		return t, nil
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	return n
+}
@@ -0,0 +1,64 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRouterTags(t *testing.T) {
+	router := New[struct{}]()
+	router.AddHandler("/public/a", fakeHandler("a"), WithTags("public"))
+	router.AddHandler("/internal/b", fakeHandler("b"), WithTags("internal", "deprecated"))
+	router.Route("/public/c").Tags("public", "deprecated").Handle(fakeHandler("c"))
+	router.AddHandler("/untagged", fakeHandler("untagged"))
+
+	tests := []struct {
+		tag      string
+		patterns []string
+	}{
+		{"public", []string{"/public/a", "/public/c"}},
+		{"deprecated", []string{"/internal/b", "/public/c"}},
+		{"missing", nil},
+	}
+	for _, test := range tests {
+		if patterns := router.RoutesByTag(test.tag); !reflect.DeepEqual(patterns, test.patterns) {
+			t.Errorf("tag %s: expected %v but got %v", test.tag, test.patterns, patterns)
+		}
+	}
+	if defs := router.Routes(); !reflect.DeepEqual(defs[1].Tags, []string{"internal", "deprecated"}) {
+		t.Errorf("unexpected tags %v", defs[1].Tags)
+	}
+
+	ctx := context.Background()
+	if n := router.SetTagEnabled("deprecated", false); n != 2 {
+		t.Errorf("expected 2 disabled routes, got %d", n)
+	}
+	if found, _ := router.Serve(ctx, "/public/c", struct{}{}); found {
+		t.Error("expected disabled route not to be found")
+	}
+	router.SetTagEnabled("deprecated", true)
+
+	var wrapped []string
+	n := router.WrapTag("public", func(next Handle[struct{}]) Handle[struct{}] {
+		return func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+			wrapped = append(wrapped, reqPath)
+			return next(ctx, reqPath, p, rw)
+		}
+	})
+	if n != 2 {
+		t.Errorf("expected 2 wrapped routes, got %d", n)
+	}
+	for _, path := range []string{"/public/a", "/public/c", "/internal/b"} {
+		fakeHandlerValue = ""
+		if found, err := router.Serve(ctx, path, struct{}{}); !found || err != nil || fakeHandlerValue == "" {
+			t.Errorf("path %s: expected found, got found=%v err=%v", path, found, err)
+		}
+	}
+	if want := []string{"/public/a", "/public/c"}; !reflect.DeepEqual(wrapped, want) {
+		t.Errorf("expected wrapped calls %v but got %v", want, wrapped)
+	}
+	if problems := router.SelfCheck(); len(problems) != 0 {
+		t.Errorf("unexpected problems after wrapping: %v", problems)
+	}
+}
@@ -0,0 +1,24 @@
+package pathrouter
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// handleWithTimeout calls the handle of the match applying the route timeout,
+// see WithTimeout.
+func (r *Router[W]) handleWithTimeout(ctx context.Context, reqPath string, m match[W], params Params, wr W) (bool, error) {
+	if m.route == nil || m.route.timeout <= 0 {
+		return m.handle(ctx, reqPath, params, wr)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, m.route.timeout)
+	defer cancel()
+	found, err := m.handle(tctx, reqPath, params, wr)
+	// the deadline of the parent context is not a route timeout
+	if err != nil && r.conf.TimeoutHandler != nil && errors.Is(tctx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return r.conf.TimeoutHandler(ctx, reqPath, params, wr)
+	}
+	return found, err
+}
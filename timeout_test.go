@@ -0,0 +1,60 @@
+package pathrouter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRouteTimeout(t *testing.T) {
+	errTimeout := errors.New("timed out")
+	conf := DefaultConfig[struct{}]()
+	conf.TimeoutHandler = func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		return true, errors.Join(errTimeout, errors.New(p.ByName("name")))
+	}
+	router := NewWithConfig(conf)
+
+	wait := func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		if _, ok := ctx.Deadline(); !ok && p.ByName("name") != "" {
+			return false, errors.New("expected deadline")
+		}
+		if p.ByName("name") == "fast" {
+			return true, nil
+		}
+		if p.ByName("name") == "fail" {
+			return false, errors.New("failed")
+		}
+		<-ctx.Done()
+		return false, ctx.Err()
+	}
+	router.AddHandler("/slow/:name", wait, WithTimeout(10*time.Millisecond))
+	router.AddHandler("/nolimit", func(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+		if _, ok := ctx.Deadline(); ok {
+			return false, errors.New("unexpected deadline")
+		}
+		return true, nil
+	})
+
+	ctx := context.Background()
+	if found, err := router.Serve(ctx, "/slow/sleepy", struct{}{}); !found || !errors.Is(err, errTimeout) || err.Error() != "timed out\nsleepy" {
+		t.Errorf("expected timeout handler result, got found=%v err=%v", found, err)
+	}
+	if found, err := router.Serve(ctx, "/slow/fast", struct{}{}); !found || err != nil {
+		t.Errorf("expected found, got found=%v err=%v", found, err)
+	}
+	if _, err := router.Serve(ctx, "/slow/fail", struct{}{}); err == nil || errors.Is(err, errTimeout) {
+		t.Errorf("expected handle error, got %v", err)
+	}
+	if found, err := router.Serve(ctx, "/nolimit", struct{}{}); !found || err != nil {
+		t.Errorf("expected found, got found=%v err=%v", found, err)
+	}
+
+	// the deadline of the parent context is not mapped to the timeout handler
+	parent, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	router.AddHandler("/long", wait, WithTimeout(time.Hour))
+	if _, err := router.Serve(parent, "/long", struct{}{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected parent deadline error, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package pathrouter
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TopicCatchAllName is the name of the param containing the levels matched by
+// the multi level # wildcard.
+const TopicCatchAllName = "#"
+
+// TopicRouterConfig is the configuration for a TopicRouter.
+type TopicRouterConfig[W any] struct {
+	// RouterConfig is the configuration for the underlying router.
+	//
+	// Overlapping filters are always allowed and the trailing slash and fixed
+	// path redirects are not applied. The Separator separates the topic
+	// levels and defaults to '/' if zero.
+	RouterConfig[W]
+}
+
+// TopicRouter dispatches MQTT style topics to the handles registered with
+// matching topic filters.
+//
+// The + wildcard matches a single topic level and the # wildcard matches any
+// number of levels including the parent level and must be the final level of
+// the filter. Wildcards only match topics starting with $ if the first level of
+// the filter is not a wildcard. The separator is configurable, for example '.'
+// for NATS style subjects.
+//
+// The params are named by the index of the level: the + in a/+/c is named "1".
+// The levels matched by # are in the param named TopicCatchAllName without the
+// leading separator.
+//
+// Safe to use concurrently.
+type TopicRouter[W any] struct {
+	sep    byte
+	router *Router[W]
+}
+
+// NewTopicRouter constructs a new TopicRouter with the given config.
+func NewTopicRouter[W any](conf TopicRouterConfig[W]) *TopicRouter[W] {
+	sep := conf.Separator
+	if sep == 0 {
+		sep = '/'
+	}
+	rconf := conf.RouterConfig
+	rconf.Fallthrough = true
+	rconf.Separator = 0
+	rconf.RedirectTrailingSlash = false
+	rconf.TrailingSlash = TrailingSlashStrict
+	rconf.RedirectFixedPath = false
+	return &TopicRouter[W]{sep: sep, router: NewWithConfig(rconf)}
+}
+
+// TopicFilterPattern converts a topic filter with the given separator to a
+// route pattern.
+func TopicFilterPattern(filter string, sep byte) (string, error) {
+	if filter == "" {
+		return "", errors.New("topic filter must not be empty")
+	}
+	if strings.ContainsAny(filter, ":*{}") {
+		return "", errors.Errorf("unsupported character in topic filter '%s'", filter)
+	}
+
+	levels := strings.Split(filter, string(sep))
+	var sb strings.Builder
+	for i, level := range levels {
+		sb.WriteByte('/')
+		switch {
+		case level == "+":
+			sb.WriteString(":" + strconv.Itoa(i))
+		case level == "#":
+			if i != len(levels)-1 {
+				return "", errors.Errorf("the # wildcard must be the final level of topic filter '%s'", filter)
+			}
+			sb.WriteString("*" + TopicCatchAllName)
+		case strings.ContainsAny(level, "+#"):
+			return "", errors.Errorf("wildcards must occupy an entire level in topic filter '%s'", filter)
+		default:
+			sb.WriteString(swapSeparator(level, sep))
+		}
+	}
+	return sb.String(), nil
+}
+
+// Router returns the underlying router.
+func (t *TopicRouter[W]) Router() *Router[W] {
+	return t.router
+}
+
+// AddHandler registers a new handle with the given topic filter.
+//
+// Panics if the filter is invalid or already registered.
+func (t *TopicRouter[W]) AddHandler(filter string, handle Handle[W], opts ...RouteOption) {
+	pattern, err := TopicFilterPattern(filter, t.sep)
+	if err != nil {
+		panic(err.Error())
+	}
+	t.router.AddHandler(pattern, handle, opts...)
+}
+
+// Serve calls the handles of all filters matching the topic.
+//
+// The handles are called in precedence order regardless of the result of the
+// previous handles, see Router.ServeAll. If no filter matches the NotFound
+// handler is called, if set.
+func (t *TopicRouter[W]) Serve(ctx context.Context, topic string, rw W) (found bool, err error) {
+	r := t.router
+	if r.recoverPanics() {
+		defer r.recoverPanic(ctx, topic, rw, &found, &err)
+	}
+
+	matches := t.match(topic)
+	if len(matches) == 0 {
+		return r.notFound(ctx, topic, rw)
+	}
+	defer r.putMatches(matches)
+
+	var anyFound bool
+	var errs []error
+	for _, m := range matches {
+		found, err := r.callHandle(ctx, topic, m, rw, nil)
+		anyFound = anyFound || found
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return anyFound, joinErrors(errs)
+}
+
+// match returns the matches for the topic in precedence order.
+func (t *TopicRouter[W]) match(topic string) []match[W] {
+	r := t.router
+	path := "/" + swapSeparator(topic, t.sep)
+	tbl := r.load()
+	matches, _ := r.matchAll(tbl, path)
+
+	// a filter ending with # also matches the parent level
+	parents, _ := r.matchAll(tbl, path+"/")
+	for _, m := range parents {
+		if parts := m.route.parts; parts[len(parts)-1].kind == catchAll && m.ps.ByName(TopicCatchAllName) == "/" {
+			matches = append(matches, m)
+		} else {
+			r.putParams(m.ps)
+		}
+	}
+	sortMatches(matches)
+
+	out := matches[:0]
+	for _, m := range matches {
+		// wildcards in the first level do not match $ topics
+		if parts := m.route.parts; strings.HasPrefix(topic, "$") && len(parts) > 1 && parts[0].value == "/" {
+			r.putParams(m.ps)
+			continue
+		}
+		if m.ps != nil {
+			ps := *m.ps
+			for i := range ps {
+				if ps[i].Key == TopicCatchAllName {
+					ps[i].Value = ps[i].Value[1:]
+				}
+				ps[i].Value = swapSeparator(ps[i].Value, t.sep)
+			}
+		}
+		out = append(out, m)
+	}
+	return out
+}
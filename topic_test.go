@@ -0,0 +1,114 @@
+package pathrouter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTopicFilterPattern(t *testing.T) {
+	tests := []struct {
+		filter  string
+		sep     byte
+		pattern string
+	}{
+		{"sport/tennis/player1", '/', "/sport/tennis/player1"},
+		{"sport/+/player1", '/', "/sport/:1/player1"},
+		{"sport/#", '/', "/sport/*#"},
+		{"#", '/', "/*#"},
+		{"+/+", '/', "/:0/:1"},
+		{"/finance", '/', "//finance"},
+		{"orders.*.created", '.', ""},
+		{"orders.+.created", '.', "/orders/:1/created"},
+		{"files.a/b.#", '.', "/files/a.b/*#"},
+	}
+	for _, test := range tests {
+		pattern, err := TopicFilterPattern(test.filter, test.sep)
+		if test.pattern == "" {
+			if err == nil {
+				t.Errorf("filter %s: expected error", test.filter)
+			}
+			continue
+		}
+		if err != nil || pattern != test.pattern {
+			t.Errorf("filter %s: expected %q but got %q %v", test.filter, test.pattern, pattern, err)
+		}
+	}
+
+	for _, filter := range []string{"", "sport/tennis#", "sport/#/ranking", "sport+", "a/:b", "a/{b}"} {
+		if _, err := TopicFilterPattern(filter, '/'); err == nil {
+			t.Errorf("filter %s: expected error", filter)
+		}
+	}
+}
+
+func TestTopicRouter(t *testing.T) {
+	var calls []string
+	var params []Params
+	handler := func(name string) Handle[struct{}] {
+		return func(ctx context.Context, topic string, p Params, rw struct{}) (bool, error) {
+			calls = append(calls, name)
+			params = append(params, append(Params(nil), p...))
+			return true, nil
+		}
+	}
+
+	router := NewTopicRouter(TopicRouterConfig[struct{}]{})
+	router.AddHandler("sport/tennis/player1", handler("exact"))
+	router.AddHandler("sport/+/player1", handler("plus"))
+	router.AddHandler("sport/tennis/#", handler("hash"))
+	router.AddHandler("#", handler("all"))
+	router.AddHandler("$SYS/#", handler("sys"))
+
+	ctx := context.Background()
+	tests := []struct {
+		topic  string
+		calls  []string
+		params []Params
+	}{{
+		topic:  "sport/tennis/player1",
+		calls:  []string{"exact", "hash", "plus", "all"},
+		params: []Params{nil, {{"#", "player1"}}, {{"1", "tennis"}}, {{"#", "sport/tennis/player1"}}},
+	}, {
+		topic:  "sport/tennis",
+		calls:  []string{"hash", "all"},
+		params: []Params{{{"#", ""}}, {{"#", "sport/tennis"}}},
+	}, {
+		topic:  "sport/golf/player1",
+		calls:  []string{"plus", "all"},
+		params: []Params{{{"1", "golf"}}, {{"#", "sport/golf/player1"}}},
+	}, {
+		topic:  "$SYS/broker/uptime",
+		calls:  []string{"sys"},
+		params: []Params{{{"#", "broker/uptime"}}},
+	}}
+	for _, test := range tests {
+		calls, params = nil, nil
+		if found, err := router.Serve(ctx, test.topic, struct{}{}); !found || err != nil {
+			t.Errorf("topic %s: expected found, got found=%v err=%v", test.topic, found, err)
+		}
+		if !reflect.DeepEqual(calls, test.calls) || !reflect.DeepEqual(params, test.params) {
+			t.Errorf("topic %s: expected %v %v but got %v %v", test.topic, test.calls, test.params, calls, params)
+		}
+	}
+}
+
+func TestTopicRouterSeparator(t *testing.T) {
+	router := NewTopicRouter(TopicRouterConfig[struct{}]{RouterConfig: RouterConfig[struct{}]{Separator: '.'}})
+	var got Params
+	router.AddHandler("orders.+.#", func(ctx context.Context, topic string, p Params, rw struct{}) (bool, error) {
+		got = append(Params(nil), p...)
+		return true, nil
+	})
+
+	if found, err := router.Serve(context.Background(), "orders.eu/west.created.v1", struct{}{}); !found || err != nil {
+		t.Fatalf("expected found, got found=%v err=%v", found, err)
+	}
+	if want := (Params{{"1", "eu/west"}, {"#", "created.v1"}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v but got %v", want, got)
+	}
+
+	if found, _ := router.Serve(context.Background(), "invoices.eu", struct{}{}); found {
+		t.Error("expected not found")
+	}
+}
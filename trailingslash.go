@@ -0,0 +1,65 @@
+package pathrouter
+
+// TrailingSlashPolicy configures how paths with an extra or missing trailing
+// slash are handled, see RouterConfig.TrailingSlash.
+type TrailingSlashPolicy int
+
+const (
+	// TrailingSlashDefault uses RouterConfig.RedirectTrailingSlash: redirect if
+	// set, otherwise strict.
+	TrailingSlashDefault TrailingSlashPolicy = iota
+	// TrailingSlashStrict only matches the path as registered: /foo/ does not
+	// match /foo.
+	TrailingSlashStrict
+	// TrailingSlashRedirect corrects the path to the route with (without) the
+	// trailing slash: the RedirectHandler is called, if set, otherwise the
+	// corrected path is served.
+	TrailingSlashRedirect
+	// TrailingSlashMatchBoth matches the route with (without) the trailing
+	// slash as if the path was registered both ways: the RedirectHandler is not
+	// called and the correction does not count against MaxCorrections.
+	TrailingSlashMatchBoth
+)
+
+// String returns the name of the policy.
+func (p TrailingSlashPolicy) String() string {
+	switch p {
+	case TrailingSlashStrict:
+		return "strict"
+	case TrailingSlashRedirect:
+		return "redirect"
+	case TrailingSlashMatchBoth:
+		return "match-both"
+	default:
+		return "default"
+	}
+}
+
+// trailingSlash returns the effective trailing slash policy.
+func (r *Router[W]) trailingSlash() TrailingSlashPolicy {
+	switch {
+	case r.conf.TrailingSlash != TrailingSlashDefault:
+		return r.conf.TrailingSlash
+	case r.conf.RedirectTrailingSlash:
+		return TrailingSlashRedirect
+	default:
+		return TrailingSlashStrict
+	}
+}
+
+// matchBothPath returns the path with (without) the trailing slash if the
+// policy is TrailingSlashMatchBoth and the lookup recommended it.
+func (r *Router[W]) matchBothPath(reqPath string, tsr bool) (string, bool) {
+	if !tsr || reqPath == "/" || r.trailingSlash() != TrailingSlashMatchBoth {
+		return "", false
+	}
+	return toggleTrailingSlash(reqPath), true
+}
+
+// toggleTrailingSlash removes the trailing slash of the path or adds one.
+func toggleTrailingSlash(reqPath string) string {
+	if len(reqPath) > 1 && reqPath[len(reqPath)-1] == '/' {
+		return reqPath[:len(reqPath)-1]
+	}
+	return reqPath + "/"
+}
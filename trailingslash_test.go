@@ -0,0 +1,102 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTrailingSlashPolicy(t *testing.T) {
+	type result struct {
+		value    string
+		redirect string
+	}
+	tests := []struct {
+		policy   TrailingSlashPolicy
+		redirect bool
+		want     map[string]result
+	}{{
+		policy:   TrailingSlashDefault,
+		redirect: true,
+		want: map[string]result{
+			"/foo":  {"foo", ""},
+			"/foo/": {"", "/foo"},
+			"/bar":  {"", "/bar/"},
+		},
+	}, {
+		policy: TrailingSlashDefault,
+		want: map[string]result{
+			"/foo/": {"", ""},
+			"/bar":  {"", ""},
+		},
+	}, {
+		policy:   TrailingSlashStrict,
+		redirect: true,
+		want: map[string]result{
+			"/foo":  {"foo", ""},
+			"/foo/": {"", ""},
+			"/bar":  {"", ""},
+		},
+	}, {
+		policy: TrailingSlashRedirect,
+		want: map[string]result{
+			"/foo/": {"", "/foo"},
+			"/bar":  {"", "/bar/"},
+		},
+	}, {
+		policy: TrailingSlashMatchBoth,
+		want: map[string]result{
+			"/foo":  {"foo", ""},
+			"/foo/": {"foo", ""},
+			"/bar":  {"bar", ""},
+			"/bar/": {"bar", ""},
+			"/baz":  {"", ""},
+		},
+	}}
+
+	for _, tt := range tests {
+		var redirected string
+		conf := RouterConfig[struct{}]{
+			TrailingSlash:         tt.policy,
+			RedirectTrailingSlash: tt.redirect,
+			RedirectHandler: func(ctx context.Context, fromPath, toPath string, rw struct{}) (bool, error) {
+				redirected = toPath
+				return true, nil
+			},
+		}
+		router := NewWithConfig(conf)
+		router.AddHandler("/foo", fakeHandler("foo"))
+		router.AddHandler("/bar/", fakeHandler("bar"))
+
+		for path, want := range tt.want {
+			fakeHandlerValue, redirected = "", ""
+			if _, err := router.Serve(context.Background(), path, struct{}{}); err != nil {
+				t.Fatal(err)
+			}
+			if fakeHandlerValue != want.value || redirected != want.redirect {
+				t.Errorf("%v redirect=%v %s: expected %+v, got value=%q redirect=%q", tt.policy, tt.redirect, path, want, fakeHandlerValue, redirected)
+			}
+
+			res := router.Lookup(path)
+			if (res.Handle != nil) != (want.value != "") || res.RedirectPath != want.redirect {
+				t.Errorf("%v %s: unexpected lookup result %+v", tt.policy, path, res)
+			}
+
+			fakeHandlerValue = ""
+			if _, err := router.ServeAll(context.Background(), path, struct{}{}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.policy == TrailingSlashMatchBoth && fakeHandlerValue != want.value {
+				t.Errorf("%v %s: ServeAll: expected %q, got %q", tt.policy, path, want.value, fakeHandlerValue)
+			}
+		}
+	}
+}
+
+func TestTrailingSlashMatchBothDisabled(t *testing.T) {
+	router := NewWithConfig(RouterConfig[struct{}]{TrailingSlash: TrailingSlashMatchBoth})
+	router.AddHandler("/foo", fakeHandler("foo"))
+	router.SetRouteEnabled("/foo", false)
+	if found, err := router.Serve(context.Background(), "/foo/", struct{}{}); found || err != nil {
+		t.Errorf("expected disabled route not to match, got found=%v err=%v", found, err)
+	}
+}
@@ -79,6 +79,12 @@ type node[W any] struct {
 	priority  uint32
 	children  []*node[W]
 	handle    Handle[W]
+
+	// anchored indicates the handle must only be matched by the exact path.
+	// Trailing slash and case-insensitive corrections never resolve to it.
+	anchored bool
+	// route is the route the handle was registered with, if any.
+	route *route[W]
 }
 
 // Increments priority of the given child and reorders if necessary
@@ -105,16 +111,17 @@ func (n *node[W]) incrementChildPrio(pos int) int {
 }
 
 // addRoute adds a node with the given handle to the path.
+// Returns the node holding the handle.
 // Not concurrency-safe!
-func (n *node[W]) addRoute(path string, handle Handle[W]) {
+func (n *node[W]) addRoute(path string, handle Handle[W]) *node[W] {
 	fullPath := path
 	n.priority++
 
 	// Empty tree
 	if n.path == "" && n.indices == "" {
-		n.insertChild(path, fullPath, handle)
+		leaf := n.insertChild(path, fullPath, handle)
 		n.nType = root
-		return
+		return leaf
 	}
 
 walk:
@@ -133,6 +140,8 @@ walk:
 				indices:   n.indices,
 				children:  n.children,
 				handle:    n.handle,
+				anchored:  n.anchored,
+				route:     n.route,
 				priority:  n.priority - 1,
 			}
 
@@ -141,6 +150,8 @@ walk:
 			n.indices = string([]byte{n.path[i]})
 			n.path = path[:i]
 			n.handle = nil
+			n.anchored = false
+			n.route = nil
 			n.wildChild = false
 		}
 
@@ -201,8 +212,7 @@ walk:
 				n.incrementChildPrio(len(n.indices) - 1)
 				n = child
 			}
-			n.insertChild(path, fullPath, handle)
-			return
+			return n.insertChild(path, fullPath, handle)
 		}
 
 		// Otherwise add handle to current node
@@ -210,11 +220,159 @@ walk:
 			panic("a handle is already registered for path '" + fullPath + "'")
 		}
 		n.handle = handle
-		return
+		return n
+	}
+}
+
+// insertRoute adds the route to the tree.
+// Not concurrency-safe!
+func (n *node[W]) insertRoute(rt *route[W]) {
+	leaf := n.addRoute(rt.path, rt.handle)
+	leaf.anchored = rt.anchored
+	leaf.route = rt
+}
+
+// clone returns a deep copy of the node and its children.
+// The handles and routes are shared with the copy.
+func (n *node[W]) clone() *node[W] {
+	out := *n
+	if len(n.children) != 0 {
+		out.children = make([]*node[W], len(n.children))
+		for i, child := range n.children {
+			out.children[i] = child.clone()
+		}
+	}
+	return &out
+}
+
+// copyNode returns a shallow copy of the node with its own children slice.
+func (n *node[W]) copyNode() *node[W] {
+	out := *n
+	if len(n.children) != 0 {
+		out.children = append([]*node[W](nil), n.children...)
+	}
+	return &out
+}
+
+// copyPath returns a copy of the tree in which the nodes addRoute may modify
+// when adding the path are copied. The other nodes are shared with the tree.
+func (n *node[W]) copyPath(path string) *node[W] {
+	root := n.copyNode()
+	n = root
+	for {
+		i := longestCommonPrefix(path, n.path)
+		if i < len(n.path) || i == len(path) {
+			return root
+		}
+		path = path[i:]
+
+		pos := -1
+		switch {
+		case n.wildChild:
+			pos = 0
+		case n.nType == param && path[0] == '/' && len(n.children) == 1:
+			pos = 0
+		default:
+			pos = strings.IndexByte(n.indices, path[0])
+		}
+		if pos < 0 {
+			return root
+		}
+		child := n.children[pos].copyNode()
+		n.children[pos] = child
+		n = child
+	}
+}
+
+// findPath returns the node holding the handle registered with exactly the
+// given path, comparing wildcards literally instead of matching them.
+// Returns nil if no handle is registered with the path.
+func (n *node[W]) findPath(path string) *node[W] {
+walk:
+	for {
+		if !strings.HasPrefix(path, n.path) {
+			return nil
+		}
+		path = path[len(n.path):]
+		if len(path) == 0 {
+			if n.handle == nil {
+				return nil
+			}
+			return n
+		}
+
+		if n.wildChild {
+			n = n.children[0]
+			continue
+		}
+
+		// '/' after param
+		if n.nType == param {
+			if path[0] != '/' || len(n.children) != 1 {
+				return nil
+			}
+			n = n.children[0]
+			continue
+		}
+
+		for i, c := range []byte(n.indices) {
+			if c == path[0] {
+				n = n.children[i]
+				continue walk
+			}
+		}
+		return nil
 	}
 }
 
-func (n *node[W]) insertChild(path, fullPath string, handle Handle[W]) {
+// findPrefix returns the node below which all paths start with the given
+// prefix, comparing wildcards literally instead of matching them.
+// Returns nil if no path starts with the prefix.
+func (n *node[W]) findPrefix(prefix string) *node[W] {
+walk:
+	for {
+		if len(prefix) <= len(n.path) {
+			if !strings.HasPrefix(n.path, prefix) {
+				return nil
+			}
+			return n
+		}
+		if !strings.HasPrefix(prefix, n.path) {
+			return nil
+		}
+		prefix = prefix[len(n.path):]
+
+		if n.wildChild || n.nType == param {
+			if len(n.children) != 1 {
+				return nil
+			}
+			n = n.children[0]
+			continue
+		}
+
+		for i, c := range []byte(n.indices) {
+			if c == prefix[0] {
+				n = n.children[i]
+				continue walk
+			}
+		}
+		return nil
+	}
+}
+
+// walkRoutes calls fn for each route registered in the tree.
+func (n *node[W]) walkRoutes(fn func(rt *route[W])) {
+	if n.route != nil {
+		fn(n.route)
+	}
+	for _, child := range n.children {
+		child.walkRoutes(fn)
+	}
+}
+
+// insertChild inserts the path below n.
+// Returns the node holding the handle.
+func (n *node[W]) insertChild(path, fullPath string, handle Handle[W]) *node[W] {
 	for {
 		// Find prefix until first wildcard
 		wildcard, i, valid := findWildcard(path)
@@ -271,7 +429,7 @@ func (n *node[W]) insertChild(path, fullPath string, handle Handle[W]) {
 
 			// Otherwise we're done. Insert the handle in the new leaf
 			n.handle = handle
-			return
+			return n
 		}
 
 		// catchAll
@@ -310,20 +468,23 @@ func (n *node[W]) insertChild(path, fullPath string, handle Handle[W]) {
 		}
 		n.children = []*node[W]{child}
 
-		return
+		return child
 	}
 
 	// If no wildcard was found, simply insert the path and handle
 	n.path = path
 	n.handle = handle
+	return n
 }
 
-// Returns the handle registered with the given path (key). The values of
-// wildcards are saved to a map.
+// Returns the node holding the handle registered with the given path (key).
+// The values of wildcards are saved to a map.
 // If no handle can be found, a TSR (trailing slash redirect) recommendation is
 // made if a handle exists with an extra (without the) trailing slash for the
-// given path.
-func (n *node[W]) getValue(path string, params func() *Params) (handle Handle[W], ps *Params, tsr bool) {
+// given path. The offsets of the wildcard values are recorded to offsets, if
+// not nil, see LazyParams.
+func (n *node[W]) getValue(path string, params func() *Params, offsets *paramOffsets) (leaf *node[W], ps *Params, tsr bool) {
+	pathLen := len(path)
 walk: // Outer loop for walking the tree
 	for {
 		prefix := n.path
@@ -346,7 +507,7 @@ walk: // Outer loop for walking the tree
 					// Nothing found.
 					// We can recommend to redirect to the same URL without a
 					// trailing slash if a leaf exists for that path.
-					tsr = (path == "/" && n.handle != nil)
+					tsr = (path == "/" && n.handle != nil && !n.anchored)
 					return
 				}
 
@@ -373,6 +534,9 @@ walk: // Outer loop for walking the tree
 							Value: path[:end],
 						}
 					}
+					if offsets != nil {
+						offsets.add(pathLen - len(path))
+					}
 
 					// We need to go deeper!
 					if end < len(path) {
@@ -387,13 +551,14 @@ walk: // Outer loop for walking the tree
 						return
 					}
 
-					if handle = n.handle; handle != nil {
+					if n.handle != nil {
+						leaf = n
 						return
 					} else if len(n.children) == 1 {
 						// No handle found. Check if a handle for this path + a
 						// trailing slash exists for TSR recommendation
 						n = n.children[0]
-						tsr = (n.path == "/" && n.handle != nil) || (n.path == "" && n.indices == "/")
+						tsr = (n.path == "/" && n.handle != nil && !n.anchored) || (n.path == "" && n.indices == "/")
 					}
 
 					return
@@ -412,8 +577,13 @@ walk: // Outer loop for walking the tree
 							Value: path,
 						}
 					}
+					if offsets != nil {
+						offsets.add(pathLen - len(path))
+					}
 
-					handle = n.handle
+					if n.handle != nil {
+						leaf = n
+					}
 					return
 
 				default:
@@ -423,7 +593,8 @@ walk: // Outer loop for walking the tree
 		} else if path == prefix {
 			// We should have reached the node containing the handle.
 			// Check if this node has a handle registered.
-			if handle = n.handle; handle != nil {
+			if n.handle != nil {
+				leaf = n
 				return
 			}
 
@@ -445,7 +616,7 @@ walk: // Outer loop for walking the tree
 			for i, c := range []byte(n.indices) {
 				if c == '/' {
 					n = n.children[i]
-					tsr = (len(n.path) == 1 && n.handle != nil) ||
+					tsr = (len(n.path) == 1 && n.handle != nil && !n.anchored) ||
 						(n.nType == catchAll && n.children[0].handle != nil)
 					return
 				}
@@ -457,7 +628,7 @@ walk: // Outer loop for walking the tree
 		// extra trailing slash if a leaf exists for that path
 		tsr = (path == "/") ||
 			(len(prefix) == len(path)+1 && prefix[len(path)] == '/' &&
-				path == prefix[:len(prefix)-1] && n.handle != nil)
+				path == prefix[:len(prefix)-1] && n.handle != nil && !n.anchored)
 		return
 	}
 }
@@ -592,7 +763,7 @@ walk: // Outer loop for walking the tree
 
 				// Nothing found. We can recommend to redirect to the same URL
 				// without a trailing slash if a leaf exists for that path
-				if fixTrailingSlash && path == "/" && n.handle != nil {
+				if fixTrailingSlash && path == "/" && n.handle != nil && !n.anchored {
 					return ciPath
 				}
 				return nil
@@ -627,13 +798,13 @@ walk: // Outer loop for walking the tree
 					return nil
 				}
 
-				if n.handle != nil {
+				if n.handle != nil && !n.anchored {
 					return ciPath
 				} else if fixTrailingSlash && len(n.children) == 1 {
 					// No handle found. Check if a handle for this path + a
 					// trailing slash exists
 					n = n.children[0]
-					if n.path == "/" && n.handle != nil {
+					if n.path == "/" && n.handle != nil && !n.anchored {
 						return append(ciPath, '/')
 					}
 				}
@@ -648,7 +819,7 @@ walk: // Outer loop for walking the tree
 		} else {
 			// We should have reached the node containing the handle.
 			// Check if this node has a handle registered.
-			if n.handle != nil {
+			if n.handle != nil && !n.anchored {
 				return ciPath
 			}
 
@@ -658,7 +829,7 @@ walk: // Outer loop for walking the tree
 				for i, c := range []byte(n.indices) {
 					if c == '/' {
 						n = n.children[i]
-						if (len(n.path) == 1 && n.handle != nil) ||
+						if (len(n.path) == 1 && n.handle != nil && !n.anchored) ||
 							(n.nType == catchAll && n.children[0].handle != nil) {
 							return append(ciPath, '/')
 						}
@@ -677,7 +848,7 @@ walk: // Outer loop for walking the tree
 			return ciPath
 		}
 		if len(path)+1 == npLen && n.path[len(path)] == '/' &&
-			strings.EqualFold(path[1:], n.path[1:len(path)]) && n.handle != nil {
+			strings.EqualFold(path[1:], n.path[1:len(path)]) && n.handle != nil && !n.anchored {
 			return append(ciPath, n.path...)
 		}
 	}
@@ -47,10 +47,10 @@ func getParams() *Params {
 
 func checkRequests[W any](t *testing.T, tree *node[W], requests testRequests) {
 	for _, request := range requests {
-		handler, psp, _ := tree.getValue(request.path, getParams)
+		leaf, psp, _ := tree.getValue(request.path, getParams, nil)
 
 		switch {
-		case handler == nil:
+		case leaf == nil:
 			if !request.nilHandler {
 				t.Errorf("handle mismatch for route '%s': Expected non-nil handle", request.path)
 			}
@@ -58,7 +58,7 @@ func checkRequests[W any](t *testing.T, tree *node[W], requests testRequests) {
 			t.Errorf("handle mismatch for route '%s': Expected nil handle", request.path)
 		default:
 			var empty W
-			handler(nil, request.path, nil, empty)
+			leaf.handle(nil, request.path, nil, empty)
 			if fakeHandlerValue != request.route {
 				t.Errorf("handle mismatch for route '%s': Wrong handle (%s != %s)", request.path, fakeHandlerValue, request.route)
 			}
@@ -426,8 +426,8 @@ func TestTreeTrailingSlashRedirect(t *testing.T) {
 		"/vendor/x",
 	}
 	for _, route := range tsrRoutes {
-		handler, _, tsr := tree.getValue(route, nil)
-		if handler != nil {
+		leaf, _, tsr := tree.getValue(route, nil, nil)
+		if leaf != nil {
 			t.Fatalf("non-nil handler for TSR route '%s", route)
 		} else if !tsr {
 			t.Errorf("expected TSR recommendation for route '%s'", route)
@@ -443,8 +443,8 @@ func TestTreeTrailingSlashRedirect(t *testing.T) {
 		"/api/world/abc",
 	}
 	for _, route := range noTsrRoutes {
-		handler, _, tsr := tree.getValue(route, nil)
-		if handler != nil {
+		leaf, _, tsr := tree.getValue(route, nil, nil)
+		if leaf != nil {
 			t.Fatalf("non-nil handler for No-TSR route '%s", route)
 		} else if tsr {
 			t.Errorf("expected no TSR recommendation for route '%s'", route)
@@ -462,8 +462,8 @@ func TestTreeRootTrailingSlashRedirect(t *testing.T) {
 		t.Fatalf("panic inserting test route: %v", recv)
 	}
 
-	handler, _, tsr := tree.getValue("/", nil)
-	if handler != nil {
+	leaf, _, tsr := tree.getValue("/", nil, nil)
+	if leaf != nil {
 		t.Fatalf("non-nil handler")
 	} else if tsr {
 		t.Errorf("expected no TSR recommendation")
@@ -645,7 +645,7 @@ func TestTreeInvalidNodeType(t *testing.T) {
 
 	// normal lookup
 	recv := catchPanic(func() {
-		tree.getValue("/test", nil)
+		tree.getValue("/test", nil, nil)
 	})
 	if rs, ok := recv.(string); !ok || rs != panicMsg {
 		t.Fatalf("Expected panic '"+panicMsg+"', got '%v'", recv)
@@ -716,8 +716,119 @@ func TestRedirectTrailingSlash(t *testing.T) {
 		node.addRoute(item.path, fakeHandler("test"))
 	}
 
-	_, _, tsr := node.getValue("/hello/abx/", nil)
+	_, _, tsr := node.getValue("/hello/abx/", nil, nil)
 	if tsr != true {
 		t.Fatalf("want true, is false")
 	}
 }
+
+func TestTreeFindPath(t *testing.T) {
+	tree := &node[struct{}]{}
+
+	routes := [...]string{
+		"/",
+		"/cmd/:tool/:sub",
+		"/cmd/:tool/",
+		"/src/*filepath",
+		"/search/",
+		"/search/:query",
+		"/user_:name",
+		"/user_:name/about",
+		"/files/:dir/*filepath",
+		"/doc/go_faq.html",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, fakeHandler(route))
+	}
+
+	for _, route := range routes {
+		leaf := tree.findPath(route)
+		if leaf == nil {
+			t.Errorf("no node found for path '%s'", route)
+			continue
+		}
+		leaf.handle(nil, route, nil, struct{}{})
+		if fakeHandlerValue != route {
+			t.Errorf("wrong node found for path '%s': %s", route, fakeHandlerValue)
+		}
+	}
+
+	for _, route := range [...]string{
+		"/cmd/test/",
+		"/cmd/:tools/",
+		"/cmd/:tool",
+		"/src/",
+		"/src/*file",
+		"/search",
+		"/user_gopher",
+		"/doc/",
+		"/doc/go_faq.htm",
+	} {
+		if leaf := tree.findPath(route); leaf != nil {
+			t.Errorf("unexpected node found for path '%s'", route)
+		}
+	}
+}
+
+func TestTreeTryInsertRoute(t *testing.T) {
+	tree := &node[struct{}]{}
+	for _, pattern := range [...]string{
+		"/",
+		"/cmd/:tool/:sub",
+		"/src/*filepath",
+		"/search/",
+		"/user_:name",
+	} {
+		rt, err := newRoute(pattern, fakeHandler(pattern), '/')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree, err = tryInsertRoute(tree, rt); err != nil {
+			t.Fatalf("insert '%s': %v", pattern, err)
+		}
+	}
+	requests := testRequests{
+		{"/", false, "/", nil},
+		{"/cmd/test/3", false, "/cmd/:tool/:sub", Params{Param{"tool", "test"}, Param{"sub", "3"}}},
+		{"/src/some/file.png", false, "/src/*filepath", Params{Param{"filepath", "/some/file.png"}}},
+		{"/search/", false, "/search/", nil},
+		{"/user_gopher", false, "/user_:name", Params{Param{"name", "gopher"}}},
+		{"/search/go", true, "", nil},
+	}
+
+	// conflicting inserts leave the tree unchanged
+	for _, pattern := range [...]string{
+		"/cmd/:name/:sub",
+		"/cmd/vet",
+		"/src/file",
+		"/search/",
+		"/user_:id/about",
+	} {
+		rt, err := newRoute(pattern, fakeHandler(pattern), '/')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tryInsertRoute(tree, rt); err == nil {
+			t.Errorf("expected conflict inserting '%s'", pattern)
+		}
+		checkRequests(t, tree, requests)
+		checkPriorities(t, tree)
+	}
+
+	// a successful insert returns a copy, the tree is not changed
+	rt, err := newRoute("/search/:query", fakeHandler("/search/:query"), '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := tryInsertRoute(tree, rt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequests(t, tree, requests)
+	checkRequests(t, updated, testRequests{
+		{"/search/", false, "/search/", nil},
+		{"/search/go", false, "/search/:query", Params{Param{"query", "go"}}},
+		{"/user_gopher", false, "/user_:name", Params{Param{"name", "gopher"}}},
+	})
+	checkPriorities(t, updated)
+}
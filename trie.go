@@ -0,0 +1,171 @@
+package pathrouter
+
+import (
+	"context"
+	"maps"
+	"strings"
+)
+
+// PathTrie is a radix trie mapping path patterns to values without the
+// handler and serving semantics of Router.
+//
+// The patterns have the syntax of the Router patterns and are stored in the
+// same radix tree: Get matches a path with the pattern rules of Router and
+// patterns conflict as with Router without RouterConfig.Fallthrough. Paths are
+// matched literally: they are not cleaned, unescaped or normalized.
+//
+// Router does not use PathTrie: both are built on the same unexported tree,
+// which Router extends with layered trees, handles and redirects.
+//
+// Not safe to use concurrently, like a map.
+type PathTrie[T any] struct {
+	tree *node[struct{}]
+	// routes contains the routes of the patterns by pattern.
+	routes map[string]*route[struct{}]
+	// values contains the values by pattern.
+	values map[string]T
+	// version orders the patterns by insertion.
+	version uint64
+	// maxParams is the maximum number of params of the patterns.
+	maxParams uint16
+}
+
+// trieHandle is the handle of the trie nodes holding a value.
+func trieHandle(ctx context.Context, reqPath string, p Params, rw struct{}) (bool, error) {
+	return false, nil
+}
+
+// NewPathTrie constructs a new empty PathTrie.
+func NewPathTrie[T any]() *PathTrie[T] {
+	return &PathTrie[T]{
+		tree:   new(node[struct{}]),
+		routes: make(map[string]*route[struct{}]),
+		values: make(map[string]T),
+	}
+}
+
+// Len returns the number of patterns in the trie.
+func (t *PathTrie[T]) Len() int {
+	return len(t.routes)
+}
+
+// Insert sets the value of the pattern, replacing the value if the pattern is
+// already in the trie.
+//
+// Returns an error if the pattern is invalid or conflicts with another pattern
+// in the trie. The trie is not changed if an error is returned.
+func (t *PathTrie[T]) Insert(pattern string, value T) error {
+	rt, err := newRoute(pattern, trieHandle, 0)
+	if err != nil {
+		return err
+	}
+	if _, exists := t.routes[rt.pattern]; exists {
+		t.values[rt.pattern] = value
+		return nil
+	}
+
+	tree, err := tryInsertRoute(t.tree, rt)
+	if err != nil {
+		return err
+	}
+	t.tree = tree
+	t.version++
+	rt.version = t.version
+	t.routes[rt.pattern] = rt
+	t.values[rt.pattern] = value
+	if paramsCount := countParams(rt.path); paramsCount > t.maxParams {
+		t.maxParams = paramsCount
+	}
+	return nil
+}
+
+// Get returns the value of the pattern matching the path and the params.
+func (t *PathTrie[T]) Get(path string) (value T, ps Params, found bool) {
+	leaf, psp, _ := t.getLeaf(path)
+	if leaf == nil {
+		return value, nil, false
+	}
+	if psp != nil {
+		ps = *psp
+	}
+	return t.values[leaf.route.pattern], ps, true
+}
+
+// newParams allocates params with the capacity for the patterns.
+func (t *PathTrie[T]) newParams() *Params {
+	ps := make(Params, 0, t.maxParams)
+	return &ps
+}
+
+// LongestPrefix returns the pattern and value of the pattern matching the
+// longest prefix of the path ending at a path segment boundary, for example
+// /api/:version for /api/v1/users if /api/:version/users is not in the trie.
+// The path itself is tried first.
+func (t *PathTrie[T]) LongestPrefix(path string) (pattern string, value T, ps Params, found bool) {
+	for prefix := path; prefix != ""; {
+		if leaf, psp, _ := t.getLeaf(prefix); leaf != nil {
+			if psp != nil {
+				ps = *psp
+			}
+			return leaf.route.pattern, t.values[leaf.route.pattern], ps, true
+		}
+		if strings.HasSuffix(prefix, "/") {
+			prefix = prefix[:len(prefix)-1]
+		} else if i := strings.LastIndexByte(prefix, '/'); i >= 0 {
+			// try the prefix with the trailing slash first
+			prefix = prefix[:i+1]
+		} else {
+			break
+		}
+	}
+	return "", value, nil, false
+}
+
+// getLeaf returns the leaf matching the path, if any.
+func (t *PathTrie[T]) getLeaf(path string) (*node[struct{}], *Params, bool) {
+	if len(t.routes) == 0 {
+		return nil, nil, false
+	}
+	return t.tree.getValue(path, t.newParams, nil)
+}
+
+// Delete removes the pattern from the trie.
+// Returns false if the pattern is not in the trie or invalid, or if the tree
+// could not be rebuilt without the pattern, in which case the trie is not
+// changed.
+//
+// The tree is rebuilt: deleting is linear in the number of patterns.
+func (t *PathTrie[T]) Delete(pattern string) bool {
+	rt, err := newRoute[struct{}](pattern, nil, 0)
+	if err != nil {
+		return false
+	}
+	if _, exists := t.routes[rt.pattern]; !exists {
+		return false
+	}
+	routes := maps.Clone(t.routes)
+	delete(routes, rt.pattern)
+
+	trees, maxParams, err := buildTrees(routes, false)
+	if err != nil {
+		return false
+	}
+	t.routes, t.maxParams = routes, maxParams
+	delete(t.values, rt.pattern)
+	t.tree = new(node[struct{}])
+	if len(trees) != 0 {
+		t.tree = trees[0]
+	}
+	return true
+}
+
+// Walk calls fn with each pattern and value in insertion order.
+// Stops and returns the error if fn returns an error.
+func (t *PathTrie[T]) Walk(fn func(pattern string, value T) error) error {
+	for _, rt := range sortRoutes(t.routes) {
+		if err := fn(rt.pattern, t.values[rt.pattern]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
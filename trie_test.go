@@ -0,0 +1,95 @@
+package pathrouter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestPathTrie(t *testing.T) {
+	trie := NewPathTrie[int]()
+	if _, _, found := trie.Get("/"); found {
+		t.Error("found value in empty trie")
+	}
+	for i, pattern := range []string{"/", "/api/:version", "/api/:version/users/:id", "/files/*filepath", "/exact/{$}"} {
+		if err := trie.Insert(pattern, i); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if err := trie.Insert("/api/:v/other", 9); err == nil {
+		t.Error("expected conflict error")
+	}
+	if err := trie.Insert("/bad/:", 9); err == nil {
+		t.Error("expected invalid pattern error")
+	}
+	if err := trie.Insert("/", 10); err != nil || trie.Len() != 5 {
+		t.Fatalf("expected the value to be replaced, got %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		value  int
+		params Params
+		found  bool
+	}{
+		{"/", 10, nil, true},
+		{"/api/v1", 1, Params{{"version", "v1"}}, true},
+		{"/api/v1/users/42", 2, Params{{"version", "v1"}, {"id", "42"}}, true},
+		{"/files/a/b", 3, Params{{"filepath", "/a/b"}}, true},
+		{"/exact/", 4, nil, true},
+		{"/exact/more", 0, nil, false},
+		{"/api/v1/users", 0, nil, false},
+		{"/nope", 0, nil, false},
+	}
+	for _, test := range tests {
+		value, params, found := trie.Get(test.path)
+		if found != test.found || value != test.value || !reflect.DeepEqual(params, test.params) {
+			t.Errorf("Get %s: expected %v %v %v but got %v %v %v", test.path, test.value, test.params, test.found, value, params, found)
+		}
+	}
+
+	prefixes := []struct {
+		path    string
+		pattern string
+	}{
+		{"/api/v1/users/42", "/api/:version/users/:id"},
+		{"/api/v1/users", "/api/:version"},
+		{"/api/v1/groups/1", "/api/:version"},
+		{"/exact/more", "/exact/{$}"},
+		{"/other", "/"},
+		{"relative", ""},
+	}
+	for _, test := range prefixes {
+		if pattern, _, _, found := trie.LongestPrefix(test.path); pattern != test.pattern || found != (test.pattern != "") {
+			t.Errorf("LongestPrefix %s: expected %q but got %q", test.path, test.pattern, pattern)
+		}
+	}
+
+	if !trie.Delete("/api/:version") || trie.Delete("/api/:version") || trie.Delete("/bad/:") {
+		t.Error("expected the pattern to be deleted once")
+	}
+	if _, _, found := trie.Get("/api/v1"); found {
+		t.Error("found deleted pattern")
+	}
+	if value, _, found := trie.Get("/api/v1/users/42"); !found || value != 2 {
+		t.Error("expected the other patterns to be kept")
+	}
+	if err := trie.Insert("/api/:version/other", 11); err != nil {
+		t.Errorf("expected no conflict after deleting, got %v", err)
+	}
+
+	var patterns []string
+	var values []int
+	errStop := errors.New("stop")
+	err := trie.Walk(func(pattern string, value int) error {
+		patterns, values = append(patterns, pattern), append(values, value)
+		if len(patterns) == 5 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || !reflect.DeepEqual(patterns, []string{"/", "/api/:version/users/:id", "/files/*filepath", "/exact/{$}", "/api/:version/other"}) || !reflect.DeepEqual(values, []int{10, 2, 3, 4, 11}) {
+		t.Errorf("unexpected walk %v %v %v", patterns, values, err)
+	}
+}
@@ -0,0 +1,31 @@
+package pathrouter
+
+import "golang.org/x/text/unicode/norm"
+
+// UnicodeForm is a Unicode normalization form applied to patterns and paths.
+type UnicodeForm int
+
+const (
+	// UnicodeNone does not normalize patterns and paths.
+	UnicodeNone UnicodeForm = iota
+	// UnicodeNFC applies canonical decomposition followed by canonical
+	// composition: visually identical text is represented the same way.
+	UnicodeNFC
+	// UnicodeNFKC applies compatibility decomposition followed by canonical
+	// composition: compatibility characters such as ligatures and full-width
+	// forms are also folded to their canonical equivalents.
+	UnicodeNFKC
+)
+
+// Normalize returns s normalized to the form.
+// Returns s if it is already normalized.
+func (f UnicodeForm) Normalize(s string) string {
+	switch f {
+	case UnicodeNFC:
+		return norm.NFC.String(s)
+	case UnicodeNFKC:
+		return norm.NFKC.String(s)
+	default:
+		return s
+	}
+}
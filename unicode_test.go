@@ -0,0 +1,57 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUnicodeFormNormalize(t *testing.T) {
+	tests := []struct {
+		form   UnicodeForm
+		in     string
+		result string
+	}{
+		{UnicodeNone, "/café", "/café"},
+		{UnicodeNFC, "/café", "/café"},
+		{UnicodeNFC, "/café", "/café"},
+		{UnicodeNFC, "/ﬁle", "/ﬁle"},
+		{UnicodeNFKC, "/ﬁle", "/file"},
+		{UnicodeNFKC, "/café", "/café"},
+	}
+	for _, test := range tests {
+		if result := test.form.Normalize(test.in); result != test.result {
+			t.Errorf("form %v: Normalize(%q) = %q, want %q", test.form, test.in, result, test.result)
+		}
+	}
+}
+
+func TestRouterUnicodeForm(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.UnicodeForm = UnicodeNFC
+	router := NewWithConfig(conf)
+
+	// registered decomposed and composed
+	router.AddHandler("/café/:name", fakeHandler("cafe"))
+	router.AddHandler("/niño", fakeHandler("nino"))
+
+	ctx := context.Background()
+	for _, path := range []string{"/café/x", "/café/x"} {
+		fakeHandlerValue = ""
+		if found, _ := router.Serve(ctx, path, struct{}{}); !found || fakeHandlerValue != "cafe" {
+			t.Errorf("path %q: expected found, got %v %q", path, found, fakeHandlerValue)
+		}
+	}
+	for _, path := range []string{"/niño", "/niño"} {
+		fakeHandlerValue = ""
+		if found, _ := router.Serve(ctx, path, struct{}{}); !found || fakeHandlerValue != "nino" {
+			t.Errorf("path %q: expected found, got %v %q", path, found, fakeHandlerValue)
+		}
+	}
+
+	recv := catchPanic(func() {
+		router.AddHandler("/niño", fakeHandler("dup"))
+	})
+	if recv == nil {
+		t.Error("expected panic registering equivalent pattern")
+	}
+}
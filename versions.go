@@ -0,0 +1,154 @@
+package pathrouter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VersionChange is how a route of a version differs from the shared routes.
+type VersionChange int
+
+const (
+	// VersionOverridden indicates the version replaces the handle of a route.
+	VersionOverridden VersionChange = iota
+	// VersionAdded indicates the route only exists in the version.
+	VersionAdded
+	// VersionRemoved indicates the version does not have the route.
+	VersionRemoved
+)
+
+// String returns the name of the change.
+func (c VersionChange) String() string {
+	switch c {
+	case VersionOverridden:
+		return "overridden"
+	case VersionAdded:
+		return "added"
+	case VersionRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// VersionDiff is a route of a version differing from the shared routes, see
+// AddVersions.
+type VersionDiff struct {
+	// Version is the version prefix.
+	Version string
+	// Pattern is the pattern of the route without the version prefix.
+	Pattern string
+	// Change is how the route differs.
+	Change VersionChange
+}
+
+// AddVersions registers the routes under each of the version prefixes, for
+// example /users/:id as /v1/users/:id and /v2/users/:id.
+//
+// The overrides contain the routes differing per version prefix: a route with
+// the pattern of a shared route replaces it in that version, or removes it if
+// the handle is nil, and other routes are added to that version only. Returns
+// the routes differing from the shared routes in the order of the versions.
+//
+// If any of the routes are invalid or conflict an error is returned and none
+// of the routes are registered.
+func (r *Router[W]) AddVersions(versions []string, routes []RouteDef[W], overrides map[string][]RouteDef[W]) ([]VersionDiff, error) {
+	for version := range overrides {
+		if !slices.Contains(versions, version) {
+			return nil, errors.Errorf("overrides for unknown version '%s'", version)
+		}
+	}
+
+	var diffs []VersionDiff
+	var rts []*route[W]
+	for _, version := range versions {
+		defs, vdiffs, err := r.versionRoutes(version, routes, overrides[version])
+		if err != nil {
+			return nil, errors.Wrapf(err, "version '%s'", version)
+		}
+		diffs = append(diffs, vdiffs...)
+
+		for _, def := range defs {
+			def.Pattern = r.joinPrefix(version, def.Pattern)
+			rt, err := newRouteFromDef(def, r.conf.UnicodeForm, r.conf.Separator)
+			if err != nil {
+				return nil, err
+			}
+			rts = append(rts, rt)
+		}
+	}
+
+	if err := r.addRoutes(rts); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// versionRoutes applies the overrides of a version to the shared routes.
+// Returns the routes of the version and how they differ.
+func (r *Router[W]) versionRoutes(version string, routes, overrides []RouteDef[W]) ([]RouteDef[W], []VersionDiff, error) {
+	// compare the patterns in the normalized form
+	keys := make(map[string]int, len(routes))
+	for i, def := range routes {
+		key, err := r.patternKey(def.Pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys[key] = i
+	}
+
+	defs := append([]RouteDef[W](nil), routes...)
+	removed := make([]bool, len(defs))
+	var diffs []VersionDiff
+	for _, def := range overrides {
+		key, err := r.patternKey(def.Pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+		diff := VersionDiff{Version: version, Pattern: def.Pattern}
+		i, shared := keys[key]
+		switch {
+		case !shared && def.Handle == nil:
+			return nil, nil, errors.Errorf("cannot remove unknown route '%s'", def.Pattern)
+		case !shared:
+			diff.Change = VersionAdded
+			defs = append(defs, def)
+			removed = append(removed, false)
+		case def.Handle == nil:
+			diff.Change = VersionRemoved
+			removed[i] = true
+		default:
+			diff.Change = VersionOverridden
+			defs[i] = def
+		}
+		diffs = append(diffs, diff)
+	}
+
+	out := defs[:0]
+	for i, def := range defs {
+		if !removed[i] {
+			out = append(out, def)
+		}
+	}
+	return out, diffs, nil
+}
+
+// patternKey returns the normalized form of a pattern for comparisons.
+func (r *Router[W]) patternKey(pattern string) (string, error) {
+	rt, err := newRoute[W](r.conf.UnicodeForm.Normalize(pattern), nil, r.conf.Separator)
+	if err != nil {
+		return "", err
+	}
+	return rt.pattern, nil
+}
+
+// joinPrefix joins the prefix and the pattern with the separator.
+func (r *Router[W]) joinPrefix(prefix, pattern string) string {
+	sep := "/"
+	if customSeparator(r.conf.Separator) {
+		sep = string(r.conf.Separator)
+	}
+	return strings.TrimSuffix(prefix, sep) + sep + strings.TrimPrefix(pattern, sep)
+}
@@ -0,0 +1,73 @@
+package pathrouter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouterAddVersions(t *testing.T) {
+	router := New[struct{}]()
+	routes := []RouteDef[struct{}]{
+		{Pattern: "/users/:id", Handle: fakeHandler("users")},
+		{Pattern: "/users/:id/avatar", Handle: fakeHandler("avatar")},
+		{Pattern: "/legacy", Handle: fakeHandler("legacy")},
+	}
+	overrides := map[string][]RouteDef[struct{}]{
+		"/v2/": {
+			{Pattern: "users/{id}", Handle: fakeHandler("users v2")},
+			{Pattern: "/legacy"},
+			{Pattern: "/teams/:id", Handle: fakeHandler("teams")},
+		},
+	}
+	diffs, err := router.AddVersions([]string{"/v1", "/v2/"}, routes, overrides)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	wantDiffs := []VersionDiff{
+		{"/v2/", "users/{id}", VersionOverridden},
+		{"/v2/", "/legacy", VersionRemoved},
+		{"/v2/", "/teams/:id", VersionAdded},
+	}
+	if !reflect.DeepEqual(diffs, wantDiffs) {
+		t.Errorf("expected diffs %v but got %v", wantDiffs, diffs)
+	}
+
+	var patterns []string
+	for _, def := range router.Routes() {
+		patterns = append(patterns, def.Pattern)
+	}
+	wantPatterns := []string{"/v1/users/:id", "/v1/users/:id/avatar", "/v1/legacy", "/v2/users/:id", "/v2/users/:id/avatar", "/v2/teams/:id"}
+	if !reflect.DeepEqual(patterns, wantPatterns) {
+		t.Errorf("expected patterns %v but got %v", wantPatterns, patterns)
+	}
+
+	for path, value := range map[string]string{"/v1/users/1": "users", "/v2/users/1": "users v2", "/v2/teams/1": "teams"} {
+		fakeHandlerValue = ""
+		if res := router.Lookup(path); !res.Found() {
+			t.Errorf("path %s: not found", path)
+		} else if _, _ = res.Handle(nil, path, res.Params, struct{}{}); fakeHandlerValue != value {
+			t.Errorf("path %s: expected handle %q but got %q", path, value, fakeHandlerValue)
+		}
+	}
+	if res := router.Lookup("/v2/legacy"); res.Found() {
+		t.Error("expected removed route not to be registered")
+	}
+
+	// conflicts and invalid overrides register none of the routes
+	for _, test := range []struct {
+		versions  []string
+		overrides map[string][]RouteDef[struct{}]
+	}{
+		{[]string{"/v3", "/v1"}, nil},
+		{[]string{"/v3"}, map[string][]RouteDef[struct{}]{"/v4": nil}},
+		{[]string{"/v3"}, map[string][]RouteDef[struct{}]{"/v3": {{Pattern: "/missing"}}}},
+		{[]string{"/v3"}, map[string][]RouteDef[struct{}]{"/v3": {{Pattern: "/users/:name", Handle: fakeHandler("")}}}},
+	} {
+		if _, err := router.AddVersions(test.versions, routes, test.overrides); err == nil {
+			t.Errorf("versions %v: expected error", test.versions)
+		}
+		if router.HasRoute("/v3/users/:id") {
+			t.Fatalf("versions %v: expected no routes to be registered", test.versions)
+		}
+	}
+}
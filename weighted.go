@@ -0,0 +1,80 @@
+package pathrouter
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"github.com/pkg/errors"
+)
+
+// WeightedHandle is a handle with a selection weight, see AddWeighted.
+type WeightedHandle[W any] struct {
+	// Handle is the handle.
+	Handle Handle[W]
+	// Weight is the relative weight of the handle, for example 95 and 5.
+	Weight int
+}
+
+// Selector selects the index of the handle to call for a request given the
+// weights of the handles. The weights are not negative and sum up to more than
+// zero. For example a Selector can hash a tenant param to pin tenants to one
+// handle.
+type Selector func(ctx context.Context, reqPath string, p Params, weights []int) int
+
+// RandomSelector selects a handle at random in proportion to the weights.
+func RandomSelector(ctx context.Context, reqPath string, p Params, weights []int) int {
+	var total int
+	for _, weight := range weights {
+		total += weight
+	}
+	n := rand.IntN(total)
+	for i, weight := range weights {
+		if n < weight {
+			return i
+		}
+		n -= weight
+	}
+	return len(weights) - 1
+}
+
+// AddWeighted registers handles with weights for the path, for example a
+// canary handle with 5 and the current handle with 95. One of the handles is
+// selected per request with the Selector of the config, which defaults to
+// RandomSelector, and called.
+//
+// Panics if no handles are given, a handle is nil, a weight is negative, all
+// weights are zero, or the path is invalid or conflicts with a registered path.
+func (r *Router[W]) AddWeighted(path string, handles []WeightedHandle[W], opts ...RouteOption) {
+	if len(handles) == 0 {
+		panic("no handles for path '" + path + "'")
+	}
+	selectHandle := r.conf.Selector
+	if selectHandle == nil {
+		selectHandle = RandomSelector
+	}
+
+	var total int
+	weights := make([]int, len(handles))
+	hs := make([]Handle[W], len(handles))
+	for i, wh := range handles {
+		if wh.Handle == nil {
+			panic("nil handle for path '" + path + "'")
+		}
+		if wh.Weight < 0 {
+			panic("negative weight for path '" + path + "'")
+		}
+		weights[i], hs[i] = wh.Weight, wh.Handle
+		total += wh.Weight
+	}
+	if total == 0 {
+		panic("all weights are zero for path '" + path + "'")
+	}
+
+	r.AddHandler(path, func(ctx context.Context, reqPath string, p Params, rw W) (bool, error) {
+		i := selectHandle(ctx, reqPath, p, weights)
+		if i < 0 || i >= len(hs) {
+			return false, errors.Errorf("selector returned invalid handle index %d", i)
+		}
+		return hs[i](ctx, reqPath, p, rw)
+	}, opts...)
+}
@@ -0,0 +1,75 @@
+package pathrouter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRandomSelector(t *testing.T) {
+	weights := []int{0, 3, 1, 0}
+	counts := make([]int, len(weights))
+	for i := 0; i < 4000; i++ {
+		counts[RandomSelector(context.Background(), "/", nil, weights)]++
+	}
+	if counts[0] != 0 || counts[3] != 0 {
+		t.Errorf("selected handle with zero weight: %v", counts)
+	}
+	if counts[1] < 2500 || counts[2] < 500 {
+		t.Errorf("unexpected distribution %v", counts)
+	}
+}
+
+func TestRouterAddWeighted(t *testing.T) {
+	conf := DefaultConfig[struct{}]()
+	conf.Selector = func(ctx context.Context, reqPath string, p Params, weights []int) int {
+		// pin the canary tenant to the second handle
+		if p.ByName("tenant") == "canary" {
+			return 1
+		}
+		if p.ByName("tenant") == "invalid" {
+			return len(weights)
+		}
+		return 0
+	}
+	router := NewWithConfig(conf)
+	router.AddWeighted("/t/:tenant", []WeightedHandle[struct{}]{
+		{Handle: fakeHandler("stable"), Weight: 95},
+		{Handle: fakeHandler("canary"), Weight: 5},
+	})
+
+	ctx := context.Background()
+	for path, value := range map[string]string{"/t/acme": "stable", "/t/canary": "canary"} {
+		fakeHandlerValue = ""
+		if found, err := router.Serve(ctx, path, struct{}{}); !found || err != nil || fakeHandlerValue != value {
+			t.Errorf("path %s: expected %q, got %q found=%v err=%v", path, value, fakeHandlerValue, found, err)
+		}
+	}
+	if _, err := router.Serve(ctx, "/t/invalid", struct{}{}); err == nil {
+		t.Error("expected error for invalid selection")
+	}
+
+	random := New[struct{}]()
+	random.AddWeighted("/r", []WeightedHandle[struct{}]{{Handle: fakeHandler("only"), Weight: 1}, {Handle: fakeHandler("never")}})
+	for i := 0; i < 10; i++ {
+		fakeHandlerValue = ""
+		if _, _ = random.Serve(ctx, "/r", struct{}{}); fakeHandlerValue != "only" {
+			t.Fatalf("expected handle with weight, got %q", fakeHandlerValue)
+		}
+	}
+
+	for _, handles := range [][]WeightedHandle[struct{}]{
+		nil,
+		{{Handle: fakeHandler("")}},
+		{{Handle: fakeHandler(""), Weight: -1}, {Handle: fakeHandler(""), Weight: 2}},
+		{{Weight: 1}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("handles %v: expected panic", handles)
+				}
+			}()
+			router.AddWeighted("/invalid", handles)
+		}()
+	}
+}